@@ -0,0 +1,310 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/load"
+	"github.com/shirou/gopsutil/mem"
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+const (
+	AbortIfFlag       = "abort-if"
+	AbortSamplesFlag  = "abort-samples"
+	AbortIntervalFlag = "abort-interval"
+	ReasonFlag        = "reason"
+	IfFlag            = "if"
+	SamplesFlag       = "samples"
+
+	defaultAbortInterval = "5s"
+	// defaultAbortSamples is the consecutive samples which cross the threshold before aborting
+	defaultAbortSamples = 3
+)
+
+// abortConditionPattern matches the conditions such as cpu>95, mem>=90% or load1>32
+var abortConditionPattern = regexp.MustCompile(`^([a-z0-9]+)(>=|<=|>|<)([0-9]+(?:\.[0-9]+)?)%?$`)
+
+// hostMetrics read the metrics of the host, the percentages are from 0 to 100
+var hostMetrics = map[string]func() (float64, error){
+	"cpu": func() (float64, error) {
+		percents, err := cpu.Percent(time.Second, false)
+		if err != nil {
+			return 0, err
+		}
+		if len(percents) == 0 {
+			return 0, fmt.Errorf("the cpu usage is unavailable")
+		}
+		return percents[0], nil
+	},
+	"mem": func() (float64, error) {
+		memory, err := mem.VirtualMemory()
+		if err != nil {
+			return 0, err
+		}
+		return memory.UsedPercent, nil
+	},
+	"swap": func() (float64, error) {
+		swap, err := mem.SwapMemory()
+		if err != nil {
+			return 0, err
+		}
+		return swap.UsedPercent, nil
+	},
+	"load1": func() (float64, error) {
+		avg, err := load.Avg()
+		if err != nil {
+			return 0, err
+		}
+		return avg.Load1, nil
+	},
+	"load5": func() (float64, error) {
+		avg, err := load.Avg()
+		if err != nil {
+			return 0, err
+		}
+		return avg.Load5, nil
+	},
+	"load15": func() (float64, error) {
+		avg, err := load.Avg()
+		if err != nil {
+			return 0, err
+		}
+		return avg.Load15, nil
+	},
+	"disk": func() (float64, error) {
+		usage, err := disk.Usage("/")
+		if err != nil {
+			return 0, err
+		}
+		return usage.UsedPercent, nil
+	},
+}
+
+// abortCondition is a threshold of a host metric, such as cpu>95
+type abortCondition struct {
+	definition string
+	metric     string
+	operator   string
+	threshold  float64
+}
+
+// parseAbortCondition parses the condition in METRIC OPERATOR THRESHOLD format, the metrics are cpu, mem, swap,
+// disk, load1, load5 and load15
+func parseAbortCondition(definition string) (*abortCondition, error) {
+	matches := abortConditionPattern.FindStringSubmatch(strings.ReplaceAll(definition, " ", ""))
+	if matches == nil {
+		return nil, fmt.Errorf("%s is not in METRIC>THRESHOLD format", definition)
+	}
+	if _, ok := hostMetrics[matches[1]]; !ok {
+		return nil, fmt.Errorf("the %s metric is not supported, only support cpu, mem, swap, disk, load1, load5 and load15",
+			matches[1])
+	}
+	threshold, err := strconv.ParseFloat(matches[3], 64)
+	if err != nil {
+		return nil, err
+	}
+	return &abortCondition{definition: definition, metric: matches[1], operator: matches[2], threshold: threshold}, nil
+}
+
+// parseAbortConditions parses all conditions, the empty definitions return no conditions
+func parseAbortConditions(definitions []string) ([]*abortCondition, error) {
+	conditions := make([]*abortCondition, 0, len(definitions))
+	for _, definition := range definitions {
+		condition, err := parseAbortCondition(definition)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// crossed returns true if the value crosses the threshold
+func (c *abortCondition) crossed(value float64) bool {
+	switch c.operator {
+	case ">":
+		return value > c.threshold
+	case ">=":
+		return value >= c.threshold
+	case "<":
+		return value < c.threshold
+	case "<=":
+		return value <= c.threshold
+	}
+	return false
+}
+
+// abortExperiment destroys the experiment by the normal destroy path and records the Aborted status with the reason
+func abortExperiment(dc *DestroyCommand, model *data.ExperimentModel, reason string) error {
+	executor, expModel, err := dc.getExecutorAndExpModelByRecord(model)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.HandlerExecNotFound, err.Error())
+	}
+	return dc.destroyExperimentWithStatus(model.Uid, executor, expModel, Aborted, reason)
+}
+
+// watchAbortConditions samples the metrics by the interval while the experiment is Success, and aborts the
+// experiment if a condition is crossed by the consecutive samples. The metrics which can not be read are skipped.
+func watchAbortConditions(dc *DestroyCommand, uid string, conditions []*abortCondition, samples int,
+	interval time.Duration, sleep func(time.Duration), readMetric func(metric string) (float64, error),
+) error {
+	ctx := context.WithValue(context.Background(), spec.Uid, uid)
+	crossings := make([]int, len(conditions))
+	for {
+		sleep(interval)
+		model, err := GetDS().QueryExperimentModelByUid(uid)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
+		}
+		if model == nil || model.Status != Success {
+			return nil
+		}
+		for idx, condition := range conditions {
+			value, err := readMetric(condition.metric)
+			if err != nil {
+				log.Warnf(ctx, "read the %s metric failed, %v", condition.metric, err)
+				crossings[idx] = 0
+				continue
+			}
+			if !condition.crossed(value) {
+				crossings[idx] = 0
+				continue
+			}
+			if crossings[idx]++; crossings[idx] < samples {
+				continue
+			}
+			reason := fmt.Sprintf("the abort condition %s is crossed by %d samples, the last %s is %.2f",
+				condition.definition, samples, condition.metric, value)
+			log.Warnf(ctx, "%s, abort the experiment", reason)
+			return abortExperiment(dc, model, reason)
+		}
+	}
+}
+
+// readHostMetric reads the metric of the host
+func readHostMetric(metric string) (float64, error) {
+	read, ok := hostMetrics[metric]
+	if !ok {
+		return 0, fmt.Errorf("the %s metric is not supported", metric)
+	}
+	return read()
+}
+
+// startAbortWatcher starts a background process which aborts the experiment if any condition is crossed
+func startAbortWatcher(launcher processLauncher, uid string, conditions []string, samples int, interval string) error {
+	args := []string{"abort", uid}
+	for _, condition := range conditions {
+		args = append(args, "--"+IfFlag, condition)
+	}
+	args = append(args, "--"+SamplesFlag, strconv.Itoa(samples), "--"+IntervalFlag, interval)
+	return launchInBackground(launcher, nil, args...)
+}
+
+// AbortCommand aborts the experiment now or when the host metrics cross the conditions
+type AbortCommand struct {
+	baseCommand
+	reason     string
+	conditions []string
+	samples    int
+	interval   string
+}
+
+func (ac *AbortCommand) Init() {
+	ac.command = &cobra.Command{
+		Use:   "abort UID",
+		Short: "Abort the experiment",
+		Long: "Abort the experiment, it is destroyed and marked as Aborted with the reason. With --if, the host " +
+			"metrics are sampled by the interval while the experiment is running, and the experiment is aborted if " +
+			"any condition is crossed by the consecutive samples. The watcher is started by blade create --abort-if " +
+			"automatically.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ac.runAbort(cmd, args[0])
+		},
+		Example: abortExample(),
+	}
+	ac.command.Flags().StringVar(&ac.reason, ReasonFlag, "aborted manually", "The reason of aborting the experiment")
+	ac.command.Flags().StringArrayVar(&ac.conditions, IfFlag, nil, "The condition of the host metric, such as cpu>95, mem>90 or load1>32, can be specified multiple times")
+	ac.command.Flags().IntVar(&ac.samples, SamplesFlag, defaultAbortSamples, "The consecutive samples which cross the condition before aborting")
+	ac.command.Flags().StringVar(&ac.interval, IntervalFlag, defaultAbortInterval, "The interval of sampling the host metrics")
+}
+
+func (ac *AbortCommand) runAbort(cmd *cobra.Command, uid string) error {
+	model, err := GetDS().QueryExperimentModelByUid(uid)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
+	}
+	if model == nil {
+		return spec.ResponseFailWithFlags(spec.DataNotFound, uid)
+	}
+	destroyCommand := &DestroyCommand{}
+	destroyCommand.Init()
+	if len(ac.conditions) > 0 {
+		conditions, err := parseAbortConditions(ac.conditions)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, IfFlag, ac.conditions, err)
+		}
+		interval, response := checkAbortSampling(IntervalFlag, ac.interval, SamplesFlag, ac.samples)
+		if response != nil {
+			return response
+		}
+		return watchAbortConditions(destroyCommand, uid, conditions, ac.samples, interval, time.Sleep, readHostMetric)
+	}
+	if model.Status != Created && model.Status != Success {
+		return spec.ResponseFailWithFlags(spec.ParameterInvalid, "uid", uid,
+			fmt.Sprintf("the experiment is %s", model.Status))
+	}
+	if err := abortExperiment(destroyCommand, model, ac.reason); err != nil {
+		return err
+	}
+	cmd.Println(spec.ReturnSuccess(uid).Print())
+	return nil
+}
+
+// checkAbortSampling checks the interval and samples of the abort conditions, the flag names are used in the response
+func checkAbortSampling(intervalFlag, value, samplesFlag string, samples int) (time.Duration, *spec.Response) {
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 0, spec.ResponseFailWithFlags(spec.ParameterIllegal, intervalFlag, value, "must be a positive duration")
+	}
+	if samples < 1 {
+		return 0, spec.ResponseFailWithFlags(spec.ParameterIllegal, samplesFlag, samples, "must be positive")
+	}
+	return interval, nil
+}
+
+func abortExample() string {
+	return `# Abort the experiment now
+blade abort 47cc0744f1bb --reason "the service is unavailable"
+
+# Create the experiment which is aborted if the cpu usage is over 95% or the load1 is over 32 by 3 consecutive samples
+blade create cpu load --cpu-percent 80 --abort-if "cpu>95" --abort-if "load1>32" --abort-samples 3 --abort-interval 5s`
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+func Test_parseAbortCondition(t *testing.T) {
+	tests := []struct {
+		definition        string
+		expectedMetric    string
+		expectedOperator  string
+		expectedThreshold float64
+		expectedErr       bool
+	}{
+		{"cpu>95", "cpu", ">", 95, false},
+		{"mem >= 90%", "mem", ">=", 90, false},
+		{"load1>32.5", "load1", ">", 32.5, false},
+		{"disk<10", "disk", "<", 10, false},
+		{"net>10", "", "", 0, true},
+		{"cpu=95", "", "", 0, true},
+		{"cpu>", "", "", 0, true},
+	}
+	for _, tt := range tests {
+		condition, err := parseAbortCondition(tt.definition)
+		if tt.expectedErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got %+v", tt.definition, condition)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.definition, err)
+			continue
+		}
+		if condition.metric != tt.expectedMetric || condition.operator != tt.expectedOperator ||
+			condition.threshold != tt.expectedThreshold {
+			t.Errorf("%s: unexpected condition %+v", tt.definition, condition)
+		}
+	}
+}
+
+func Test_watchAbortConditions(t *testing.T) {
+	tests := []struct {
+		name              string
+		values            []float64
+		expectedIntervals int
+		expectedStatus    string
+	}{
+		// the condition is crossed by 3 consecutive samples
+		{"aborted", []float64{96, 97, 50, 96, 98, 99}, 6, Aborted},
+		{"unreadable", nil, 4, Destroyed},
+		{"steady", []float64{10, 96, 96, 10, 96}, 6, Destroyed},
+	}
+	for _, tt := range tests {
+		src := data.NewMemorySource()
+		if err := src.InsertExperimentModel(&data.ExperimentModel{
+			Uid: "abort", Command: "cpu", SubCommand: "fullload", Status: Success,
+		}); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+		SetDS(src)
+		dc := &DestroyCommand{}
+		dc.Init()
+		dc.executors[createExecutorKey("cpu", "", "fullload")] = &probeTestExecutor{}
+		// the experiment is destroyed by others after the values are sampled
+		intervals := 0
+		sleep := func(time.Duration) {
+			if intervals++; intervals > len(tt.values) && intervals > 3 {
+				src.UpdateExperimentModelByUid("abort", Destroyed, "")
+			}
+		}
+		readMetric := func(metric string) (float64, error) {
+			if intervals > len(tt.values) {
+				return 0, fmt.Errorf("the %s metric is unavailable", metric)
+			}
+			return tt.values[intervals-1], nil
+		}
+		conditions, _ := parseAbortConditions([]string{"cpu>95"})
+		if err := watchAbortConditions(dc, "abort", conditions, 3, time.Second, sleep, readMetric); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		model, _ := src.QueryExperimentModelByUid("abort")
+		if model.Status != tt.expectedStatus || intervals != tt.expectedIntervals {
+			t.Errorf("%s: unexpected experiment %+v, intervals %d", tt.name, model, intervals)
+		}
+		if tt.expectedStatus == Aborted && !strings.Contains(model.Error, "cpu>95") {
+			t.Errorf("%s: unexpected reason %s", tt.name, model.Error)
+		}
+	}
+	SetDS(&MockSource{})
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// AuditCommand inspects the audit journal of the status transitions
+type AuditCommand struct {
+	baseCommand
+}
+
+func (ac *AuditCommand) Init() {
+	ac.command = &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the audit journal",
+		Long: "Inspect the append-only audit journal, which records every status transition of the experiments " +
+			"and preparations with the OS user, the command line and the source of the change",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return spec.ResponseFailWithFlags(spec.CommandIllegal, "less verify command")
+		},
+		Example: auditExample(),
+	}
+}
+
+func auditExample() string {
+	return `blade audit verify`
+}
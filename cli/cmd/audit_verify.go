@@ -0,0 +1,97 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+// AuditVerifyCommand checks the hash chain of the audit journal
+type AuditVerifyCommand struct {
+	baseCommand
+	// head is the head hash returned by the previous verifying, which is kept outside the data file
+	head string
+}
+
+// auditVerifyResult contains the count of the entries and the hash of the last one,
+// the BrokenSeq is the seq of the first broken entry
+type auditVerifyResult struct {
+	Entries   int    `json:"entries"`
+	Head      string `json:"head,omitempty"`
+	BrokenSeq int64  `json:"brokenSeq,omitempty"`
+}
+
+func (avc *AuditVerifyCommand) Init() {
+	avc.command = &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the hash chain of the audit journal",
+		Long: "Verify the hash chain of the audit journal, the modified, inserted or deleted entries break the chain. " +
+			"The hash is not keyed, so keep the head returned by the verifying outside the data file and verify " +
+			"with it next time, then the entries deleted from the end or a recomputed chain are also detected",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return avc.runVerify(cmd)
+		},
+		Example: auditVerifyExample(),
+	}
+	avc.command.Flags().StringVar(&avc.head, "head", "", "the head returned by the previous verifying, which the chain must contain")
+}
+
+func (avc *AuditVerifyCommand) runVerify(cmd *cobra.Command) error {
+	result, err := verifyAuditJournal(GetDS(), avc.head)
+	if err != nil {
+		return err
+	}
+	cmd.Println(spec.ReturnSuccess(result).Print())
+	return nil
+}
+
+// verifyAuditJournal returns the failed response with the result if the chain is broken,
+// or it does not contain the non-empty head
+func verifyAuditJournal(journal data.AuditJournal, head string) (*auditVerifyResult, error) {
+	entries, err := journal.QueryAuditEntries()
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.DbQueryFailed, "audit", err)
+	}
+	result := &auditVerifyResult{Entries: len(entries)}
+	err = data.VerifyAuditEntries(entries)
+	if err == nil && head != "" {
+		err = data.VerifyAuditHead(entries, head)
+	}
+	if err != nil {
+		if chainErr, ok := err.(*data.AuditChainError); ok {
+			result.BrokenSeq = chainErr.Seq
+		}
+		response := spec.ResponseFailWithFlags(spec.DatabaseError, "verify", err)
+		response.Result = result
+		return result, response
+	}
+	if len(entries) > 0 {
+		result.Head = entries[len(entries)-1].Hash
+	}
+	return result, nil
+}
+
+func auditVerifyExample() string {
+	return `blade audit verify
+
+# Verify the chain contains the head returned by the previous verifying
+blade audit verify --head 5f0c3a7e9b2d...`
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+// tamperedJournal returns the entries of the journal modified by the tamper function
+type tamperedJournal struct {
+	data.AuditJournal
+	tamper func(entries []*data.AuditEntry)
+}
+
+func (j *tamperedJournal) QueryAuditEntries() ([]*data.AuditEntry, error) {
+	entries, err := j.AuditJournal.QueryAuditEntries()
+	if err == nil {
+		j.tamper(entries)
+	}
+	return entries, err
+}
+
+func Test_verifyAuditJournal(t *testing.T) {
+	src := data.NewAuditedSource(data.NewMemorySource())
+	for _, uid := range []string{"uid1", "uid2", "uid3"} {
+		if err := src.InsertExperimentModel(&data.ExperimentModel{Uid: uid, Command: "cpu", Status: "Created"}); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+	}
+
+	result, err := verifyAuditJournal(src, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Entries != 3 || result.Head == "" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	head := result.Head
+
+	journal := &tamperedJournal{AuditJournal: src, tamper: func(entries []*data.AuditEntry) {
+		entries[1].User = "nobody"
+	}}
+	result, err = verifyAuditJournal(journal, "")
+	response, ok := err.(*spec.Response)
+	if !ok || response.Code != spec.DatabaseError.Code {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.BrokenSeq != 2 {
+		t.Errorf("unexpected broken seq: %d", result.BrokenSeq)
+	}
+
+	if err := src.UpdateExperimentModelByUid("uid3", "Success", ""); err != nil {
+		t.Fatalf("update experiment failed, %v", err)
+	}
+	if _, err := verifyAuditJournal(src, head); err != nil {
+		t.Errorf("unexpected error with the head: %v", err)
+	}
+	// the chain truncated before the head is valid, but it does not contain the head
+	truncated := &truncatedJournal{AuditJournal: src, count: 2}
+	if _, err := verifyAuditJournal(truncated, ""); err != nil {
+		t.Errorf("unexpected error of the truncated chain: %v", err)
+	}
+	result, err = verifyAuditJournal(truncated, head)
+	if response, ok := err.(*spec.Response); !ok || response.Code != spec.DatabaseError.Code {
+		t.Fatalf("unexpected error of the truncated chain with the head: %v", err)
+	}
+	if result.BrokenSeq != 3 {
+		t.Errorf("unexpected broken seq: %d", result.BrokenSeq)
+	}
+}
+
+// truncatedJournal returns the first entries of the journal
+type truncatedJournal struct {
+	data.AuditJournal
+	count int
+}
+
+func (j *truncatedJournal) QueryAuditEntries() ([]*data.AuditEntry, error) {
+	entries, err := j.AuditJournal.QueryAuditEntries()
+	if err == nil && len(entries) > j.count {
+		entries = entries[:j.count]
+	}
+	return entries, err
+}
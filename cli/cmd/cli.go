@@ -46,6 +46,8 @@ func NewCli() *Cli {
 func (cli *Cli) setFlags() {
 	flags := cli.rootCmd.PersistentFlags()
 	flags.BoolVarP(&util.Debug, "debug", "d", false, "Set client to DEBUG mode")
+	flags.StringVarP(&outputFormat, OutputFlag, "o", "", "Output format of the status, query, server status and version commands, "+
+		"one of table|wide|json|yaml|jsonpath=TEMPLATE|go-template=TEMPLATE")
 	// flags.StringVarP(&util.LogLevel, "log-level", "l", "info", "level of logging wanted. 1=DEBUG, 0=INFO, -1=WARN, A higher verbosity level means a log message is less important.")
 }
 
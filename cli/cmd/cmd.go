@@ -44,9 +44,44 @@ func CmdInit() *baseCommand {
 	destroyCommand := &DestroyCommand{}
 	baseCmd.AddCommand(destroyCommand)
 
+	// add run command
+	baseCmd.AddCommand(&RunCommand{})
+
 	// add status command
 	baseCmd.AddCommand(&StatusCommand{})
 
+	// add reconcile command
+	baseCmd.AddCommand(&ReconcileCommand{})
+
+	// add prune command
+	baseCmd.AddCommand(&PruneCommand{})
+
+	// add history command
+	historyCommand := &HistoryCommand{}
+	baseCmd.AddCommand(historyCommand)
+	historyCommand.AddCommand(&HistoryExportCommand{})
+	historyCommand.AddCommand(&HistoryImportCommand{})
+
+	// add audit command
+	auditCommand := &AuditCommand{}
+	baseCmd.AddCommand(auditCommand)
+	auditCommand.AddCommand(&AuditVerifyCommand{})
+
+	// add event command
+	eventCommand := &EventCommand{}
+	baseCmd.AddCommand(eventCommand)
+	eventCommand.AddCommand(&EventListCommand{})
+	eventCommand.AddCommand(&EventDeliverCommand{})
+	eventCommand.AddCommand(&EventRedeliverCommand{})
+
+	// add doctor command
+	baseCmd.AddCommand(&DoctorCommand{})
+
+	// add panic command
+	baseCmd.AddCommand(&PanicCommand{})
+	baseCmd.AddCommand(&ProbeCommand{})
+	baseCmd.AddCommand(&AbortCommand{})
+
 	// add query command
 	queryCommand := &QueryCommand{}
 	baseCmd.AddCommand(queryCommand)
@@ -55,7 +90,17 @@ func CmdInit() *baseCommand {
 	queryCommand.AddCommand(&QueryJvmCommand{})
 	queryCommand.AddCommand(&QueryK8sCommand{})
 
-	// UPDATE 2023-12-30 Disable server command mode.
+	// add server command
+	serverCommand := &ServerCommand{}
+	baseCmd.AddCommand(serverCommand)
+	serverCommand.AddCommand(&StartServerCommand{})
+	serverCommand.AddCommand(&StopServerCommand{})
+	serverCommand.AddCommand(&StatusServerCommand{})
+
+	// add db command
+	dbCommand := &DBCommand{}
+	baseCmd.AddCommand(dbCommand)
+	dbCommand.AddCommand(&DBMigrateCommand{})
 
 	// add check command
 	checkCommand := &CheckCommand{}
@@ -62,6 +62,9 @@ var ds data.SourceI
 // GetDS returns dataSource
 func GetDS() data.SourceI {
 	if ds == nil {
+		if router := newEventRouter(); router != nil {
+			data.SetEventRouter(router)
+		}
 		ds = data.GetSource()
 	}
 	return ds
@@ -73,8 +76,21 @@ func SetDS(source data.SourceI) {
 }
 
 // recordExpModel
-func (bc *baseCommand) recordExpModel(commandPath string, expModel *spec.ExpModel) (commandModel *data.ExperimentModel,
-	response *spec.Response,
+func (bc *baseCommand) recordExpModel(commandPath, groupUid string, labels map[string]string, expModel *spec.ExpModel) (
+	commandModel *data.ExperimentModel, response *spec.Response,
+) {
+	command, subCommand, err := parseCommandPath(commandPath)
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.CommandIllegal, err)
+	}
+	return bc.recordExpModelWithCommand(command, subCommand, groupUid, labels, expModel)
+}
+
+// recordExpModelWithCommand inserts the experiment record with the command, sub command, group uid and labels
+func (bc *baseCommand) recordExpModelWithCommand(command, subCommand, groupUid string, labels map[string]string,
+	expModel *spec.ExpModel,
+) (
+	commandModel *data.ExperimentModel, response *spec.Response,
 ) {
 	uid := expModel.ActionFlags[UidFlag]
 	var err error
@@ -88,11 +104,13 @@ func (bc *baseCommand) recordExpModel(commandPath string, expModel *spec.ExpMode
 	flagsInline := spec.ConvertExpMatchersToString(expModel, func() map[string]spec.Empty {
 		return make(map[string]spec.Empty)
 	})
-	time := time.Now().Format(time.RFC3339Nano)
-	command, subCommand, err := parseCommandPath(commandPath)
-	if err != nil {
-		return nil, spec.ResponseFailWithFlags(spec.CommandIllegal, err)
+	flags := make(map[string]string)
+	for name, value := range expModel.ActionFlags {
+		if value != "" {
+			flags[name] = value
+		}
 	}
+	time := time.Now().Format(time.RFC3339Nano)
 	commandModel = &data.ExperimentModel{
 		Uid:        uid,
 		Command:    command,
@@ -102,6 +120,9 @@ func (bc *baseCommand) recordExpModel(commandPath string, expModel *spec.ExpMode
 		Error:      "",
 		CreateTime: time,
 		UpdateTime: time,
+		GroupUid:   groupUid,
+		Labels:     labels,
+		Flags:      flags,
 	}
 	err = GetDS().InsertExperimentModel(commandModel)
 	if err != nil {
@@ -110,6 +131,28 @@ func (bc *baseCommand) recordExpModel(commandPath string, expModel *spec.ExpMode
 	return commandModel, spec.ReturnSuccess(uid)
 }
 
+// parseLabels converts the k=v label values to map
+func parseLabels(values []string) (map[string]string, error) {
+	return parseKeyValues("label", values)
+}
+
+// parseKeyValues converts the k=v values of the flag to map
+func parseKeyValues(name string, values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, found := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("the %s must be key=value format, but got %s", name, value)
+		}
+		result[key] = strings.TrimSpace(val)
+	}
+	return result, nil
+}
+
 func parseCommandPath(commandPath string) (string, string, error) {
 	// chaosbd create docker cpu fullload
 	cmds := strings.SplitN(commandPath, " ", 4)
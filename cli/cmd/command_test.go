@@ -17,8 +17,10 @@
 package cmd
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -46,6 +48,12 @@ func Test_baseCommand_recordExpModel(t *testing.T) {
 	delayCommand.PersistentFlags().StringVar(&inf, "interface", "eth0", "")
 	delayCommand.PersistentFlags().SortFlags = true
 	delayCommand.ParseFlags([]string{})
+	// the output format of the root command is not recorded
+	lossCommand := &cobra.Command{Use: "loss"}
+	var output string
+	lossCommand.PersistentFlags().StringVar(&inf, "interface", "eth0", "")
+	lossCommand.PersistentFlags().StringVarP(&output, OutputFlag, "o", "", "")
+	lossCommand.ParseFlags([]string{"-o", "json"})
 
 	tests := []struct {
 		input  input
@@ -69,9 +77,18 @@ func Test_baseCommand_recordExpModel(t *testing.T) {
 				Status:     Created,
 			}, false},
 		},
+		{
+			input{"blade create network loss", lossCommand, "network", "", "loss"},
+			expect{&data.ExperimentModel{
+				Command:    "network",
+				SubCommand: "loss",
+				Flag:       " --interface=eth0",
+				Status:     Created,
+			}, false},
+		},
 	}
 	for _, tt := range tests {
-		got, err := bc.recordExpModel(tt.input.commandPath,
+		got, err := bc.recordExpModel(tt.input.commandPath, "", nil,
 			createExpModel(tt.input.target, tt.input.scope, tt.input.action, tt.input.command))
 		if !err.Success != tt.expect.err {
 			t.Errorf("unexpected result: %t, expected: %t", err != nil, tt.expect.err)
@@ -160,45 +177,89 @@ func Test_parseCommandPath(t *testing.T) {
 	}
 }
 
+func Test_parseLabels(t *testing.T) {
+	tests := []struct {
+		input  []string
+		expect map[string]string
+		err    bool
+	}{
+		{nil, nil, false},
+		{[]string{"team=sre", " env = staging "}, map[string]string{"team": "sre", "env": "staging"}, false},
+		{[]string{"empty="}, map[string]string{"empty": ""}, false},
+		{[]string{"team"}, nil, true},
+		{[]string{"=sre"}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseLabels(tt.input)
+		if (err != nil) != tt.err {
+			t.Errorf("unexpected error of %v: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("unexpected result: %v, expected: %v", got, tt.expect)
+		}
+	}
+}
+
 type MockSource struct{}
 
-func (*MockSource) CheckAndInitExperimentTable() {
+func (*MockSource) InsertExperimentModel(model *data.ExperimentModel) error {
+	return nil
 }
 
-func (*MockSource) ExperimentTableExists() (bool, error) {
-	return true, nil
+func (*MockSource) UpdateExperimentModelByUid(uid, status, errMsg string) error {
+	return nil
 }
 
-func (*MockSource) InitExperimentTable() error {
-	return nil
+func (*MockSource) QueryExperimentModelsByFlags(target, action, status string, flags map[string]string, limit string,
+	asc bool,
+) ([]*data.ExperimentModel, error) {
+	return make([]*data.ExperimentModel, 0), nil
 }
 
-func (*MockSource) InsertExperimentModel(model *data.ExperimentModel) error {
+func (*MockSource) PruneExperimentModels(before time.Time, statuses []string) ([]string, error) {
+	return make([]string, 0), nil
+}
+
+func (*MockSource) PrunePreparationRecords(before time.Time, statuses []string) ([]string, error) {
+	return make([]string, 0), nil
+}
+
+func (*MockSource) AppendAuditEntry(entry *data.AuditEntry) error {
 	return nil
 }
 
-func (*MockSource) UpdateExperimentModelByUid(uid, status, errMsg string) error {
+func (*MockSource) QueryAuditEntries() ([]*data.AuditEntry, error) {
+	return make([]*data.AuditEntry, 0), nil
+}
+
+func (*MockSource) InsertEventDeliveries(deliveries []*data.EventDelivery) error {
 	return nil
 }
 
-// return nil for generating a new uid
-func (*MockSource) QueryExperimentModelByUid(uid string) (*data.ExperimentModel, error) {
-	return nil, nil
+func (*MockSource) QueryEventDeliveries(status string, dueBefore int64) ([]*data.EventDelivery, error) {
+	return make([]*data.EventDelivery, 0), nil
 }
 
-func (*MockSource) QueryExperimentModels(target, action, flag, status, limit string, asc bool) ([]*data.ExperimentModel, error) {
-	return make([]*data.ExperimentModel, 0), nil
+func (*MockSource) ClaimEventDelivery(id, nextAttempt, lease int64) (bool, error) {
+	return false, nil
 }
 
-func (*MockSource) CheckAndInitPreTable() {
+func (*MockSource) UpdateEventDelivery(id int64, status string, attempts int, nextAttempt int64, errMsg string) error {
+	return nil
 }
 
-func (*MockSource) InitPreparationTable() error {
+func (*MockSource) UpdateExperimentStatusByUid(uid string, expected []string, status, errMsg string) error {
 	return nil
 }
 
-func (*MockSource) PreparationTableExists() (bool, error) {
-	return true, nil
+// return nil for generating a new uid
+func (*MockSource) QueryExperimentModelByUid(uid string) (*data.ExperimentModel, error) {
+	return nil, nil
+}
+
+func (*MockSource) QueryExperimentModels(target, action, flag, status, limit string, asc bool) ([]*data.ExperimentModel, error) {
+	return make([]*data.ExperimentModel, 0), nil
 }
 
 func (*MockSource) InsertPreparationRecord(record *data.PreparationRecord) error {
@@ -235,6 +296,49 @@ func (*MockSource) QueryExperimentModelsByCommand(command, subCommand string, fl
 	return make([]*data.ExperimentModel, 0), nil
 }
 
+func (*MockSource) QueryExperimentModelsByGroup(groupUid string) ([]*data.ExperimentModel, error) {
+	return make([]*data.ExperimentModel, 0), nil
+}
+
 func (*MockSource) DeleteExperimentModelByUid(uid string) error {
 	return nil
 }
+
+func (*MockSource) InsertExperimentDeadline(uid string, deadline time.Time) error {
+	return nil
+}
+
+func (*MockSource) UpdateExperimentProbesByUid(uid string, probes []*data.Probe) error {
+	return nil
+}
+
+func (*MockSource) DeleteExperimentDeadline(uid string) error {
+	return nil
+}
+
+func (*MockSource) QueryExperimentDeadline(uid string) (*data.ExperimentDeadline, error) {
+	return nil, nil
+}
+
+func (*MockSource) DeferExperimentDeadline(uid string, nextRetry time.Time, errMsg string) error {
+	return nil
+}
+
+func (*MockSource) QueryOverdueExperimentModels(deadline time.Time) ([]*data.ExperimentModel, error) {
+	return make([]*data.ExperimentModel, 0), nil
+}
+
+// launcherFunc adapts the function to the processLauncher
+type launcherFunc func(env []string, args ...string) (func() error, error)
+
+func (f launcherFunc) launch(env []string, args ...string) (func() error, error) {
+	return f(env, args...)
+}
+
+// recordLaunches returns the launcher which records the args of the launched processes, the processes exit at once
+func recordLaunches(launches *[]string) processLauncher {
+	return launcherFunc(func(env []string, args ...string) (func() error, error) {
+		*launches = append(*launches, strings.Join(args, " "))
+		return func() error { return nil }, nil
+	})
+}
@@ -20,8 +20,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
-	"path"
 	"strconv"
 	"time"
 
@@ -46,6 +44,21 @@ type CreateCommand struct {
 	// The installation result report is triggered only when the async value is true and the value is not empty.
 	endpoint string
 	nohup    bool // used to internal async create, no need to config
+	// group is the group uid of the experiment, such as a game day name
+	group string
+	// labels are the k=v labels of the experiment
+	labels []string
+	// dryRun validates the experiment and shows what would be executed without executing it
+	dryRun bool
+	// probes are the steady-state probes evaluated before, during and after the experiment
+	probes        []string
+	probeInterval string
+	// abortIf are the host metric conditions which abort the experiment
+	abortIf       []string
+	abortSamples  int
+	abortInterval string
+	// launcher starts the nohup creating, the watchers and the timeout of the experiment in the background
+	launcher processLauncher
 }
 
 const (
@@ -53,6 +66,8 @@ const (
 	AsyncFlag    = "async"
 	EndpointFlag = "endpoint"
 	NohupFlag    = "nohup"
+	GroupFlag    = "group"
+	LabelFlag    = "label"
 )
 
 var uid string
@@ -70,7 +85,16 @@ func (cc *CreateCommand) Init() {
 	flags.BoolVarP(&cc.async, AsyncFlag, "a", false, "whether to create asynchronously, default is false")
 	flags.StringVarP(&cc.endpoint, EndpointFlag, "e", "", "the create result reporting address. It takes effect only when the async value is true and the value is not empty")
 	flags.BoolVarP(&cc.nohup, NohupFlag, "n", false, "used to internal async create, no need to config")
+	flags.StringVar(&cc.group, GroupFlag, "", "the group of the experiment, the experiments of a group can be queried and destroyed together")
+	flags.StringArrayVar(&cc.labels, LabelFlag, nil, "the label of the experiment in key=value format, can be specified multiple times")
+	flags.StringArrayVar(&cc.probes, ProbeFlag, nil, "the steady-state probe, such as \"http://svc/health expect=200 p99<300ms\", \"tcp://host:port\" or \"exec:COMMAND expect=0\", can be specified multiple times")
+	flags.StringVar(&cc.probeInterval, ProbeIntervalFlag, defaultProbeInterval, "the interval of evaluating the probes during the experiment")
+	flags.StringArrayVar(&cc.abortIf, AbortIfFlag, nil, "the host metric condition which aborts the experiment, such as cpu>95, mem>90 or load1>32, can be specified multiple times")
+	flags.IntVar(&cc.abortSamples, AbortSamplesFlag, defaultAbortSamples, "the consecutive samples which cross the abort condition before aborting")
+	flags.StringVar(&cc.abortInterval, AbortIntervalFlag, defaultAbortInterval, "the interval of sampling the host metrics for the abort conditions")
+	flags.BoolVar(&cc.dryRun, DryRunFlag, false, "validate the experiment and show what would be executed, without executing it or writing the record")
 
+	cc.launcher = nohupLauncher{}
 	cc.baseExpCommandService = newBaseExpCommandService(cc)
 }
 
@@ -116,13 +140,49 @@ func (cc *CreateCommand) actionRunEFunc(target, scope string, actionCommand *act
 				}
 			}
 		}
+		// the group and labels are recorded separately, not passed to the executor
+		delete(expModel.ActionFlags, GroupFlag)
+		delete(expModel.ActionFlags, LabelFlag)
+		delete(expModel.ActionFlags, DryRunFlag)
+		delete(expModel.ActionFlags, ProbeFlag)
+		delete(expModel.ActionFlags, ProbeIntervalFlag)
+		delete(expModel.ActionFlags, AbortIfFlag)
+		delete(expModel.ActionFlags, AbortSamplesFlag)
+		delete(expModel.ActionFlags, AbortIntervalFlag)
+		if response := checkExperimentPolicy(expModel, actionCommandSpec); response != nil {
+			return response
+		}
+		labels, err := parseLabels(cc.labels)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, LabelFlag, cc.labels, err)
+		}
+		probes, err := newProbeRecords(cc.probes)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, ProbeFlag, cc.probes, err)
+		}
+		if interval, err := time.ParseDuration(cc.probeInterval); err != nil || interval <= 0 {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, ProbeIntervalFlag, cc.probeInterval, "must be a positive duration")
+		}
+		if _, err := parseAbortConditions(cc.abortIf); err != nil {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, AbortIfFlag, cc.abortIf, err)
+		}
+		if _, response := checkAbortSampling(AbortIntervalFlag, cc.abortInterval, AbortSamplesFlag, cc.abortSamples); response != nil {
+			return response
+		}
+		if cc.dryRun {
+			return cc.runDryRun(cmd, expModel, actionCommandSpec.Executor())
+		}
+		// the dry run executes nothing, so it is allowed while the experiments are locked
+		if response := checkPanicLock(); response != nil {
+			return response
+		}
 		nohup := expModel.ActionFlags[NohupFlag] == "true"
 		var model *data.ExperimentModel
 		var resp *spec.Response
-		var err error
 		ctx := context.Background()
 
 		if nohup {
+			data.SetAuditSource(data.AuditSourceAsync)
 			uid := expModel.ActionFlags[UidFlag]
 			if uid == "" {
 				ctx := context.Background()
@@ -131,56 +191,76 @@ func (cc *CreateCommand) actionRunEFunc(target, scope string, actionCommand *act
 			} else {
 				ctx = context.WithValue(context.Background(), spec.Uid, uid)
 				model, err = GetDS().QueryExperimentModelByUid(uid)
-				if err == nil {
-					delete(expModel.ActionFlags, NohupFlag)
+				if err != nil {
+					return spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
+				}
+				// the record is inserted by the async creating before starting the nohup process
+				if model == nil {
+					return spec.ResponseFailWithFlags(spec.DataNotFound, uid)
 				}
+				delete(expModel.ActionFlags, NohupFlag)
+				probes = model.Probes
 			}
 		} else {
+			// the fault is not injected if the system is not in the steady state
+			if failed := evaluateProbes(ctx, probes, probeBefore); failed != nil {
+				return spec.ResponseFailWithFlags(SteadyStateViolated, probeBefore+" injecting", failed.Definition,
+					failed.Before.Message)
+			}
 			// update status
-			model, resp = actionCommand.recordExpModel(cmd.CommandPath(), expModel)
-		}
-		if resp != nil && !resp.Success {
-			return resp
+			model, resp = actionCommand.recordExpModel(cmd.CommandPath(), cc.group, labels, expModel)
+			if !resp.Success {
+				return resp
+			}
+			model.Uid = resp.Result.(string)
+			if len(probes) > 0 {
+				if err := GetDS().UpdateExperimentProbesByUid(model.Uid, probes); err != nil {
+					return recordFailedResponse(model.Uid, err)
+				}
+			}
 		}
-		model.Uid = resp.Result.(string)
 		// is async ?
 		async := expModel.ActionFlags[AsyncFlag] == "true"
 		endpoint := expModel.ActionFlags[EndpointFlag]
 
 		if async {
-			var args string
-			if scope == "host" {
-				args = fmt.Sprintf("create %s %s --uid %s --nohup=true", target, actionCommand.Name(), model.Uid)
-			} else if scope == "docker" || scope == "cri" {
-				args = fmt.Sprintf("create %s %s %s --uid %s --nohup=true", scope, target, actionCommand.Name(), model.Uid)
-			} else {
-				args = fmt.Sprintf("create k8s %s-%s %s --uid %s --nohup=true", scope, target, actionCommand.Name(), model.Uid)
+			args := []string{"create", target, actionCommand.Name()}
+			if scope == "docker" || scope == "cri" {
+				args = []string{"create", scope, target, actionCommand.Name()}
+			} else if scope != "host" {
+				args = []string{"create", "k8s", scope + "-" + target, actionCommand.Name()}
 			}
+			args = append(args, "--"+UidFlag, model.Uid, "--"+NohupFlag+"=true")
 			cmd.Flags().VisitAll(func(flag *pflag.Flag) {
 				if flag.Value.String() == "false" {
 					return
 				}
-				if flag.Name == AsyncFlag || flag.Name == UidFlag {
+				if flag.Name == AsyncFlag || flag.Name == UidFlag || flag.Name == GroupFlag || flag.Name == LabelFlag ||
+					flag.Name == ProbeFlag || flag.Name == AbortIfFlag || flag.Name == OutputFlag {
 					return
 				}
-				args = fmt.Sprintf("%s --%s=%s ", args, flag.Name, flag.Value)
+				args = append(args, fmt.Sprintf("--%s=%s", flag.Name, flag.Value))
 			})
-			args = fmt.Sprintf("%s %s %s", path.Join(util.GetProgramPath(), "blade"), args, "> /dev/null 2>&1 &")
-			response := channel.NewLocalChannel().Run(context.Background(), "nohup", args)
+			for _, condition := range cc.abortIf {
+				args = append(args, fmt.Sprintf("--%s=%s", AbortIfFlag, condition))
+			}
+			response := spec.ReturnSuccess(model.Uid)
+			if err := launchInBackground(cc.launcher, nil, args...); err != nil {
+				response = spec.ResponseFailWithFlags(spec.OsCmdExecFailed, "nohup", err)
+			}
 			if response.Success {
 				log.Infof(ctx, "async create success, uid: %s", model.Uid)
-				cmd.Println(spec.ReturnSuccess(model.Uid).Print())
 			} else {
 				log.Warnf(ctx, "async create fail, err: %s, uid: %s", response.Err, model.Uid)
-				cmd.Println(spec.ResponseFailWithFlags(spec.OsCmdExecFailed, "nohup", response.Err).Print())
 			}
+			cmd.Println(response.Print())
 			return nil
 		} else {
 			// execute experiment
 			executor := actionCommandSpec.Executor()
 			executor.SetChannel(channel.NewLocalChannel())
 			ctx := context.WithValue(context.Background(), spec.Uid, model.Uid)
-			response := executor.Exec(model.Uid, ctx, expModel)
+			response := execExperiment(ctx, executor, model.Uid, expModel, "create")
 			if response.Code == spec.ReturnOKDirectly.Code {
 				// return directly
 				response.Code = spec.OK.Code
@@ -191,37 +271,24 @@ func (cc *CreateCommand) actionRunEFunc(target, scope string, actionCommand *act
 			actionCommand.expModel = expModel
 			actionCommand.uid = model.Uid
 
+			if err := updateExpStatusByResponse(ctx, model.Uid, expModel, response); err != nil {
+				response = recordFailedResponse(model.Uid, err)
+			}
+			countCreatedExperiment(expModel, response)
 			if !response.Success {
-				// update status
-				checkError(GetDS().UpdateExperimentModelByUid(model.Uid, Error, response.Err))
 				endpointCallBack(ctx, endpoint, model.Uid, response)
 				return response
 			}
-
-			if expModel.ActionProcessHang && scope != "pod" && scope != "container" && scope != "node" && expModel.ActionFlags["channel"] != "ssh" {
-				// todo -> need to find a better way to query the status
-				time.Sleep(time.Millisecond * 100)
-				log.Debugf(ctx, "result: %v", response.Result)
-				if response.Result == nil {
-					errMsg := "chaos_os process not found, please check chaosblade log"
-					checkError(GetDS().UpdateExperimentModelByUid(model.Uid, Error, errMsg))
-					response.Err = errMsg
-				} else {
-					_, err := process.NewProcess(int32(response.Result.(int)))
-					if err != nil {
-						errMsg := fmt.Sprintf("chaos_os process not found, please check chaosblade log, err: %s", err.Error())
-						checkError(GetDS().UpdateExperimentModelByUid(model.Uid, Error, errMsg))
-						response.Err = errMsg
-					} else {
-						// update status
-						checkError(GetDS().UpdateExperimentModelByUid(model.Uid, Success, response.Err))
-					}
+			if len(probes) > 0 {
+				if err := startProbeWatcher(cc.launcher, model.Uid, cc.probeInterval); err != nil {
+					log.Warnf(ctx, "start the probe watcher failed, %v", err)
+				}
+			}
+			if len(cc.abortIf) > 0 {
+				if err := startAbortWatcher(cc.launcher, model.Uid, cc.abortIf, cc.abortSamples, cc.abortInterval); err != nil {
+					log.Warnf(ctx, "start the abort watcher failed, %v", err)
 				}
-			} else {
-				// update status
-				checkError(GetDS().UpdateExperimentModelByUid(model.Uid, Success, response.Err))
 			}
-			response.Result = model.Uid
 			cmd.Println(response.Print())
 			endpointCallBack(ctx, endpoint, model.Uid, response)
 			return nil
@@ -229,6 +296,94 @@ func (cc *CreateCommand) actionRunEFunc(target, scope string, actionCommand *act
 	}
 }
 
+// createExperiment creates the experiment in process, which is used by the blade server and plan files.
+// The flags of the experiment model are checked against the action spec before executing.
+func (cc *CreateCommand) createExperiment(expModel *spec.ExpModel, groupUid string, labels map[string]string) *spec.Response {
+	if response := checkPanicLock(); response != nil {
+		return response
+	}
+	actionSpec := cc.GetActionSpec(expModel)
+	if actionSpec == nil || actionSpec.Executor() == nil {
+		parent, actionTarget := getParentAndActionTarget(expModel.Target, expModel.Scope)
+		return spec.ResponseFailWithFlags(spec.HandlerExecNotFound, createExecutorKey(parent, actionTarget, expModel.ActionName))
+	}
+	if err := cc.checkExpModelFlags(expModel, actionSpec); err != nil {
+		return err.(*spec.Response)
+	}
+	var timeout uint64
+	if tt := expModel.ActionFlags["timeout"]; tt != "" {
+		var err error
+		if timeout, err = parseTimeout(tt); err != nil {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, "timeout", tt, err)
+		}
+	}
+	if response := checkExperimentPolicy(expModel, actionSpec.ExpActionCommandSpec); response != nil {
+		return response
+	}
+	expModel.ActionProcessHang = actionSpec.ProcessHang()
+	command, subCommand := getCommandAndSubCommand(expModel)
+	model, resp := cc.recordExpModelWithCommand(command, subCommand, groupUid, labels, expModel)
+	if !resp.Success {
+		return resp
+	}
+	executor := actionSpec.Executor()
+	executor.SetChannel(channel.NewLocalChannel())
+	ctx := context.WithValue(context.Background(), spec.Uid, model.Uid)
+	response := execExperiment(ctx, executor, model.Uid, expModel, "create")
+	if response.Code == spec.ReturnOKDirectly.Code {
+		response.Code = spec.OK.Code
+	}
+	if err := updateExpStatusByResponse(ctx, model.Uid, expModel, response); err != nil {
+		response = recordFailedResponse(model.Uid, err)
+	}
+	countCreatedExperiment(expModel, response)
+	if response.Success && timeout > 0 {
+		if err := scheduleDestroy(cc.launcher, model.Uid, expModel.Scope, timeout); err != nil {
+			log.Warnf(ctx, "schedule the timeout destroying failed, %v", err)
+		}
+	}
+	return response
+}
+
+// updateExpStatusByResponse updates the experiment record by the executor response.
+// If the action process hangs, the process is checked before the experiment is marked as success.
+// It returns the error of recording, the experiment may be running even if the recording fails.
+func updateExpStatusByResponse(ctx context.Context, uid string, expModel *spec.ExpModel, response *spec.Response) error {
+	if !response.Success {
+		data.SetEventCode(uid, response.Code)
+		return updateExpStatus(uid, Error, response.Err)
+	}
+	scope := expModel.Scope
+	if expModel.ActionProcessHang && scope != "pod" && scope != "container" && scope != "node" && expModel.ActionFlags["channel"] != "ssh" {
+		// todo -> need to find a better way to query the status
+		time.Sleep(time.Millisecond * 100)
+		log.Debugf(ctx, "result: %v", response.Result)
+		if response.Result == nil {
+			errMsg := "chaos_os process not found, please check chaosblade log"
+			response.Err = errMsg
+			response.Result = uid
+			return updateExpStatus(uid, Error, errMsg)
+		}
+		if _, err := process.NewProcess(int32(response.Result.(int))); err != nil {
+			errMsg := fmt.Sprintf("chaos_os process not found, please check chaosblade log, err: %s", err.Error())
+			response.Err = errMsg
+			response.Result = uid
+			return updateExpStatus(uid, Error, errMsg)
+		}
+	}
+	// update status
+	response.Result = uid
+	return updateExpStatus(uid, Success, response.Err)
+}
+
+// recordFailedResponse returns the response of the experiment whose status is not recorded
+func recordFailedResponse(uid string, err error) *spec.Response {
+	response := spec.ResponseFailWithFlags(spec.DatabaseError, "update",
+		fmt.Sprintf("record the status of the %s experiment failed, %v", uid, err))
+	response.Result = uid
+	return response
+}
+
 func endpointCallBack(ctx context.Context, endpoint, uid string, response *spec.Response) {
 	if endpoint != "" {
 		log.Infof(ctx, "report response: %s to endpoint: %s", response.Print(), endpoint)
@@ -251,37 +406,61 @@ func endpointCallBack(ctx context.Context, endpoint, uid string, response *spec.
 
 func (cc *CreateCommand) actionPostRunEFunc(actionCommand *actionCommand) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		const bladeBin = "blade"
 		if actionCommand.expModel != nil {
 			tt := actionCommand.expModel.ActionFlags["timeout"]
 			async := actionCommand.expModel.ActionFlags[AsyncFlag] == "true"
 			if tt == "" || async {
 				return nil
 			}
-			// err possible if timeout used as timeDuration.
-			timeout, err := strconv.ParseUint(tt, 10, 64)
-			if err != nil {
-				// the err checked in RunE function
-				timeDuartion, _ := time.ParseDuration(tt)
-				timeout = uint64(timeDuartion.Seconds())
-			}
+			// the err checked in RunE function
+			timeout, _ := parseTimeout(tt)
 
 			if timeout > 0 && actionCommand.uid != "" {
-				// fix https://github.com/chaosblade-io/chaosblade-operator/issues/34
-				if actionCommand.expModel.Scope == "container" || actionCommand.expModel.Scope == "pod" {
-					timeout = timeout + 60
-				}
-				script := path.Join(util.GetProgramPath(), bladeBin)
-				args := fmt.Sprintf("nohup /bin/sh -c 'sleep %d; %s destroy %s' > /dev/null 2>&1 &",
-					timeout, script, actionCommand.uid)
-				cmd := exec.CommandContext(context.TODO(), "/bin/sh", "-c", args)
-				return cmd.Run()
+				return scheduleDestroy(cc.launcher, actionCommand.uid, actionCommand.expModel.Scope, timeout)
 			}
 		}
 		return nil
 	}
 }
 
+// parseTimeout returns the timeout seconds, the value is a number of seconds or a time duration
+func parseTimeout(value string) (uint64, error) {
+	timeout, err := strconv.ParseUint(value, 10, 64)
+	if err == nil {
+		return timeout, nil
+	}
+	timeDuration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(timeDuration.Seconds()), nil
+}
+
+// scheduleDestroy records the deadline of the experiment and starts a background process to reconcile it after the
+// timeout seconds. The deadline is persisted, so the experiment is still expired by the `blade reconcile` command or
+// the blade server if the background process is killed or the host reboots.
+func scheduleDestroy(launcher processLauncher, uid, scope string, timeout uint64) error {
+	// fix https://github.com/chaosblade-io/chaosblade-operator/issues/34
+	if scope == "container" || scope == "pod" {
+		timeout = timeout + 60
+	}
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	if err := GetDS().InsertExperimentDeadline(uid, deadline); err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "insert", err)
+	}
+	return launchInBackground(launcher, []string{data.AuditSourceEnv + "=" + data.AuditSourceTimeout},
+		"reconcile", "--uid", uid, "--"+WaitFlag, fmt.Sprintf("%ds", timeout))
+}
+
 func createExample() string {
-	return `blade create cpu load --cpu-percent 60`
+	return `blade create cpu load --cpu-percent 60
+
+# Create the experiment in a group with labels
+blade create cpu load --cpu-percent 60 --group gameday-1 --label team=sre --label env=staging
+
+# Create the experiment if the service is healthy, and destroy it once the service is not
+blade create cpu load --cpu-percent 60 --probe "http://127.0.0.1:8080/health expect=200 p99<300ms"
+
+# Show the chaos_os command of the experiment without executing it
+blade create cpu load --cpu-percent 60 --dry-run`
 }
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+// runCreateAction runs the create command of the cpu fullload action with the flags
+func runCreateAction(cc *CreateCommand, flags map[string]string, executor spec.Executor) error {
+	command := &cobra.Command{Use: "fullload"}
+	command.SetOut(io.Discard)
+	for name, value := range flags {
+		command.Flags().String(name, value, "")
+	}
+	actionSpec := &spec.ActionModel{ActionName: "fullload"}
+	actionSpec.SetExecutor(executor)
+	return cc.actionRunEFunc("cpu", "host", &actionCommand{}, actionSpec)(command, nil)
+}
+
+// newTestCreateCommand returns the create command which records the launched processes
+func newTestCreateCommand(launches *[]string) *CreateCommand {
+	return &CreateCommand{
+		probeInterval: defaultProbeInterval,
+		abortSamples:  defaultAbortSamples,
+		abortInterval: defaultAbortInterval,
+		launcher:      recordLaunches(launches),
+	}
+}
+
+func Test_CreateCommand_dryRunWhilePanicLocked(t *testing.T) {
+	t.Setenv(PanicLockPathEnv, filepath.Join(t.TempDir(), panicLockFile))
+	t.Setenv(PolicyFileEnv, filepath.Join(t.TempDir(), "policy.yaml"))
+	if _, err := writePanicLock(time.Now()); err != nil {
+		t.Fatalf("write panic lock failed, %v", err)
+	}
+	cc := newTestCreateCommand(&[]string{})
+	cc.dryRun = true
+	if err := runCreateAction(cc, map[string]string{"timeout": "60"}, &probeTestExecutor{}); err != nil {
+		t.Errorf("unexpected error of the dry run: %v", err)
+	}
+	cc.dryRun = false
+	err := runCreateAction(cc, map[string]string{"timeout": "60"}, &probeTestExecutor{})
+	if response, ok := err.(*spec.Response); !ok || response.Code != ChaosPanicLocked.Code {
+		t.Errorf("expected the panic lock, got %v", err)
+	}
+}
+
+func Test_CreateCommand_nohup(t *testing.T) {
+	t.Setenv(PanicLockPathEnv, filepath.Join(t.TempDir(), panicLockFile))
+	t.Setenv(PolicyFileEnv, filepath.Join(t.TempDir(), "policy.yaml"))
+	defer SetDS(&MockSource{})
+	src := data.NewMemorySource()
+	// the async creating records the experiment with the probes before starting the nohup process
+	if err := src.InsertExperimentModel(&data.ExperimentModel{
+		Uid: "async", Command: "cpu", SubCommand: "fullload", Status: Created,
+		Probes: []*data.Probe{{Definition: "exec:exit 0"}},
+	}); err != nil {
+		t.Fatalf("insert experiment failed, %v", err)
+	}
+	SetDS(src)
+	tests := []struct {
+		name     string
+		uid      string
+		abortIf  []string
+		code     int32
+		launches []string
+	}{
+		{"missing record", "missing", nil, spec.DataNotFound.Code, []string{}},
+		{"probes and abort conditions", "async", []string{"cpu>95"}, 0, []string{
+			"probe async --watch --interval 10s",
+			"abort async --if cpu>95 --samples 3 --interval 5s",
+		}},
+	}
+	for _, tt := range tests {
+		launches := make([]string, 0)
+		cc := newTestCreateCommand(&launches)
+		cc.abortIf = tt.abortIf
+		err := runCreateAction(cc, map[string]string{NohupFlag: "true", UidFlag: tt.uid, "timeout": "60"},
+			&probeTestExecutor{})
+		code := int32(0)
+		if response, ok := err.(*spec.Response); ok {
+			code = response.Code
+		} else if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if code != tt.code || !reflect.DeepEqual(launches, tt.launches) {
+			t.Errorf("%s: unexpected code %d, launches %v, error %v", tt.name, code, launches, err)
+		}
+	}
+	if model, err := src.QueryExperimentModelByUid("async"); err != nil || model.Status != Success {
+		t.Errorf("unexpected experiment %+v, %v", model, err)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// DBCommand manages the chaosblade data file
+type DBCommand struct {
+	baseCommand
+}
+
+func (dc *DBCommand) Init() {
+	dc.command = &cobra.Command{
+		Use:   "db",
+		Short: "Manage the chaosblade data file",
+		Long:  "Manage the chaosblade data file which records the experiments and preparations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return spec.ResponseFailWithFlags(spec.CommandIllegal, "less migrate command")
+		},
+		Example: dbExample(),
+	}
+}
+
+func dbExample() string {
+	return `blade db migrate --dry-run`
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+// DBMigrateCommand upgrades the schema of the data file to the version supported by blade
+type DBMigrateCommand struct {
+	baseCommand
+	dryRun bool
+}
+
+// migrateResult is the result of migrate command, migrations are the pending ones if dry-run, else the applied ones
+type migrateResult struct {
+	DataFile       string           `json:"dataFile"`
+	CurrentVersion int              `json:"currentVersion"`
+	TargetVersion  int              `json:"targetVersion"`
+	DryRun         bool             `json:"dryRun"`
+	Migrations     []data.Migration `json:"migrations"`
+}
+
+func (mc *DBMigrateCommand) Init() {
+	mc.command = &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the data file to the latest schema",
+		Long: "Migrate the data file to the latest schema supported by blade. Every migration is applied in a transaction. " +
+			"Other commands migrate the data file automatically, the command is used to check or upgrade it in advance.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return mc.runMigrate(cmd)
+		},
+		Example: migrateExample(),
+	}
+	mc.command.Flags().BoolVar(&mc.dryRun, "dry-run", false, "only print the pending migrations")
+}
+
+func (mc *DBMigrateCommand) runMigrate(cmd *cobra.Command) error {
+	dataFile, err := data.SQLiteDataFile()
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "migrate", err)
+	}
+	source, err := data.NewSource(dataFile)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "migrate", err)
+	}
+	defer source.Close()
+	version, pending, err := source.PendingMigrations()
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "migrate", err)
+	}
+	result := &migrateResult{
+		DataFile:       dataFile,
+		CurrentVersion: version,
+		TargetVersion:  data.UserVersion,
+		DryRun:         mc.dryRun,
+		Migrations:     pending,
+	}
+	if !mc.dryRun {
+		result.Migrations, err = source.Migrate()
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.DatabaseError, "migrate", err)
+		}
+	}
+	cmd.Println(spec.ReturnSuccess(result).Print())
+	return nil
+}
+
+func migrateExample() string {
+	return `# Print the pending migrations
+blade db migrate --dry-run
+
+# Migrate the data file
+blade db migrate`
+}
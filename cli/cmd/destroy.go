@@ -47,17 +47,51 @@ type DestroyCommand struct {
 	forceRemove           bool
 	expTarget, kubeconfig string
 	proxyURL, token       string
+	group                 string
+	allOrphans            bool
+	// the filters of the bulk destroying
+	all               bool
+	status, olderThan string
+	labels            []string
+	parallelism       int
+	dryRun            bool
+	// launcher evaluates the probes in the background after destroying
+	launcher processLauncher
+}
+
+// destroyResult is the destroying result of an experiment when destroying several experiments
+type destroyResult struct {
+	Uid     string `json:"uid"`
+	Target  string `json:"target,omitempty"`
+	Success bool   `json:"success"`
+	// Error is the error of destroying, and RemoveError is the error of forcibly removing the record and resource
+	Error       string `json:"error,omitempty"`
+	RemoveError string `json:"removeError,omitempty"`
 }
 
 func (dc *DestroyCommand) Init() {
 	dc.command = &cobra.Command{
-		Use:     "destroy UID",
-		Short:   "Destroy a chaos experiment",
-		Long:    "Destroy a chaos experiment by experiment uid which you can run status command to query",
-		Args:    cobra.MinimumNArgs(1),
+		Use:   "destroy UID",
+		Short: "Destroy a chaos experiment",
+		Long:  "Destroy a chaos experiment by experiment uid which you can run status command to query",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if dc.group != "" || dc.allOrphans || dc.isBulk(args) {
+				return nil
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		Aliases: []string{"d"},
 		Example: destroyExample(),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if dc.allOrphans {
+				return dc.runDestroyOrphans(cmd)
+			}
+			if dc.isBulk(args) {
+				return dc.runDestroyBulk(cmd)
+			}
+			if dc.group != "" {
+				return dc.runDestroyWithGroup(cmd)
+			}
 			return dc.runDestroyWithUid(context.Background(), cmd, args)
 		},
 	}
@@ -68,6 +102,17 @@ func (dc *DestroyCommand) Init() {
 	flags.StringVar(&dc.kubeconfig, KubeconfigFlag, "", "The config file of kubernetes cluster. Used to destroy creating k8s experiments without using blade command")
 	flags.StringVar(&dc.proxyURL, ProxyURLFlag, "", "Kubectl proxy URL for accessing Kubernetes API, e.g., http://localhost:8001")
 	flags.StringVar(&dc.token, TokenFlag, "", "Bearer token for Kubernetes API authentication")
+	flags.StringVar(&dc.group, GroupFlag, "", "Destroy all created experiments of the group")
+	flags.BoolVar(&dc.dryRun, DryRunFlag, false, "Show what would be destroyed and executed, without executing it or updating the records")
+	flags.BoolVar(&dc.allOrphans, AllOrphansFlag, false, "Destroy the chaos processes, jvm sandboxes and cplus proxies which are not tracked by the records, such as the ones left after the data file is lost")
+	// the bulk flags are not inherited by the action commands
+	bulkFlags := dc.command.Flags()
+	bulkFlags.BoolVar(&dc.all, AllFlag, false, "Destroy all Created and Success experiments")
+	bulkFlags.StringVar(&dc.status, StatusFlag, "", "Destroy the experiments of the comma separated statuses, support Created, Success and Error, default value is Created,Success")
+	bulkFlags.StringVar(&dc.olderThan, OlderThanFlag, "", "Destroy the experiments created before the age, for example: 2h, 7d")
+	bulkFlags.StringArrayVar(&dc.labels, LabelFlag, nil, "Destroy the experiments which have the label in key=value format, can be specified multiple times")
+	bulkFlags.IntVar(&dc.parallelism, ParallelismFlag, 4, "The maximum number of the experiments destroyed at the same time in bulk")
+	dc.launcher = nohupLauncher{}
 	dc.baseExpCommandService = newBaseExpCommandService(dc)
 }
 
@@ -80,6 +125,9 @@ func (dc *DestroyCommand) runDestroyWithUid(ctx context.Context, cmd *cobra.Comm
 	lowerExpTarget := strings.ToLower(dc.expTarget)
 	isK8sTarget := lowerExpTarget == "kubernetes" || lowerExpTarget == "k8s"
 	if err != nil || model == nil {
+		if isK8sTarget && dc.dryRun {
+			return dc.dryRunDestroyWithoutRecord(cmd, uid)
+		}
 		if isK8sTarget {
 			return dc.destroyAndRemoveK8sExperimentWithoutRecordByForceFlag(cmd, uid)
 		}
@@ -88,9 +136,65 @@ func (dc *DestroyCommand) runDestroyWithUid(ctx context.Context, cmd *cobra.Comm
 		}
 		return spec.ResponseFailWithFlags(spec.DataNotFound, uid)
 	}
+	if dc.dryRun {
+		return dc.dryRunDestroy(cmd, []*data.ExperimentModel{model})
+	}
 	return dc.destroyAndRemoveExperimentByUidAndForceFlag(cmd, err, model, uid, isK8sTarget)
 }
 
+// runDestroyWithGroup destroys the Created and Success experiments of the group in the reverse order of creation.
+// The records are removed if force-remove is true.
+func (dc *DestroyCommand) runDestroyWithGroup(cmd *cobra.Command) error {
+	ctx := context.Background()
+	log.Infof(ctx, "destroy by %s group, force-remove: %t", dc.group, dc.forceRemove)
+	models, err := GetDS().QueryExperimentModelsByGroup(dc.group)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
+	}
+	if len(models) == 0 {
+		return spec.ResponseFailWithFlags(spec.DataNotFound, dc.group)
+	}
+	if dc.dryRun {
+		selected := make([]*data.ExperimentModel, 0)
+		for idx := len(models) - 1; idx >= 0; idx-- {
+			if models[idx].Status == Created || models[idx].Status == Success {
+				selected = append(selected, models[idx])
+			}
+		}
+		return dc.dryRunDestroy(cmd, selected)
+	}
+	results := make([]*destroyResult, 0)
+	var failed *spec.Response
+	for idx := len(models) - 1; idx >= 0; idx-- {
+		model := models[idx]
+		if model.Status != Created && model.Status != Success {
+			continue
+		}
+		result := &destroyResult{Uid: model.Uid, Success: true}
+		if _, err := dc.destroyExperimentByUid(model, model.Uid); err != nil {
+			response := err.(*spec.Response)
+			result.Success = false
+			result.Error = response.Err
+			if failed == nil {
+				failed = response
+			}
+		} else if err := dc.checkAndForceRemoveForExpRecord(model.Uid); err != nil {
+			result.Error = fmt.Sprintf("forcibly remove the record failed, %v", err)
+		}
+		results = append(results, result)
+	}
+	if failed != nil {
+		return &spec.Response{
+			Code:    failed.Code,
+			Success: false,
+			Err:     fmt.Sprintf("destroy the experiments of %s group failed, %s", dc.group, failed.Err),
+			Result:  results,
+		}
+	}
+	cmd.Println(spec.ReturnSuccess(results).Print())
+	return nil
+}
+
 // destroyAndRemoveK8sExperimentWithoutRecordByForceFlag deletes and forcibly removes the chaosblade resources in the cluster by the forceRemoveFlag.
 func (dc *DestroyCommand) destroyAndRemoveK8sExperimentWithoutRecordByForceFlag(cmd *cobra.Command, uid string) error {
 	response, err := dc.destroyK8sExperimentWithoutRecord(uid)
@@ -119,11 +223,7 @@ func (dc *DestroyCommand) destroyAndRemoveExperimentByUidAndForceFlag(
 	cmd *cobra.Command, err error, model *data.ExperimentModel, uid string, isK8sTarget bool,
 ) error {
 	response, err := dc.destroyExperimentByUid(model, uid)
-	removeRecordErr := dc.checkAndForceRemoveForExpRecord(uid)
-	var removeResourceErr error
-	if isK8sTarget {
-		removeResourceErr = dc.checkAndForceRemoveForK8sExp(uid, dc.kubeconfig, dc.proxyURL)
-	}
+	removeRecordErr, removeResourceErr := dc.checkAndForceRemoveExperiment(uid, isK8sTarget)
 	if err == nil {
 		if removeRecordErr == nil && removeResourceErr == nil {
 			cmd.Println(response.Print())
@@ -150,7 +250,7 @@ func (dc *DestroyCommand) destroyExperimentByUid(model *data.ExperimentModel, ui
 	if model == nil {
 		return nil, spec.ResponseFailWithFlags(spec.DataNotFound, uid)
 	}
-	if model.Status == Destroyed {
+	if model.Status == Destroyed || model.Status == Expired || model.Status == Aborted {
 		result := fmt.Sprintf("command: %s %s %s, destroy time: %s",
 			model.Command, model.SubCommand, model.Flag, model.UpdateTime)
 		return spec.ReturnSuccess(result), nil
@@ -188,6 +288,48 @@ func (dc *DestroyCommand) destroyK8sExperimentWithoutRecord(uid string) (*spec.R
 	return spec.ReturnSuccess(exp), nil
 }
 
+// checkAndForceRemoveExperiment deletes the experiment record, and the chaosblade resource of the k8s experiment,
+// if force-remove is true
+func (dc *DestroyCommand) checkAndForceRemoveExperiment(uid string, isK8sTarget bool) (
+	removeRecordErr, removeResourceErr error,
+) {
+	removeRecordErr = dc.checkAndForceRemoveForExpRecord(uid)
+	if isK8sTarget {
+		removeResourceErr = dc.checkAndForceRemoveForK8sExp(uid, dc.kubeconfig, dc.proxyURL)
+	}
+	return
+}
+
+// destroyAndForceRemoveExperiment destroys the experiment of the record like destroying it by uid, the record and
+// resource are forcibly removed even if the destroying fails. The result reports the errors separately.
+func (dc *DestroyCommand) destroyAndForceRemoveExperiment(model *data.ExperimentModel) *destroyResult {
+	result := &destroyResult{
+		Uid:     model.Uid,
+		Target:  strings.TrimSpace(model.Command + " " + model.SubCommand),
+		Success: true,
+	}
+	if _, err := dc.destroyExperimentByUid(model, model.Uid); err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		if response, ok := err.(*spec.Response); ok {
+			result.Error = response.Err
+		}
+	}
+	removeErrors := make([]string, 0)
+	removeRecordErr, removeResourceErr := dc.checkAndForceRemoveExperiment(model.Uid, model.Command == "k8s")
+	if removeRecordErr != nil {
+		removeErrors = append(removeErrors, fmt.Sprintf("remove the record failed, %v", removeRecordErr))
+	}
+	if removeResourceErr != nil {
+		removeErrors = append(removeErrors, fmt.Sprintf("remove the resource failed, %v", removeResourceErr))
+	}
+	if len(removeErrors) > 0 {
+		result.Success = false
+		result.RemoveError = strings.Join(removeErrors, "; ")
+	}
+	return result
+}
+
 // checkAndForceRemoveForK8sExp deletes chaosblade resource by resource name if force-remove is true
 func (dc *DestroyCommand) checkAndForceRemoveForK8sExp(name, kubeconfig, proxyURL string) error {
 	if dc.forceRemove {
@@ -205,35 +347,41 @@ func (dc *DestroyCommand) checkAndForceRemoveForExpRecord(uid string) error {
 }
 
 func (dc *DestroyCommand) destroyExperiment(uid string, executor spec.Executor, expModel *spec.ExpModel) error {
+	return dc.destroyExperimentWithStatus(uid, executor, expModel, Destroyed, "")
+}
+
+// destroyExperimentWithStatus destroys the experiment and updates the record to the status, Destroyed, Expired or
+// Aborted, the reason is recorded as the error of the experiment
+func (dc *DestroyCommand) destroyExperimentWithStatus(uid string, executor spec.Executor, expModel *spec.ExpModel,
+	status, reason string,
+) error {
 	// set destroy flag
 	ctx := spec.SetDestroyFlag(context.Background(), uid)
 	ctx = context.WithValue(ctx, spec.Uid, uid)
 	// execute
-	response := executor.Exec(uid, ctx, expModel)
+	response := execExperiment(ctx, executor, uid, expModel, "destroy")
 	if !response.Success {
 		return response
 	}
 	// return result
-	checkError(GetDS().UpdateExperimentModelByUid(uid, Destroyed, ""))
+	if err := updateExpStatus(uid, status, reason); err != nil {
+		if conflict, ok := err.(*data.StatusConflictError); !ok || conflict.Status != "" {
+			return recordFailedResponse(uid, err)
+		}
+		// the k8s experiment created by others has no record
+	}
+	if err := GetDS().DeleteExperimentDeadline(uid); err != nil {
+		return recordFailedResponse(uid, err)
+	}
+	experimentsDestroyed.WithLabelValues(append(expModelLabels(expModel), status)...).Inc()
+	startAfterDestroyProbes(dc.launcher, uid)
 	return nil
 }
 
 func (dc *DestroyCommand) getExecutorAndExpModelByRecord(model *data.ExperimentModel) (
 	executor spec.Executor, expModel *spec.ExpModel, err error,
 ) {
-	firstCommand := model.Command
-	var actionCommand, actionTargetCommand string
-	subCommands := strings.Split(model.SubCommand, " ")
-	subLength := len(subCommands)
-	if subLength > 0 {
-		if subLength > 1 {
-			actionCommand = subCommands[subLength-1]
-			actionTargetCommand = subCommands[subLength-2]
-		} else {
-			actionCommand = subCommands[0]
-			actionTargetCommand = ""
-		}
-	}
+	firstCommand, actionTargetCommand, actionCommand := splitRecordCommand(model)
 	executor = dc.GetExecutor(firstCommand, actionTargetCommand, actionCommand)
 	if executor == nil {
 		err = fmt.Errorf("can't find executor for %s, %s", model.Command, model.SubCommand)
@@ -247,6 +395,22 @@ func (dc *DestroyCommand) getExecutorAndExpModelByRecord(model *data.ExperimentM
 	return executor, expModel, err
 }
 
+// splitRecordCommand returns the target, action target and action of the experiment record,
+// which are the lookup key of the executor and action spec
+func splitRecordCommand(model *data.ExperimentModel) (target, actionTarget, action string) {
+	subCommands := strings.Split(model.SubCommand, " ")
+	subLength := len(subCommands)
+	if subLength > 1 {
+		return model.Command, subCommands[subLength-2], subCommands[subLength-1]
+	}
+	return model.Command, "", subCommands[0]
+}
+
+// getActionSpecByRecord returns the action spec of the experiment record
+func (ec *baseExpCommandService) getActionSpecByRecord(model *data.ExperimentModel) *expActionSpec {
+	return ec.actionSpecs[createExecutorKey(splitRecordCommand(model))]
+}
+
 func (dc *DestroyCommand) getExecutorAndExpModelByChaosBladeResource(chaosBlade *v1alpha1.ChaosBlade) (
 	executor spec.Executor, expModel *spec.ExpModel, err error,
 ) {
@@ -311,6 +475,7 @@ func (dc *DestroyCommand) bindFlagsFunction() func(commandFlags map[string]func(
 func (dc *DestroyCommand) actionRunEFunc(target, scope string, _ *actionCommand, actionCommandSpec spec.ExpActionCommandSpec) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		expModel := createExpModel(target, scope, actionCommandSpec.Name(), cmd)
+		delete(expModel.ActionFlags, DryRunFlag)
 		ctx := context.Background()
 		log.Infof(ctx, "destroy %+v", expModel)
 		// If uid exists, use uid first. If the record cannot be found, then continue to destroy using matchers
@@ -331,9 +496,17 @@ func (dc *DestroyCommand) actionRunEFunc(target, scope string, _ *actionCommand,
 			log.Warnf(ctx, "the force-remove flag does not work if the uid does not exist.")
 		}
 		executor := actionCommandSpec.Executor()
+		if dc.dryRun {
+			command, subCommand := getCommandAndSubCommand(expModel)
+			result, response := dryRunExperiment(spec.UnknownUid, command+" "+subCommand, executor, expModel, true)
+			if response != nil {
+				return response
+			}
+			return printDryRun(cmd, []*dryRunResult{result})
+		}
 		executor.SetChannel(channel.NewLocalChannel())
 		ctx = spec.SetDestroyFlag(ctx, spec.UnknownUid)
-		response := executor.Exec(spec.UnknownUid, ctx, expModel)
+		response := execExperiment(ctx, executor, spec.UnknownUid, expModel, "destroy")
 		if !response.Success {
 			return response
 		}
@@ -350,7 +523,15 @@ func (dc *DestroyCommand) actionRunEFunc(target, scope string, _ *actionCommand,
 			log.Warnf(ctx, "destroy success but query records failed, %v", err)
 		} else {
 			for _, record := range experimentModels {
-				checkError(GetDS().UpdateExperimentModelByUid(record.Uid, Destroyed, ""))
+				if record.Status == Destroyed || record.Status == Expired || record.Status == Aborted {
+					continue
+				}
+				if err := updateExpStatus(record.Uid, Destroyed, ""); err != nil {
+					return recordFailedResponse(record.Uid, err)
+				}
+				if err := GetDS().DeleteExperimentDeadline(record.Uid); err != nil {
+					return recordFailedResponse(record.Uid, err)
+				}
 			}
 		}
 		cmd.Println(spec.ReturnSuccess(expModel).Print())
@@ -367,6 +548,21 @@ func destroyExample() string {
 # Destroy experiment
 blade destroy 47cc0744f1bb
 
+# Destroy all experiments of the group
+blade destroy --group gameday-1
+
+# Destroy all running experiments
+blade destroy --all
+
+# Destroy the network experiments created 2 hours ago with the label
+blade destroy --status Success --target network --older-than 2h --label team=sre
+
+# Destroy the running faults whose records are lost
+blade destroy --all-orphans
+
+# Show what would be destroyed without destroying
+blade destroy --all --dry-run
+
 # Force delete kubernetes experiment
 blade destroy 47cc0744f1bb --target k8s --kubeconfig ~/.kube/config --force-remove`
 }
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+const (
+	AllFlag         = "all"
+	StatusFlag      = "status"
+	OlderThanFlag   = "older-than"
+	ParallelismFlag = "parallelism"
+)
+
+// bulkDestroyStatuses are the statuses accepted by the --status flag of the bulk destroying
+var bulkDestroyStatuses = []string{Created, Success, Error}
+
+// bulkDestroyFilter selects the experiment records to destroy, the empty fields match all
+type bulkDestroyFilter struct {
+	statuses []string
+	target   string
+	group    string
+	labels   map[string]string
+	// createdBefore is zero if the age is not limited
+	createdBefore time.Time
+}
+
+// isBulk returns true if the experiments are selected by the filter flags instead of the uid.
+// The group only keeps destroying the group in the reverse order of creation.
+func (dc *DestroyCommand) isBulk(args []string) bool {
+	return len(args) == 0 && (dc.all || dc.status != "" || dc.olderThan != "" || len(dc.labels) > 0 || dc.expTarget != "")
+}
+
+// newBulkDestroyFilter converts the flags to the filter
+func (dc *DestroyCommand) newBulkDestroyFilter(now time.Time) (*bulkDestroyFilter, error) {
+	filter := &bulkDestroyFilter{statuses: []string{Created, Success}, group: dc.group}
+	if dc.status != "" {
+		filter.statuses = make([]string, 0)
+		for _, status := range strings.Split(dc.status, ",") {
+			if status = data.UpperFirst(strings.TrimSpace(status)); status == "" {
+				continue
+			}
+			if !containsString(bulkDestroyStatuses, status) {
+				return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, StatusFlag, dc.status,
+					fmt.Sprintf("only support %s", strings.Join(bulkDestroyStatuses, ", ")))
+			}
+			filter.statuses = append(filter.statuses, status)
+		}
+		if len(filter.statuses) == 0 {
+			return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, StatusFlag, dc.status, "the statuses are empty")
+		}
+	}
+	filter.target = strings.ToLower(dc.expTarget)
+	if filter.target == "kubernetes" {
+		filter.target = "k8s"
+	}
+	labels, err := parseLabels(dc.labels)
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, LabelFlag, dc.labels, err)
+	}
+	filter.labels = labels
+	if dc.olderThan != "" {
+		age, err := data.ParseAge(dc.olderThan)
+		if err != nil {
+			return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, OlderThanFlag, dc.olderThan, err)
+		}
+		filter.createdBefore = now.Add(-age)
+	}
+	return filter, nil
+}
+
+// match returns true if the experiment matches the group, labels and age, the status and target are
+// matched by the query
+func (f *bulkDestroyFilter) match(model *data.ExperimentModel) bool {
+	if f.group != "" && model.GroupUid != f.group {
+		return false
+	}
+	for key, value := range f.labels {
+		if actual, ok := model.Labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	if !f.createdBefore.IsZero() {
+		created, err := time.Parse(time.RFC3339Nano, model.CreateTime)
+		if err != nil || !created.Before(f.createdBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+// selectExperiments queries the experiments of the filter order by creation
+func (f *bulkDestroyFilter) selectExperiments(source data.ExperimentSource) ([]*data.ExperimentModel, error) {
+	models := make([]*data.ExperimentModel, 0)
+	for _, status := range f.statuses {
+		records, err := source.QueryExperimentModels(f.target, "", "", status, "", true)
+		if err != nil {
+			return nil, spec.ResponseFailWithFlags(spec.DbQueryFailed, "experiments", err)
+		}
+		for _, model := range records {
+			if f.match(model) {
+				models = append(models, model)
+			}
+		}
+	}
+	return models, nil
+}
+
+// runDestroyBulk destroys the selected experiments in parallel, and prints the result of every experiment.
+// It fails if any experiment fails to destroy.
+func (dc *DestroyCommand) runDestroyBulk(cmd *cobra.Command) error {
+	if dc.parallelism < 1 {
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, ParallelismFlag, dc.parallelism, "must be positive")
+	}
+	filter, err := dc.newBulkDestroyFilter(time.Now())
+	if err != nil {
+		return err
+	}
+	models, err := filter.selectExperiments(GetDS())
+	if err != nil {
+		return err
+	}
+	if dc.dryRun {
+		return dc.dryRunDestroy(cmd, models)
+	}
+	log.Infof(context.Background(), "destroy %d experiments in bulk, parallelism: %d, force-remove: %t",
+		len(models), dc.parallelism, dc.forceRemove)
+	results := dc.destroyExperimentsInParallel(models, dc.parallelism)
+	failed := 0
+	for _, result := range results {
+		if !result.Success {
+			failed++
+		}
+	}
+	response := spec.ReturnSuccess(results)
+	if failed > 0 {
+		response = &spec.Response{
+			Code:    spec.OsCmdExecFailed.Code,
+			Success: false,
+			Err:     fmt.Sprintf("destroy %d of %d experiments failed", failed, len(results)),
+			Result:  results,
+		}
+	}
+	if err := printResponse(cmd, response, func() {
+		(&outputPrinter{format: OutputTable}).print(cmd.OutOrStdout(), response)
+	}); err != nil {
+		return err
+	}
+	if failed > 0 {
+		// the results have been printed, only the exit code is kept
+		return fmt.Errorf("%s", response.Err)
+	}
+	return nil
+}
+
+// destroyExperimentsInParallel destroys the experiments by at most parallelism goroutines,
+// the results are in the order of the experiments
+func (dc *DestroyCommand) destroyExperimentsInParallel(models []*data.ExperimentModel, parallelism int) []*destroyResult {
+	results := make([]*destroyResult, len(models))
+	semaphore := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for idx, model := range models {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(idx int, model *data.ExperimentModel) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			result := dc.destroyAndForceRemoveExperiment(model)
+			results[idx] = result
+		}(idx, model)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+	"github.com/chaosblade-io/chaosblade/exec/cloud"
+	"github.com/chaosblade-io/chaosblade/exec/cplus"
+	"github.com/chaosblade-io/chaosblade/exec/middleware"
+	"github.com/chaosblade-io/chaosblade/exec/os"
+)
+
+const AllOrphansFlag = "all-orphans"
+
+// The kinds of the orphans
+const (
+	orphanProcess = "process"
+	orphanSandbox = "sandbox"
+	orphanProxy   = "cplus"
+)
+
+// hangProcessExecutors create the executors of the hangProcessBins
+var hangProcessExecutors = map[string]func() spec.Executor{
+	"os":         os.NewExecutor,
+	"middleware": middleware.NewExecutor,
+	"cloud":      cloud.NewExecutor,
+}
+
+// orphan is a running fault which is not tracked by a Created or Success experiment or a Running preparation,
+// the Id is the pid of the process or the port of the sandbox and proxy
+type orphan struct {
+	Kind    string `json:"kind"`
+	Id      string `json:"id"`
+	Uid     string `json:"uid,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+
+	executor spec.Executor
+	expModel *spec.ExpModel
+}
+
+// orphanFinder discovers and tears down the orphans
+type orphanFinder struct {
+	processes processLister
+	sandbox   sandboxClient
+	proxy     proxyClient
+}
+
+func newOrphanFinder() *orphanFinder {
+	return &orphanFinder{
+		processes: localProcesses{},
+		sandbox:   jvmSandboxes{},
+		proxy:     cplusProxies{},
+	}
+}
+
+// runDestroyOrphans tears down the orphans, which are left if the records are lost
+func (dc *DestroyCommand) runDestroyOrphans(cmd *cobra.Command) error {
+	finder := newOrphanFinder()
+	orphans, err := finder.find(GetDS())
+	if err != nil {
+		return err
+	}
+	if dc.dryRun {
+		return printDryRun(cmd, orphans)
+	}
+	var failed *orphan
+	for _, o := range orphans {
+		finder.tearDown(o)
+		if !o.Success && failed == nil {
+			failed = o
+		}
+	}
+	if failed != nil {
+		return &spec.Response{
+			Code:    spec.OsCmdExecFailed.Code,
+			Success: false,
+			Err:     fmt.Sprintf("destroy the orphaned %s %s failed, %s", failed.Kind, failed.Id, failed.Error),
+			Result:  orphans,
+		}
+	}
+	cmd.Println(spec.ReturnSuccess(orphans).Print())
+	return nil
+}
+
+// find returns the chaos processes, attached sandboxes and cplus proxies which are not tracked by the records
+func (f *orphanFinder) find(source data.SourceI) ([]*orphan, error) {
+	trackedUids := make(map[string]bool)
+	for _, status := range []string{Created, Success} {
+		models, err := source.QueryExperimentModels("", "", "", status, "", true)
+		if err != nil {
+			return nil, spec.ResponseFailWithFlags(spec.DbQueryFailed, "experiments", err)
+		}
+		for _, model := range models {
+			trackedUids[model.Uid] = true
+		}
+	}
+	records, err := source.QueryPreparationRecords("", Running, "", "", "", true)
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.DbQueryFailed, "preparations", err)
+	}
+	trackedPorts := make(map[string]bool)
+	for _, record := range records {
+		trackedPorts[record.ProgramType+"/"+record.Port] = true
+	}
+
+	orphans := make([]*orphan, 0)
+	names := make([]string, 0, len(hangProcessBins))
+	for name := range hangProcessBins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		bin := hangProcessBins[name]
+		processes, err := f.processes.processes(bin, fmt.Sprintf(" %s ", spec.Create))
+		if err != nil {
+			return nil, spec.ResponseFailWithFlags(spec.ProcessIdByNameFailed, bin, err)
+		}
+		for _, proc := range processes {
+			uid, expModel := parseChaosProcessArgs(proc.args)
+			if expModel == nil || trackedUids[uid] {
+				continue
+			}
+			orphans = append(orphans, &orphan{
+				Kind:     orphanProcess,
+				Id:       proc.pid,
+				Uid:      uid,
+				Target:   fmt.Sprintf("%s %s %s", bin, expModel.Target, expModel.ActionName),
+				executor: hangProcessExecutors[name](),
+				expModel: expModel,
+			})
+		}
+	}
+	for _, port := range f.sandbox.ports() {
+		if trackedPorts["jvm/"+port] || !f.sandbox.check(port).Success {
+			continue
+		}
+		orphans = append(orphans, &orphan{Kind: orphanSandbox, Id: port, Target: "jvm"})
+	}
+	ports, err := f.proxy.ports()
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.ProcessIdByNameFailed, cplus.ApplicationName, err)
+	}
+	for _, port := range ports {
+		if trackedPorts["cplus/"+port] {
+			continue
+		}
+		orphans = append(orphans, &orphan{Kind: orphanProxy, Id: port, Target: "cplus"})
+	}
+	return orphans, nil
+}
+
+// tearDown destroys the orphan and records the result in it
+func (f *orphanFinder) tearDown(o *orphan) {
+	var response *spec.Response
+	switch o.Kind {
+	case orphanProcess:
+		// destroy by the matchers of the process, the same as blade destroy TARGET ACTION --uid UID
+		ctx := spec.SetDestroyFlag(context.Background(), o.Uid)
+		response = o.executor.Exec(o.Uid, context.WithValue(ctx, spec.Uid, o.Uid), o.expModel)
+	case orphanSandbox:
+		response = f.sandbox.detach(o.Id)
+	case orphanProxy:
+		response = f.proxy.revoke(o.Id)
+	}
+	if response == nil || !response.Success {
+		o.Error = "unknown orphan"
+		if response != nil {
+			o.Error = response.Err
+		}
+		log.Warnf(context.Background(), "destroy the orphaned %s %s failed, %s", o.Kind, o.Id, o.Error)
+		return
+	}
+	o.Success = true
+}
+
+// parseChaosProcessArgs returns the uid and experiment model of the process started by
+// chaos_os create TARGET ACTION --uid=UID --FLAG=VALUE, the model is nil if the args do not match
+func parseChaosProcessArgs(args []string) (string, *spec.ExpModel) {
+	for idx, arg := range args {
+		if arg != spec.Create || idx+2 >= len(args) {
+			continue
+		}
+		expModel := &spec.ExpModel{
+			Target:      args[idx+1],
+			ActionName:  args[idx+2],
+			ActionFlags: make(map[string]string),
+		}
+		for _, flag := range args[idx+3:] {
+			name, value, found := strings.Cut(strings.TrimPrefix(flag, "--"), "=")
+			if !strings.HasPrefix(flag, "--") || name == "" {
+				continue
+			}
+			if !found {
+				value = "true"
+			}
+			expModel.ActionFlags[name] = value
+		}
+		uid := expModel.ActionFlags[UidFlag]
+		if uid == "" {
+			return "", nil
+		}
+		delete(expModel.ActionFlags, UidFlag)
+		return uid, expModel
+	}
+	return "", nil
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+func Test_parseChaosProcessArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedUid   string
+		expectedModel *spec.ExpModel
+	}{
+		{
+			name:        "create",
+			args:        []string{"/opt/chaosblade/bin/chaos_os", "create", "cpu", "fullload", "--uid=a1b2c3", "--cpu-percent=60"},
+			expectedUid: "a1b2c3",
+			expectedModel: &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+				ActionFlags: map[string]string{"cpu-percent": "60"}},
+		},
+		{
+			name: "destroy",
+			args: []string{"/opt/chaosblade/bin/chaos_os", "destroy", "cpu", "fullload", "--uid=a1b2c3"},
+		},
+		{
+			name: "without uid",
+			args: []string{"/opt/chaosblade/bin/chaos_os", "create", "cpu", "fullload"},
+		},
+	}
+	for _, tt := range tests {
+		uid, model := parseChaosProcessArgs(tt.args)
+		if uid != tt.expectedUid || !reflect.DeepEqual(model, tt.expectedModel) {
+			t.Errorf("%s: unexpected uid %s, model %+v", tt.name, uid, model)
+		}
+	}
+}
+
+func Test_orphanFinder_find(t *testing.T) {
+	src := data.NewMemorySource()
+	if err := src.InsertExperimentModel(&data.ExperimentModel{
+		Uid: "tracked", Command: "cpu", SubCommand: "fullload", Status: Success,
+	}); err != nil {
+		t.Fatalf("insert experiment failed, %v", err)
+	}
+	if err := src.InsertPreparationRecord(&data.PreparationRecord{
+		Uid: "jvm", ProgramType: "jvm", Port: "51001", Status: Running,
+	}); err != nil {
+		t.Fatalf("insert preparation failed, %v", err)
+	}
+	finder := &orphanFinder{
+		processes: testProcesses{
+			spec.ChaosOsBin: {
+				{pid: "101", args: []string{spec.ChaosOsBin, "create", "cpu", "fullload", "--uid=tracked"}},
+				{pid: "102", args: []string{spec.ChaosOsBin, "create", "mem", "load", "--uid=lost"}},
+				{pid: "103", args: []string{spec.ChaosOsBin, "destroy", "mem", "load", "--uid=lost"}},
+			},
+		},
+		sandbox: testSandboxes{attached: []string{"51001", "51002", "51003"}, dead: map[string]bool{"51003": true}},
+		proxy:   testProxies{"9526"},
+	}
+	orphans, err := finder.find(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := make([]string, 0)
+	for _, o := range orphans {
+		found = append(found, o.Kind+":"+o.Id+":"+o.Uid)
+	}
+	expected := []string{"process:102:lost", "sandbox:51002:", "cplus:9526:"}
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("unexpected orphans %v, expected %v", found, expected)
+	}
+}
@@ -19,8 +19,13 @@ package cmd
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
 
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
 )
 
 func Test_convertCommandModel(t *testing.T) {
@@ -64,3 +69,133 @@ func Test_convertCommandModel(t *testing.T) {
 		}
 	}
 }
+
+type groupMockSource struct {
+	MockSource
+	models []*data.ExperimentModel
+}
+
+func (s *groupMockSource) QueryExperimentModelsByGroup(groupUid string) ([]*data.ExperimentModel, error) {
+	return s.models, nil
+}
+
+func TestDestroyCommand_runDestroyWithGroup(t *testing.T) {
+	defer SetDS(&MockSource{})
+	dc := &DestroyCommand{}
+	dc.Init()
+	dc.group = "gameday-1"
+
+	SetDS(&groupMockSource{})
+	err := dc.runDestroyWithGroup(&cobra.Command{})
+	if response, ok := err.(*spec.Response); !ok || response.Code != spec.DataNotFound.Code {
+		t.Errorf("unexpected result: %v", err)
+	}
+
+	SetDS(&groupMockSource{models: []*data.ExperimentModel{
+		{Uid: "7c1f7afc281482c8", Command: "unknown", SubCommand: "action", Status: Success},
+		{Uid: "9b2e3c4d5f6a7b8c", Command: "unknown", SubCommand: "action", Status: Destroyed},
+	}})
+	err = dc.runDestroyWithGroup(&cobra.Command{})
+	response, ok := err.(*spec.Response)
+	if !ok || response.Success {
+		t.Fatalf("unexpected result: %v", err)
+	}
+	results := response.Result.([]*destroyResult)
+	if len(results) != 1 || results[0].Uid != "7c1f7afc281482c8" || results[0].Success {
+		t.Errorf("unexpected destroy results: %+v", results)
+	}
+}
+
+func Test_bulkDestroyFilter_selectExperiments(t *testing.T) {
+	now := time.Now()
+	src := data.NewMemorySource()
+	for _, model := range []*data.ExperimentModel{
+		{Uid: "cpu-old", Command: "cpu", SubCommand: "fullload", Status: Success, GroupUid: "gameday-1",
+			Labels: map[string]string{"team": "sre"}},
+		{Uid: "network-old", Command: "network", SubCommand: "delay", Status: Success,
+			Labels: map[string]string{"team": "sre"}},
+		{Uid: "network-created", Command: "network", SubCommand: "loss", Status: Created},
+		{Uid: "network-error", Command: "network", SubCommand: "loss", Status: Error},
+		{Uid: "network-destroyed", Command: "network", SubCommand: "loss", Status: Destroyed},
+	} {
+		model.CreateTime = now.Add(-30 * time.Minute).Format(time.RFC3339Nano)
+		if err := src.InsertExperimentModel(model); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+	}
+	tests := []struct {
+		name     string
+		dc       *DestroyCommand
+		now      time.Time
+		expected []string
+	}{
+		{"all", &DestroyCommand{all: true}, now, []string{"network-created", "cpu-old", "network-old"}},
+		{"status and target", &DestroyCommand{status: "success", expTarget: "network"}, now, []string{"network-old"}},
+		{"error", &DestroyCommand{status: "Error"}, now, []string{"network-error"}},
+		{"group", &DestroyCommand{all: true, group: "gameday-1"}, now, []string{"cpu-old"}},
+		{"label", &DestroyCommand{labels: []string{"team=sre"}}, now, []string{"cpu-old", "network-old"}},
+		{"older than", &DestroyCommand{olderThan: "1h"}, now, []string{}},
+		{"older than in the future", &DestroyCommand{olderThan: "1h"}, now.Add(2 * time.Hour),
+			[]string{"network-created", "cpu-old", "network-old"}},
+	}
+	for _, tt := range tests {
+		if !tt.dc.isBulk(nil) {
+			t.Errorf("%s: expected bulk destroying", tt.name)
+		}
+		filter, err := tt.dc.newBulkDestroyFilter(tt.now)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		models, err := filter.selectExperiments(src)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		uids := make([]string, 0)
+		for _, model := range models {
+			uids = append(uids, model.Uid)
+		}
+		if !reflect.DeepEqual(uids, tt.expected) {
+			t.Errorf("%s: unexpected experiments %v, expected %v", tt.name, uids, tt.expected)
+		}
+	}
+	if _, err := (&DestroyCommand{status: "Destroyed"}).newBulkDestroyFilter(now); err == nil {
+		t.Errorf("expected the error of the Destroyed status")
+	}
+}
+
+func TestDestroyCommand_destroyExperimentsInParallel(t *testing.T) {
+	defer SetDS(&MockSource{})
+	SetDS(data.NewMemorySource())
+	dc := &DestroyCommand{}
+	dc.Init()
+	models := make([]*data.ExperimentModel, 0)
+	for _, uid := range []string{"uid1", "uid2", "uid3", "uid4", "uid5"} {
+		models = append(models, &data.ExperimentModel{Uid: uid, Command: "unknown", SubCommand: "action", Status: Success})
+	}
+	results := dc.destroyExperimentsInParallel(models, 2)
+	if len(results) != len(models) {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	for idx, result := range results {
+		if result.Uid != models[idx].Uid || result.Success || result.Error == "" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	}
+
+	// the records are forcibly removed even if the destroying fails
+	dc.forceRemove = true
+	for _, model := range models {
+		if err := GetDS().InsertExperimentModel(model); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+	}
+	results = dc.destroyExperimentsInParallel(models, 2)
+	for idx, result := range results {
+		if result.Uid != models[idx].Uid || result.Success || result.Error == "" || result.RemoveError != "" {
+			t.Errorf("unexpected result of force-remove: %+v", result)
+		}
+		if model, _ := GetDS().QueryExperimentModelByUid(result.Uid); model != nil {
+			t.Errorf("the %s record is not removed", result.Uid)
+		}
+	}
+}
@@ -0,0 +1,452 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/process"
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/channel"
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+	"github.com/chaosblade-io/chaosblade/exec/cplus"
+	"github.com/chaosblade-io/chaosblade/exec/jvm"
+)
+
+// The liveness states of the records
+const (
+	doctorAlive     = "alive"
+	doctorDrifted   = "drifted"
+	doctorUnknown   = "unknown"
+	doctorUnchecked = "unchecked"
+)
+
+// The checks of the liveness
+const (
+	doctorCheckProcess = "process"
+	doctorCheckK8s     = "k8s"
+	doctorCheckJvm     = "jvm"
+	doctorCheckCPlus   = "cplus"
+	doctorCheckSandbox = "sandbox"
+)
+
+// hangProcessBins are the binaries of the executors which keep a process running for the experiment
+var hangProcessBins = map[string]string{
+	"os":         spec.ChaosOsBin,
+	"middleware": spec.ChaosMiddlewareBin,
+	"cloud":      spec.ChaosCloudBin,
+}
+
+// DoctorCommand verifies the Success experiments and Running preparations are alive
+type DoctorCommand struct {
+	baseCommand
+	fix     bool
+	destroy bool
+}
+
+// doctorFinding is the liveness of a record and the action taken for the drifted one
+type doctorFinding struct {
+	Kind   string `json:"kind"`
+	Uid    string `json:"uid"`
+	Target string `json:"target"`
+	Status string `json:"status"`
+	Check  string `json:"check,omitempty"`
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+	Action string `json:"action,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// doctorResult contains the findings of all checked records, the Drifted is the count of the drifted records
+// which are not fixed
+type doctorResult struct {
+	Checked  int              `json:"checked"`
+	Drifted  int              `json:"drifted"`
+	Findings []*doctorFinding `json:"findings"`
+}
+
+// executorResolver returns the executor name and the action spec of the experiment record
+type executorResolver interface {
+	resolveExecutor(model *data.ExperimentModel) (string, *expActionSpec)
+}
+
+// processLister finds the processes on the local host
+type processLister interface {
+	// processes returns the processes of the bin whose command line contains the keyword
+	processes(bin, keyword string) ([]*chaosProcess, error)
+	exists(pid int32) (bool, error)
+}
+
+// clusterClient queries the chaosblade resources of the k8s experiments
+type clusterClient interface {
+	experimentStatus(model *data.ExperimentModel) *spec.Response
+}
+
+// sandboxClient manages the jvm sandboxes attached to the java processes
+type sandboxClient interface {
+	ports() []string
+	check(port string) *spec.Response
+	experimentStatus(uid string) *spec.Response
+	detach(port string) *spec.Response
+}
+
+// proxyClient manages the cplus proxies
+type proxyClient interface {
+	ports() ([]string, error)
+	check(port string) *spec.Response
+	revoke(port string) *spec.Response
+}
+
+// chaosProcess is a running process of the chaos binaries
+type chaosProcess struct {
+	pid  string
+	args []string
+}
+
+// livenessChecker checks the records by the executors
+type livenessChecker struct {
+	resolver  executorResolver
+	processes processLister
+	cluster   clusterClient
+	sandbox   sandboxClient
+	proxy     proxyClient
+}
+
+func (dc *DoctorCommand) Init() {
+	dc.command = &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the running experiments and preparations are alive",
+		Long: "Check the Success experiments and Running preparations are alive by their executors, " +
+			"such as the chaos_os process, the jvm sandbox, the cplus proxy and the chaosblade resource, " +
+			"and report the drifted records. The drifted records can be marked as Error or destroyed again.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return dc.runDoctor(cmd)
+		},
+		Example: doctorExample(),
+	}
+	dc.command.Flags().BoolVar(&dc.fix, "fix", false, "Mark the drifted experiments and preparations as Error")
+	dc.command.Flags().BoolVar(&dc.destroy, "destroy", false, "Destroy the drifted experiments again to clean up the residues, the failed ones are marked as Error if --fix is set")
+}
+
+func (dc *DoctorCommand) runDoctor(cmd *cobra.Command) error {
+	destroyCommand := &DestroyCommand{}
+	destroyCommand.Init()
+	result, err := diagnoseRecords(destroyCommand, newLivenessChecker(destroyCommand), dc.fix, dc.destroy)
+	if err != nil {
+		return err
+	}
+	response := spec.ReturnSuccess(result)
+	if result.Drifted > 0 {
+		response = &spec.Response{
+			Code:    spec.UnexpectedStatus.Code,
+			Success: false,
+			Err:     fmt.Sprintf("%d records drifted", result.Drifted),
+			Result:  result,
+		}
+	}
+	if outputFormat == "" {
+		if !response.Success {
+			return response
+		}
+		cmd.Println(response.Print())
+		return nil
+	}
+	if err := printResponse(cmd, response, nil); err != nil {
+		return err
+	}
+	if !response.Success {
+		// the result has been printed in the format, only the exit code is kept
+		return fmt.Errorf("%s", response.Err)
+	}
+	return nil
+}
+
+func newLivenessChecker(dc *DestroyCommand) *livenessChecker {
+	return &livenessChecker{
+		resolver:  dc,
+		processes: localProcesses{},
+		cluster:   k8sCluster{},
+		sandbox:   jvmSandboxes{},
+		proxy:     cplusProxies{},
+	}
+}
+
+func (dc *DestroyCommand) resolveExecutor(model *data.ExperimentModel) (string, *expActionSpec) {
+	executor, _, err := dc.getExecutorAndExpModelByRecord(model)
+	if err != nil {
+		return "", nil
+	}
+	return executor.Name(), dc.getActionSpecByRecord(model)
+}
+
+// localProcesses lists the processes by the local channel
+type localProcesses struct{}
+
+func (localProcesses) processes(bin, keyword string) ([]*chaosProcess, error) {
+	ctx := context.WithValue(context.Background(), channel.ProcessKey, keyword)
+	pids, err := channel.NewLocalChannel().GetPidsByProcessName(bin, ctx)
+	if err != nil {
+		return nil, err
+	}
+	processes := make([]*chaosProcess, 0, len(pids))
+	for _, pid := range pids {
+		id, err := strconv.Atoi(pid)
+		if err != nil {
+			continue
+		}
+		proc, err := process.NewProcess(int32(id))
+		if err != nil {
+			continue
+		}
+		// the process exits after it is listed
+		args, err := proc.CmdlineSlice()
+		if err != nil {
+			continue
+		}
+		processes = append(processes, &chaosProcess{pid: pid, args: args})
+	}
+	return processes, nil
+}
+
+func (localProcesses) exists(pid int32) (bool, error) {
+	return process.PidExists(pid)
+}
+
+// k8sCluster queries the chaosblade resources by the kubeconfig of the experiments
+type k8sCluster struct{}
+
+func (k8sCluster) experimentStatus(model *data.ExperimentModel) *spec.Response {
+	return queryK8sStatus(model)
+}
+
+// jvmSandboxes calls the jvm sandboxes by their http ports
+type jvmSandboxes struct{}
+
+func (jvmSandboxes) ports() []string {
+	return jvm.SandboxPorts()
+}
+
+func (jvmSandboxes) check(port string) *spec.Response {
+	return jvm.CheckSandbox(context.Background(), port)
+}
+
+func (jvmSandboxes) experimentStatus(uid string) *spec.Response {
+	return jvm.NewExecutor().QueryStatus(context.WithValue(context.Background(), spec.Uid, uid))
+}
+
+func (jvmSandboxes) detach(port string) *spec.Response {
+	return jvm.Detach(context.Background(), port)
+}
+
+// cplusProxies calls the cplus proxies by their http ports
+type cplusProxies struct{}
+
+func (cplusProxies) ports() ([]string, error) {
+	return cplus.ProxyPorts(context.Background())
+}
+
+func (cplusProxies) check(port string) *spec.Response {
+	return cplus.Check(context.Background(), port)
+}
+
+func (cplusProxies) revoke(port string) *spec.Response {
+	return cplus.Revoke(context.Background(), port)
+}
+
+// diagnoseRecords checks the Success experiments and Running preparations. The drifted experiments are destroyed
+// if destroy is true, and the drifted records not destroyed are marked as Error if fix is true.
+func diagnoseRecords(dc *DestroyCommand, checker *livenessChecker, fix, destroy bool) (*doctorResult, error) {
+	models, err := GetDS().QueryExperimentModels("", "", "", Success, "", true)
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.DbQueryFailed, "experiments", err)
+	}
+	records, err := GetDS().QueryPreparationRecords("", Running, "", "", "", true)
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.DbQueryFailed, "preparations", err)
+	}
+	result := &doctorResult{Findings: make([]*doctorFinding, 0, len(models)+len(records))}
+	for _, model := range models {
+		finding := &doctorFinding{
+			Kind:   data.AuditKindExperiment,
+			Uid:    model.Uid,
+			Target: strings.TrimSpace(model.Command + " " + model.SubCommand),
+			Status: model.Status,
+		}
+		finding.Check, finding.State, finding.Reason = checker.checkExperiment(model)
+		if finding.State == doctorDrifted {
+			repairExperiment(dc, model, finding, fix, destroy)
+		}
+		result.add(finding)
+	}
+	for _, record := range records {
+		finding := &doctorFinding{
+			Kind:   data.AuditKindPreparation,
+			Uid:    record.Uid,
+			Target: record.ProgramType,
+			Status: record.Status,
+		}
+		finding.Check, finding.State, finding.Reason = checker.checkPreparation(record)
+		if finding.State == doctorDrifted && fix {
+			if err := GetDS().UpdatePreparationRecordByUid(record.Uid, Error,
+				fmt.Sprintf("the preparation is not alive, %s", finding.Reason)); err != nil {
+				finding.Error = err.Error()
+			} else {
+				finding.Action = "marked Error"
+			}
+		}
+		result.add(finding)
+	}
+	return result, nil
+}
+
+func (r *doctorResult) add(finding *doctorFinding) {
+	r.Findings = append(r.Findings, finding)
+	if finding.State != doctorUnchecked {
+		r.Checked++
+	}
+	if finding.State == doctorDrifted && finding.Action == "" {
+		r.Drifted++
+	}
+}
+
+// repairExperiment destroys the drifted experiment or marks it as Error, the record is only marked
+// if it is still Success
+func repairExperiment(dc *DestroyCommand, model *data.ExperimentModel, finding *doctorFinding, fix, destroy bool) {
+	ctx := context.WithValue(context.Background(), spec.Uid, model.Uid)
+	if destroy {
+		executor, expModel, err := dc.getExecutorAndExpModelByRecord(model)
+		if err == nil {
+			err = dc.destroyExperimentWithStatus(model.Uid, executor, expModel, Destroyed, "")
+		}
+		if err == nil {
+			finding.Action = "destroyed"
+			return
+		}
+		log.Warnf(ctx, "destroy the drifted experiment failed, %v", err)
+		finding.Error = fmt.Sprintf("destroy failed, %v", err)
+	}
+	if !fix {
+		return
+	}
+	if err := GetDS().UpdateExperimentStatusByUid(model.Uid, []string{Success}, Error,
+		fmt.Sprintf("the experiment is not alive, %s", finding.Reason)); err != nil {
+		finding.Error = strings.TrimPrefix(fmt.Sprintf("%s; mark Error failed, %v", finding.Error, err), "; ")
+		return
+	}
+	finding.Action = "marked Error"
+}
+
+// checkExperiment returns the check, state and reason of the experiment
+func (c *livenessChecker) checkExperiment(model *data.ExperimentModel) (string, string, string) {
+	executorName, actionSpec := c.resolver.resolveExecutor(model)
+	switch executorName {
+	case "":
+		return "", doctorUnchecked, "the executor is not found"
+	case "k8s":
+		response := c.cluster.experimentStatus(model)
+		if response.Success {
+			return doctorCheckK8s, doctorAlive, ""
+		}
+		// the cluster can not be accessed
+		if response.Code == spec.K8sExecFailed.Code && !strings.Contains(response.Err, "not found") {
+			return doctorCheckK8s, doctorUnknown, response.Err
+		}
+		return doctorCheckK8s, doctorDrifted, response.Err
+	case "jvm":
+		response := c.sandbox.experimentStatus(model.Uid)
+		if response.Success {
+			return doctorCheckJvm, doctorAlive, ""
+		}
+		return doctorCheckJvm, doctorDrifted, response.Err
+	case "cplus":
+		record, err := GetDS().QueryRunningPreByTypeAndProcess("cplus", recordFlags(model)["port"], "")
+		if err != nil {
+			return doctorCheckCPlus, doctorUnknown, err.Error()
+		}
+		if record == nil {
+			return doctorCheckCPlus, doctorDrifted, "the cplus preparation is not running"
+		}
+		if response := c.proxy.check(record.Port); !response.Success {
+			return doctorCheckCPlus, doctorDrifted, response.Err
+		}
+		return doctorCheckCPlus, doctorAlive, ""
+	}
+	bin, ok := hangProcessBins[executorName]
+	if !ok || actionSpec == nil || !actionSpec.ProcessHang() {
+		return "", doctorUnchecked, "the experiment has no process to check"
+	}
+	// the process is on the remote host
+	if recordFlags(model)["channel"] == "ssh" {
+		return "", doctorUnchecked, "the experiment is executed by ssh"
+	}
+	processes, err := c.processes.processes(bin, fmt.Sprintf("--uid=%s", model.Uid))
+	if err != nil {
+		return doctorCheckProcess, doctorUnknown, err.Error()
+	}
+	if len(processes) == 0 {
+		return doctorCheckProcess, doctorDrifted, fmt.Sprintf("the %s process is not found", bin)
+	}
+	return doctorCheckProcess, doctorAlive, ""
+}
+
+// checkPreparation returns the check, state and reason of the preparation
+func (c *livenessChecker) checkPreparation(record *data.PreparationRecord) (string, string, string) {
+	switch record.ProgramType {
+	case "jvm":
+		if pid, err := strconv.Atoi(record.Pid); err == nil && pid > 0 {
+			if exists, err := c.processes.exists(int32(pid)); err == nil && !exists {
+				return doctorCheckProcess, doctorDrifted, fmt.Sprintf("the java process %d is not found", pid)
+			}
+		}
+		if response := c.sandbox.check(record.Port); !response.Success {
+			return doctorCheckSandbox, doctorDrifted, response.Err
+		}
+		return doctorCheckSandbox, doctorAlive, ""
+	case "cplus":
+		if response := c.proxy.check(record.Port); !response.Success {
+			return doctorCheckCPlus, doctorDrifted, response.Err
+		}
+		return doctorCheckCPlus, doctorAlive, ""
+	}
+	return "", doctorUnchecked, "the preparation type is not supported"
+}
+
+// recordFlags returns the flags of the experiment record
+func recordFlags(model *data.ExperimentModel) map[string]string {
+	if model.Flags != nil {
+		return model.Flags
+	}
+	return data.ParseFlags(model.Flag)
+}
+
+func doctorExample() string {
+	return `# Report the experiments and preparations which are not alive
+blade doctor
+
+# Mark the drifted records as Error
+blade doctor --fix
+
+# Destroy the drifted experiments again, and mark the failed ones as Error
+blade doctor --destroy --fix`
+}
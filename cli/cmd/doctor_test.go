@@ -0,0 +1,221 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+// testResolver resolves the experiments by the command
+type testResolver struct{}
+
+func (testResolver) resolveExecutor(model *data.ExperimentModel) (string, *expActionSpec) {
+	switch model.Command {
+	case "cpu":
+		return "os", &expActionSpec{ExpActionCommandSpec: &spec.ActionModel{ActionProcessHang: true}}
+	case "network":
+		return "os", &expActionSpec{ExpActionCommandSpec: &spec.ActionModel{}}
+	case "k8s", "jvm":
+		return model.Command, nil
+	}
+	return "", nil
+}
+
+// testProcesses are the running processes of the bins
+type testProcesses map[string][]*chaosProcess
+
+func (p testProcesses) processes(bin, keyword string) ([]*chaosProcess, error) {
+	processes := make([]*chaosProcess, 0)
+	for _, proc := range p[bin] {
+		if strings.Contains(strings.Join(proc.args, " "), keyword) {
+			processes = append(processes, proc)
+		}
+	}
+	return processes, nil
+}
+
+func (p testProcesses) exists(pid int32) (bool, error) {
+	for _, processes := range p {
+		for _, proc := range processes {
+			if proc.pid == strconv.Itoa(int(pid)) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// testCluster is not accessible for the unreachable experiment, and the others are deleted
+type testCluster struct{}
+
+func (testCluster) experimentStatus(model *data.ExperimentModel) *spec.Response {
+	if model.Uid == "unreachable" {
+		return spec.ResponseFailWithFlags(spec.K8sExecFailed, "getClient", "connection refused")
+	}
+	return spec.ResponseFailWithFlags(spec.UnexpectedStatus, "running", "Destroyed")
+}
+
+// testSandboxes are the attached sandboxes, which are alive except the dead ones
+type testSandboxes struct {
+	attached []string
+	dead     map[string]bool
+}
+
+func (s testSandboxes) ports() []string {
+	return s.attached
+}
+
+func (s testSandboxes) check(port string) *spec.Response {
+	if s.dead[port] {
+		return spec.ResponseFailWithFlags(spec.HttpExecFailed, port, "connection refused")
+	}
+	return spec.ReturnSuccess(port)
+}
+
+func (s testSandboxes) experimentStatus(uid string) *spec.Response {
+	return spec.ReturnSuccess(uid)
+}
+
+func (s testSandboxes) detach(port string) *spec.Response {
+	return spec.ReturnSuccess(port)
+}
+
+// testProxies are the started proxies, which can not be accessed
+type testProxies []string
+
+func (p testProxies) ports() ([]string, error) {
+	return p, nil
+}
+
+func (p testProxies) check(port string) *spec.Response {
+	return spec.ResponseFailWithFlags(spec.HttpExecFailed, port, "connection refused")
+}
+
+func (p testProxies) revoke(port string) *spec.Response {
+	return spec.ReturnSuccess(port)
+}
+
+func newTestLivenessChecker() *livenessChecker {
+	return &livenessChecker{
+		resolver: testResolver{},
+		processes: testProcesses{
+			spec.ChaosOsBin: {{pid: "1024", args: []string{spec.ChaosOsBin, "create", "cpu", "fullload", "--uid=alive"}}},
+		},
+		cluster: testCluster{},
+		sandbox: testSandboxes{},
+		proxy:   testProxies{},
+	}
+}
+
+func Test_livenessChecker_checkExperiment(t *testing.T) {
+	checker := newTestLivenessChecker()
+	tests := []struct {
+		model         *data.ExperimentModel
+		expectedCheck string
+		expectedState string
+	}{
+		{&data.ExperimentModel{Uid: "alive", Command: "cpu", SubCommand: "fullload"}, doctorCheckProcess, doctorAlive},
+		{&data.ExperimentModel{Uid: "dead", Command: "cpu", SubCommand: "fullload"}, doctorCheckProcess, doctorDrifted},
+		{&data.ExperimentModel{Uid: "ssh", Command: "cpu", SubCommand: "fullload", Flag: "--channel=ssh"}, "", doctorUnchecked},
+		{&data.ExperimentModel{Uid: "nohang", Command: "network", SubCommand: "delay"}, "", doctorUnchecked},
+		{&data.ExperimentModel{Uid: "deleted", Command: "k8s", SubCommand: "pod-cpu fullload"}, doctorCheckK8s, doctorDrifted},
+		{&data.ExperimentModel{Uid: "unreachable", Command: "k8s", SubCommand: "pod-cpu fullload"}, doctorCheckK8s, doctorUnknown},
+		{&data.ExperimentModel{Uid: "jvm", Command: "jvm", SubCommand: "delay"}, doctorCheckJvm, doctorAlive},
+		{&data.ExperimentModel{Uid: "unknown", Command: "unknown", SubCommand: "action"}, "", doctorUnchecked},
+	}
+	for _, tt := range tests {
+		check, state, reason := checker.checkExperiment(tt.model)
+		if check != tt.expectedCheck || state != tt.expectedState {
+			t.Errorf("%s: unexpected check %s, state %s, reason %s", tt.model.Uid, check, state, reason)
+		}
+	}
+}
+
+func Test_livenessChecker_checkPreparation(t *testing.T) {
+	checker := newTestLivenessChecker()
+	tests := []struct {
+		record        *data.PreparationRecord
+		expectedCheck string
+		expectedState string
+	}{
+		{&data.PreparationRecord{Uid: "jvm", ProgramType: "jvm", Pid: "1024", Port: "8703"}, doctorCheckSandbox, doctorAlive},
+		{&data.PreparationRecord{Uid: "exited", ProgramType: "jvm", Pid: "2048", Port: "8703"}, doctorCheckProcess, doctorDrifted},
+		{&data.PreparationRecord{Uid: "cplus", ProgramType: "cplus", Port: "9526"}, doctorCheckCPlus, doctorDrifted},
+		{&data.PreparationRecord{Uid: "other", ProgramType: "other"}, "", doctorUnchecked},
+	}
+	for _, tt := range tests {
+		check, state, reason := checker.checkPreparation(tt.record)
+		if check != tt.expectedCheck || state != tt.expectedState {
+			t.Errorf("%s: unexpected check %s, state %s, reason %s", tt.record.Uid, check, state, reason)
+		}
+	}
+}
+
+func Test_diagnoseRecords(t *testing.T) {
+	tests := []struct {
+		name            string
+		fix             bool
+		expectedDrifted int
+		expectedStatus  string
+	}{
+		{"report", false, 2, Success},
+		{"fix", true, 0, Error},
+	}
+	for _, tt := range tests {
+		src := data.NewMemorySource()
+		for _, model := range []*data.ExperimentModel{
+			{Uid: "alive", Command: "cpu", SubCommand: "fullload", Status: Success},
+			{Uid: "dead", Command: "cpu", SubCommand: "fullload", Status: Success},
+			{Uid: "destroyed", Command: "cpu", SubCommand: "fullload", Status: Destroyed},
+		} {
+			if err := src.InsertExperimentModel(model); err != nil {
+				t.Fatalf("insert experiment failed, %v", err)
+			}
+		}
+		if err := src.InsertPreparationRecord(&data.PreparationRecord{
+			Uid: "cplus", ProgramType: "cplus", Port: "9526", Status: Running,
+		}); err != nil {
+			t.Fatalf("insert preparation failed, %v", err)
+		}
+		SetDS(src)
+		dc := &DestroyCommand{}
+		dc.Init()
+
+		result, err := diagnoseRecords(dc, newTestLivenessChecker(), tt.fix, false)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if result.Checked != 3 || result.Drifted != tt.expectedDrifted || len(result.Findings) != 3 {
+			t.Errorf("%s: unexpected result: %+v", tt.name, result)
+		}
+		model, _ := src.QueryExperimentModelByUid("dead")
+		record, _ := src.QueryPreparationByUid("cplus")
+		if model.Status != tt.expectedStatus || record.Status != map[bool]string{false: Running, true: Error}[tt.fix] {
+			t.Errorf("%s: unexpected statuses %s, %s", tt.name, model.Status, record.Status)
+		}
+		if alive, _ := src.QueryExperimentModelByUid("alive"); alive.Status != Success {
+			t.Errorf("%s: the alive experiment is changed to %s", tt.name, alive.Status)
+		}
+	}
+	SetDS(&MockSource{})
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+	"github.com/chaosblade-io/chaosblade/exec/kubernetes"
+)
+
+const DryRunFlag = "dry-run"
+
+// dryRunner is implemented by the executors which can render what would be executed without executing it
+type dryRunner interface {
+	DryRun(uid string, ctx context.Context, model *spec.ExpModel) *spec.Response
+}
+
+// dryRunResult shows what would be executed for an experiment, the execution is the command line, the
+// http request or the kubernetes resource rendered by the executor
+type dryRunResult struct {
+	Uid       string            `json:"uid"`
+	Command   string            `json:"command"`
+	Executor  string            `json:"executor,omitempty"`
+	Flags     map[string]string `json:"flags,omitempty"`
+	Execution interface{}       `json:"execution,omitempty"`
+	// Note explains why nothing is rendered
+	Note  string `json:"note,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// dryRunExperiment renders the creating or destroying execution of the experiment. The result is always returned,
+// the response is not nil if the executor fails to render it.
+func dryRunExperiment(uid, command string, executor spec.Executor, expModel *spec.ExpModel, destroy bool) (
+	*dryRunResult, *spec.Response,
+) {
+	result := &dryRunResult{Uid: uid, Command: command, Executor: executor.Name(), Flags: expModel.ActionFlags}
+	runner, ok := executor.(dryRunner)
+	if !ok {
+		result.Note = fmt.Sprintf("the execution of the %s executor can not be rendered", executor.Name())
+		return result, nil
+	}
+	ctx := context.WithValue(context.Background(), spec.Uid, uid)
+	if destroy {
+		ctx = spec.SetDestroyFlag(ctx, uid)
+	}
+	response := runner.DryRun(uid, ctx, expModel)
+	if !response.Success {
+		result.Error = response.Err
+		return result, response
+	}
+	result.Execution = response.Result
+	return result, nil
+}
+
+// printDryRun prints the dry-run results by the output format
+func printDryRun(cmd *cobra.Command, result interface{}) error {
+	response := spec.ReturnSuccess(result)
+	return printResponse(cmd, response, func() { cmd.Println(response.Print()) })
+}
+
+// runDryRun shows the uid and the execution of the experiment, which has passed the flag and policy checks.
+// Neither the record nor the experiment is created.
+func (cc *CreateCommand) runDryRun(cmd *cobra.Command, expModel *spec.ExpModel, executor spec.Executor) error {
+	uid := expModel.ActionFlags[UidFlag]
+	if uid == "" {
+		var err error
+		if uid, err = cc.generateUid(); err != nil {
+			return spec.ResponseFailWithFlags(spec.GenerateUidFailed, err)
+		}
+	}
+	command, subCommand := getCommandAndSubCommand(expModel)
+	result, response := dryRunExperiment(uid, command+" "+subCommand, executor, expModel, false)
+	if response != nil {
+		return response
+	}
+	return printDryRun(cmd, result)
+}
+
+// dryRunDestroy shows the executions of destroying the experiment records, without executing or updating them
+func (dc *DestroyCommand) dryRunDestroy(cmd *cobra.Command, models []*data.ExperimentModel) error {
+	results := make([]*dryRunResult, 0, len(models))
+	for _, model := range models {
+		result := &dryRunResult{Uid: model.Uid, Command: strings.TrimSpace(model.Command + " " + model.SubCommand)}
+		if model.Status == Destroyed || model.Status == Expired || model.Status == Aborted {
+			result.Note = fmt.Sprintf("the experiment is %s already", model.Status)
+			results = append(results, result)
+			continue
+		}
+		executor, expModel, err := dc.getExecutorAndExpModelByRecord(model)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result, _ = dryRunExperiment(model.Uid, result.Command, executor, expModel, true)
+		results = append(results, result)
+	}
+	return printDryRun(cmd, results)
+}
+
+// dryRunDestroyWithoutRecord shows the ChaosBlade resource which would be deleted from the cluster
+func (dc *DestroyCommand) dryRunDestroyWithoutRecord(cmd *cobra.Command, uid string) error {
+	result, response := dryRunExperiment(uid, "k8s", kubernetes.NewExecutor(), &spec.ExpModel{}, true)
+	if response != nil {
+		return response
+	}
+	return printDryRun(cmd, []*dryRunResult{result})
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+
+	"github.com/chaosblade-io/chaosblade/apis/chaosblade/v1alpha1"
+	"github.com/chaosblade-io/chaosblade/data"
+	"github.com/chaosblade-io/chaosblade/exec/docker"
+	"github.com/chaosblade-io/chaosblade/exec/kubernetes"
+	"github.com/chaosblade-io/chaosblade/exec/os"
+)
+
+func Test_dryRunExperiment(t *testing.T) {
+	chaosOsBin := path.Join(util.GetProgramPath(), "bin", spec.ChaosOsBin)
+	tests := []struct {
+		name     string
+		executor spec.Executor
+		model    *spec.ExpModel
+		destroy  bool
+		expected []string
+	}{
+		{"create", os.NewExecutor(), &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+			ActionFlags: map[string]string{"cpu-percent": "60", "cpu-count": "2", "timeout": "60", "debug": ""}}, false,
+			[]string{chaosOsBin, spec.Create, "cpu", "fullload", "--uid=dryrun", "--cpu-count=2", "--cpu-percent=60"}},
+		{"destroy", os.NewExecutor(), &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+			ActionFlags: map[string]string{"cpu-percent": "60"}}, true,
+			[]string{chaosOsBin, spec.Destroy, "cpu", "fullload", "--uid=dryrun", "--cpu-percent=60"}},
+		{"unsupported", docker.NewExecutor(), &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+			ActionFlags: map[string]string{}}, false, nil},
+	}
+	for _, tt := range tests {
+		result, response := dryRunExperiment("dryrun", "cpu fullload", tt.executor, tt.model, tt.destroy)
+		if response != nil {
+			t.Fatalf("%s: unexpected response %v", tt.name, response)
+		}
+		if tt.expected == nil {
+			if result.Execution != nil || result.Note == "" {
+				t.Errorf("%s: unexpected result %+v", tt.name, result)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(result.Execution, tt.expected) {
+			t.Errorf("%s: unexpected execution %v, expected %v", tt.name, result.Execution, tt.expected)
+		}
+	}
+}
+
+func Test_dryRunExperiment_kubernetes(t *testing.T) {
+	model := &spec.ExpModel{Target: "cpu", Scope: "pod", ActionName: "fullload", ActionProcessHang: true,
+		ActionFlags: map[string]string{"namespace": "default", "names": "a,b"}}
+	result, response := dryRunExperiment("dryrun", "k8s pod-cpu fullload", kubernetes.NewExecutor(), model, false)
+	if response != nil {
+		t.Fatalf("unexpected response %v", response)
+	}
+	chaosBlade, ok := result.Execution.(v1alpha1.ChaosBlade)
+	if !ok || chaosBlade.Name != "dryrun" || len(chaosBlade.Spec.Experiments) != 1 {
+		t.Fatalf("unexpected execution %+v", result.Execution)
+	}
+	if matchers := chaosBlade.Spec.Experiments[0].Matchers; len(matchers) != 3 {
+		t.Errorf("unexpected matchers %+v", matchers)
+	}
+	if _, ok := model.ActionFlags["cgroup-root"]; ok {
+		t.Errorf("the flags of the experiment are changed, %v", model.ActionFlags)
+	}
+}
+
+func Test_DestroyCommand_dryRunDestroy(t *testing.T) {
+	src := data.NewMemorySource()
+	for _, model := range []*data.ExperimentModel{
+		{Uid: "running", Command: "cpu", SubCommand: "fullload", Flag: "--cpu-percent=60", Status: Success},
+		{Uid: "destroyed", Command: "cpu", SubCommand: "fullload", Status: Destroyed},
+		{Uid: "unknown", Command: "unknown", SubCommand: "action", Status: Success},
+	} {
+		if err := src.InsertExperimentModel(model); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+	}
+	SetDS(src)
+	defer SetDS(&MockSource{})
+	dc := &DestroyCommand{dryRun: true}
+	dc.Init()
+	dc.executors[createExecutorKey("cpu", "", "fullload")] = os.NewExecutor()
+
+	var out bytes.Buffer
+	dc.command.SetOut(&out)
+
+	models, _ := src.QueryExperimentModels("", "", "", "", "", true)
+	if err := dc.dryRunDestroy(dc.command, models); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{"--uid=running", "Destroyed already", "can't find executor for unknown"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("the output does not contain %s, %s", expected, out.String())
+		}
+	}
+	for _, model := range models {
+		record, _ := src.QueryExperimentModelByUid(model.Uid)
+		if record.Status != model.Status {
+			t.Errorf("the status of %s experiment is changed to %s", model.Uid, record.Status)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+const (
+	// EventSinksFileEnv overrides the path of the event sinks file
+	EventSinksFileEnv     = "CHAOSBLADE_EVENT_SINKS_FILE"
+	defaultEventSinksFile = "/etc/chaosblade/event-sinks.yaml"
+
+	defaultEventTimeout     = 5 * time.Second
+	defaultEventMaxAttempts = 10
+)
+
+// eventSinksConfig is the file of the sinks which receive the lifecycle events, for example:
+//
+//	sinks:
+//	- name: ops
+//	  url: https://hooks.example.com/chaosblade
+//	  secret: 7b0e4c1d
+//	  events: ["experiment.*", "preparation.attached"]
+//	  timeout: 5s
+//	  maxAttempts: 10
+//	- name: pipeline
+//	  format: cloudevents
+//	  url: file:///var/spool/chaosblade/events.jsonl
+type eventSinksConfig struct {
+	Sinks []*eventSink `yaml:"sinks"`
+}
+
+// The formats of the event sinks
+const (
+	eventFormatWebhook     = "webhook"
+	eventFormatCloudEvents = "cloudevents"
+)
+
+// eventSink receives the events by POST requests, or the cloudevents sink also by a spool file or unix socket
+type eventSink struct {
+	// Name identifies the deliveries of the sink in the outbox, so it must not be changed while delivering
+	Name string `yaml:"name"`
+	// Format is webhook or cloudevents, webhook by default
+	Format string `yaml:"format"`
+	// Url is http or https, the cloudevents sink also supports file:///PATH and unix:///PATH
+	Url string `yaml:"url"`
+	// Mode is the content mode of the cloudevents over http, structured or binary, structured by default
+	Mode string `yaml:"mode"`
+	// Source is the source attribute of the cloudevents, /chaosblade/HOSTNAME by default
+	Source string `yaml:"source"`
+	// Secret signs the requests by HMAC-SHA256, the requests are not signed if it is empty
+	Secret string `yaml:"secret"`
+	// Events are the patterns of the event types, such as experiment.*, all events are received if it is empty
+	Events []string `yaml:"events"`
+	// Timeout is the timeout of a request, 5s by default
+	Timeout string `yaml:"timeout"`
+	// MaxAttempts is the attempts before the delivery is marked as Failed, 10 by default
+	MaxAttempts int `yaml:"maxAttempts"`
+
+	timeout  time.Duration
+	endpoint *url.URL
+}
+
+// getEventSinksFile returns the path of the event sinks file
+func getEventSinksFile() string {
+	if file := os.Getenv(EventSinksFileEnv); file != "" {
+		return file
+	}
+	return defaultEventSinksFile
+}
+
+// loadEventSinks reads the sinks file, it returns no sink if the file does not exist
+func loadEventSinks(file string) ([]*eventSink, error) {
+	bytes, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	config := &eventSinksConfig{}
+	if err := yaml.UnmarshalStrict(bytes, config); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, sink := range config.Sinks {
+		if sink.Name == "" || names[sink.Name] {
+			return nil, fmt.Errorf("the sink name %q is empty or duplicated", sink.Name)
+		}
+		names[sink.Name] = true
+		if err := sink.validate(); err != nil {
+			return nil, fmt.Errorf("the %s sink is invalid, %v", sink.Name, err)
+		}
+	}
+	return config.Sinks, nil
+}
+
+// validate checks the sink and sets the defaults
+func (s *eventSink) validate() error {
+	var err error
+	if s.endpoint, err = url.Parse(s.Url); err != nil {
+		return err
+	}
+	if s.Format == "" {
+		s.Format = eventFormatWebhook
+	}
+	switch s.Format {
+	case eventFormatWebhook:
+		if s.endpoint.Scheme != "http" && s.endpoint.Scheme != "https" {
+			return fmt.Errorf("the url %s is not http or https", s.Url)
+		}
+	case eventFormatCloudEvents:
+		if err := s.validateCloudEvents(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("the format %s is not webhook or cloudevents", s.Format)
+	}
+	s.timeout = defaultEventTimeout
+	if s.Timeout != "" {
+		if s.timeout, err = time.ParseDuration(s.Timeout); err != nil || s.timeout <= 0 {
+			return fmt.Errorf("the timeout %s is not a positive duration", s.Timeout)
+		}
+	}
+	if s.MaxAttempts == 0 {
+		s.MaxAttempts = defaultEventMaxAttempts
+	}
+	if s.MaxAttempts < 0 {
+		return fmt.Errorf("the maxAttempts %d is negative", s.MaxAttempts)
+	}
+	for _, pattern := range s.Events {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("the event pattern %s is illegal, %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// accept returns true if the sink receives the event type
+func (s *eventSink) accept(eventType string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, pattern := range s.Events {
+		if matched, _ := path.Match(pattern, eventType); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// eventRouter routes the events to the configured sinks, and starts the deliverer after the deliveries are stored
+// if it is not running. The deliverer exits when the outbox is drained, so it is started again if the deliveries
+// are stored while it is running, which may be after it finds no Pending delivery.
+type eventRouter struct {
+	sinks []*eventSink
+	// launcher starts the deliverer
+	launcher processLauncher
+	lock     sync.Mutex
+	running  bool
+	// notified is true if the deliveries are stored while the deliverer is running
+	notified bool
+}
+
+// newEventRouter returns nil if no sink is configured, the invalid sinks file is logged and publishes no event
+func newEventRouter() data.EventRouter {
+	file := getEventSinksFile()
+	sinks, err := loadEventSinks(file)
+	if err != nil {
+		log.Warnf(context.Background(), "the event sinks file %s is invalid, no event is published, %v", file, err)
+		return nil
+	}
+	if len(sinks) == 0 {
+		return nil
+	}
+	return &eventRouter{sinks: sinks, launcher: nohupLauncher{}}
+}
+
+func (r *eventRouter) Route(event *data.LifecycleEvent) []string {
+	names := make([]string, 0, len(r.sinks))
+	for _, sink := range r.sinks {
+		if sink.accept(event.Type) {
+			names = append(names, sink.Name)
+		}
+	}
+	return names
+}
+
+func (r *eventRouter) Notify(deliveries []*data.EventDelivery) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.running {
+		r.notified = true
+		return
+	}
+	r.start()
+}
+
+// start starts the deliverer and starts it again after it exits if the deliveries are notified, the lock is held
+func (r *eventRouter) start() {
+	wait, err := startEventDeliverer(r.launcher)
+	if err != nil {
+		log.Warnf(context.Background(), "start the event deliverer failed, %v", err)
+		return
+	}
+	r.running, r.notified = true, false
+	go func() {
+		if err := wait(); err != nil {
+			log.Warnf(context.Background(), "the event deliverer exits, %v", err)
+		}
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		r.running = false
+		if r.notified {
+			r.start()
+		}
+	}()
+}
+
+// startEventDeliverer starts a background process which delivers the pending events until the outbox is drained,
+// the process keeps running after the blade process exits
+func startEventDeliverer(launcher processLauncher) (func() error, error) {
+	return launcher.launch(nil, "event", "deliver", "--"+WatchFlag)
+}
+
+// EventCommand manages the outbox of the lifecycle events
+type EventCommand struct {
+	baseCommand
+}
+
+func (ec *EventCommand) Init() {
+	ec.command = &cobra.Command{
+		Use:   "event",
+		Short: "Manage the lifecycle events",
+		Long: "Manage the lifecycle events, which are published to the sinks configured in the " + defaultEventSinksFile +
+			" file or the " + EventSinksFileEnv + " environment variable. The events are stored in the outbox " +
+			"before delivering and retried with backoff, the deliveries failed after the max attempts are kept " +
+			"until they are redelivered.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return spec.ResponseFailWithFlags(spec.CommandIllegal, "less list, deliver or redeliver command")
+		},
+		Example: eventExample(),
+	}
+}
+
+func eventExample() string {
+	return `# List the deliveries which are failed after the max attempts
+blade event list --status Failed
+
+# Deliver the failed deliveries again
+blade event redeliver --failed`
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsContentType = "application/cloudevents+json"
+	cloudEventTypePrefix   = "io.chaosblade."
+
+	// The content modes of the cloudevents over http
+	cloudEventsStructured = "structured"
+	cloudEventsBinary     = "binary"
+)
+
+// cloudEvent is a CloudEvents 1.0 event in the JSON format. The partitionkey extension is the uid, so the events
+// of an experiment are kept in order when the spool is shipped to kafka.
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	Id              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            string          `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	PartitionKey    string          `json:"partitionkey,omitempty"`
+	Data            *cloudEventData `json:"data,omitempty"`
+}
+
+// cloudEventData is the data of the lifecycle event, the target of the preparation is the program type
+type cloudEventData struct {
+	Uid            string            `json:"uid"`
+	Kind           string            `json:"kind"`
+	Target         string            `json:"target"`
+	Scope          string            `json:"scope,omitempty"`
+	Action         string            `json:"action,omitempty"`
+	Flags          map[string]string `json:"flags,omitempty"`
+	Group          string            `json:"group,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	Status         string            `json:"status"`
+	PreviousStatus string            `json:"previousStatus,omitempty"`
+	Code           int32             `json:"code,omitempty"`
+	Error          string            `json:"error,omitempty"`
+}
+
+// validateCloudEvents checks the url and mode of the cloudevents sink and sets the defaults
+func (s *eventSink) validateCloudEvents() error {
+	switch s.endpoint.Scheme {
+	case "http", "https":
+		if s.Mode == "" {
+			s.Mode = cloudEventsStructured
+		}
+		if s.Mode != cloudEventsStructured && s.Mode != cloudEventsBinary {
+			return fmt.Errorf("the mode %s is not structured or binary", s.Mode)
+		}
+	case "file", "unix":
+		if s.endpoint.Path == "" {
+			return fmt.Errorf("the url %s has no path", s.Url)
+		}
+		if s.Mode != "" && s.Mode != cloudEventsStructured {
+			return fmt.Errorf("the %s sink only supports the structured mode", s.endpoint.Scheme)
+		}
+	default:
+		return fmt.Errorf("the url %s is not http, https, file or unix", s.Url)
+	}
+	if s.Source == "" {
+		hostname, err := os.Hostname()
+		if err != nil || hostname == "" {
+			hostname = "localhost"
+		}
+		s.Source = "/chaosblade/" + hostname
+	}
+	return nil
+}
+
+// newCloudEvent converts the lifecycle event of the delivery to the cloudevent
+func newCloudEvent(sink *eventSink, delivery *data.EventDelivery) (*cloudEvent, error) {
+	event := &data.LifecycleEvent{}
+	if err := json.Unmarshal([]byte(delivery.Payload), event); err != nil {
+		return nil, fmt.Errorf("unmarshal the payload of the %s event failed, %v", delivery.EventId, err)
+	}
+	kind, _, _ := strings.Cut(event.Type, ".")
+	eventData := &cloudEventData{
+		Uid:            event.Uid,
+		Kind:           kind,
+		Status:         event.Status,
+		PreviousStatus: event.PreviousStatus,
+		Code:           event.Code,
+		Error:          event.Error,
+	}
+	if model := event.Experiment; model != nil {
+		eventData.Target, eventData.Scope, eventData.Action = recordTargetScopeAction(model)
+		eventData.Flags, eventData.Group, eventData.Labels = model.Flags, model.GroupUid, model.Labels
+	}
+	if record := event.Preparation; record != nil {
+		eventData.Target, eventData.Scope = record.ProgramType, "host"
+		eventData.Flags = make(map[string]string)
+		for name, value := range map[string]string{"process": record.Process, "port": record.Port, "pid": record.Pid} {
+			if value != "" {
+				eventData.Flags[name] = value
+			}
+		}
+	}
+	return &cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		Id:              event.Id,
+		Source:          sink.Source,
+		Type:            cloudEventTypePrefix + event.Type,
+		Subject:         event.Uid,
+		Time:            event.Time,
+		DataContentType: "application/json",
+		PartitionKey:    event.Uid,
+		Data:            eventData,
+	}, nil
+}
+
+// recordTargetScopeAction returns the target, scope and action of the experiment record,
+// which is the reverse of getCommandAndSubCommand
+func recordTargetScopeAction(model *data.ExperimentModel) (target, scope, action string) {
+	command, actionTarget, action := splitRecordCommand(model)
+	switch {
+	case actionTarget == "":
+		return command, "host", action
+	case command == "k8s":
+		if scope, target, found := strings.Cut(actionTarget, "-"); found {
+			return target, scope, action
+		}
+		return actionTarget, command, action
+	default:
+		return actionTarget, command, action
+	}
+}
+
+// sendCloudEvent sends the cloudevent over http, or appends it to the spool file or the unix socket as a JSON line
+func sendCloudEvent(sink *eventSink, delivery *data.EventDelivery, now time.Time) error {
+	event, err := newCloudEvent(sink, delivery)
+	if err != nil {
+		return err
+	}
+	switch sink.endpoint.Scheme {
+	case "file":
+		return appendCloudEvent(sink.endpoint.Path, event)
+	case "unix":
+		return writeCloudEvent(sink.endpoint.Path, event, sink.timeout)
+	}
+	if sink.Mode == cloudEventsBinary {
+		body, err := json.Marshal(event.Data)
+		if err != nil {
+			return err
+		}
+		return postEvent(sink, delivery, now, string(body), map[string]string{
+			"Content-Type":    event.DataContentType,
+			"ce-specversion":  event.SpecVersion,
+			"ce-id":           event.Id,
+			"ce-source":       event.Source,
+			"ce-type":         event.Type,
+			"ce-subject":      event.Subject,
+			"ce-time":         event.Time,
+			"ce-partitionkey": event.PartitionKey,
+		})
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return postEvent(sink, delivery, now, string(body), map[string]string{"Content-Type": cloudEventsContentType})
+}
+
+// appendCloudEvent appends the event to the spool file in one write call, so the lines of processes are not interleaved
+func appendCloudEvent(spool string, event *cloudEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(spool), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(spool, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeCloudEvent writes the event as a JSON line to the unix socket which the sidecar listens on
+func writeCloudEvent(socket string, event *cloudEvent, timeout time.Duration) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialTimeout("unix", socket, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	_, err = conn.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+func Test_recordTargetScopeAction(t *testing.T) {
+	tests := []struct {
+		command, subCommand                           string
+		expectedTarget, expectedScope, expectedAction string
+	}{
+		{"cpu", "fullload", "cpu", "host", "fullload"},
+		{"docker", "cpu fullload", "cpu", "docker", "fullload"},
+		{"cri", "network delay", "network", "cri", "delay"},
+		{"k8s", "pod-network delay", "network", "pod", "delay"},
+		{"k8s", "node-cpu fullload", "cpu", "node", "fullload"},
+	}
+	for _, tt := range tests {
+		target, scope, action := recordTargetScopeAction(&data.ExperimentModel{Command: tt.command, SubCommand: tt.subCommand})
+		if target != tt.expectedTarget || scope != tt.expectedScope || action != tt.expectedAction {
+			t.Errorf("%s %s: unexpected target %s, scope %s, action %s", tt.command, tt.subCommand, target, scope, action)
+		}
+	}
+}
+
+// newTestCloudEventDelivery returns the delivery of a failed k8s experiment
+func newTestCloudEventDelivery(t *testing.T) *data.EventDelivery {
+	payload, err := json.Marshal(&data.LifecycleEvent{
+		Id: "e1", Type: data.EventExperimentFailed, Time: "2025-06-01T10:00:00Z", Uid: "a1",
+		PreviousStatus: "Created", Status: Error, Error: "connection refused", Code: 63061,
+		Experiment: &data.ExperimentModel{
+			Uid: "a1", Command: "k8s", SubCommand: "pod-network delay", Status: Error,
+			Flags: map[string]string{"time": "3000", "namespace": "default"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal the event failed, %v", err)
+	}
+	return &data.EventDelivery{Id: 1, EventId: "e1", EventType: data.EventExperimentFailed, Uid: "a1",
+		Sink: "pipeline", Payload: string(payload)}
+}
+
+// checkCloudEvent checks the attributes and the data of the structured cloudevent
+func checkCloudEvent(t *testing.T, name string, event *cloudEvent) {
+	t.Helper()
+	if event.SpecVersion != "1.0" || event.Id != "e1" || event.Type != "io.chaosblade.experiment.failed" ||
+		event.Source != "/chaosblade/test" || event.PartitionKey != "a1" {
+		t.Errorf("%s: unexpected attributes %+v", name, event)
+	}
+	checkCloudEventData(t, name, event.Data)
+}
+
+func checkCloudEventData(t *testing.T, name string, eventData *cloudEventData) {
+	t.Helper()
+	if eventData == nil || eventData.Uid != "a1" || eventData.Target != "network" || eventData.Scope != "pod" ||
+		eventData.Action != "delay" || eventData.Flags["time"] != "3000" || eventData.Code != 63061 ||
+		eventData.Error != "connection refused" {
+		t.Errorf("%s: unexpected data %+v", name, eventData)
+	}
+}
+
+func Test_sendCloudEvent(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- body
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	for _, mode := range []string{cloudEventsStructured, cloudEventsBinary} {
+		sink := &eventSink{Name: "pipeline", Format: eventFormatCloudEvents, Url: server.URL, Mode: mode, Source: "/chaosblade/test"}
+		if err := sink.validate(); err != nil {
+			t.Fatalf("validate the sink failed, %v", err)
+		}
+		if err := sendEvent(sink, newTestCloudEventDelivery(t), time.Now()); err != nil {
+			t.Fatalf("%s: send the event failed, %v", mode, err)
+		}
+		request, body := <-requests, <-bodies
+		if mode == cloudEventsStructured {
+			event := &cloudEvent{}
+			if err := json.Unmarshal(body, event); err != nil || request.Header.Get("Content-Type") != cloudEventsContentType {
+				t.Errorf("%s: unexpected content type %s, %v", mode, request.Header.Get("Content-Type"), err)
+			}
+			checkCloudEvent(t, mode, event)
+			continue
+		}
+		eventData := &cloudEventData{}
+		if err := json.Unmarshal(body, eventData); err != nil || request.Header.Get("ce-specversion") != "1.0" ||
+			request.Header.Get("ce-type") != "io.chaosblade.experiment.failed" || request.Header.Get("ce-id") != "e1" {
+			t.Errorf("%s: unexpected headers %v, %v", mode, request.Header, err)
+		}
+		checkCloudEventData(t, mode, eventData)
+	}
+
+	// the spool file is appended by a line of every event
+	spool := filepath.Join(dir, "spool", "events.jsonl")
+	sink := &eventSink{Name: "pipeline", Format: eventFormatCloudEvents, Url: "file://" + spool, Source: "/chaosblade/test"}
+	if err := sink.validate(); err != nil {
+		t.Fatalf("validate the sink failed, %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := sendEvent(sink, newTestCloudEventDelivery(t), time.Now()); err != nil {
+			t.Fatalf("spool the event failed, %v", err)
+		}
+	}
+	file, err := os.Open(spool)
+	if err != nil {
+		t.Fatalf("open the spool failed, %v", err)
+	}
+	defer file.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(file); scanner.Scan(); lines++ {
+		event := &cloudEvent{}
+		if err := json.Unmarshal(scanner.Bytes(), event); err != nil {
+			t.Fatalf("unmarshal the spooled event failed, %v", err)
+		}
+		checkCloudEvent(t, "file", event)
+	}
+	if lines != 2 {
+		t.Errorf("unexpected spooled lines %d", lines)
+	}
+
+	socket := filepath.Join(dir, "events.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen the socket failed, %v", err)
+	}
+	defer listener.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadBytes('\n')
+		received <- line
+	}()
+	sink = &eventSink{Name: "pipeline", Format: eventFormatCloudEvents, Url: "unix://" + socket, Source: "/chaosblade/test"}
+	if err := sink.validate(); err != nil {
+		t.Fatalf("validate the sink failed, %v", err)
+	}
+	if err := sendEvent(sink, newTestCloudEventDelivery(t), time.Now()); err != nil {
+		t.Fatalf("write the event to the socket failed, %v", err)
+	}
+	event := &cloudEvent{}
+	if err := json.Unmarshal(<-received, event); err != nil {
+		t.Fatalf("unmarshal the event of the socket failed, %v", err)
+	}
+	checkCloudEvent(t, "unix", event)
+}
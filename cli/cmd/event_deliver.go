@@ -0,0 +1,348 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+const (
+	FailedFlag = "failed"
+
+	// The headers of the webhook requests, the signature is sha256=HEX of HMAC-SHA256(secret, "TIMESTAMP.BODY")
+	EventIdHeader        = "X-Chaosblade-Event-Id"
+	EventTypeHeader      = "X-Chaosblade-Event"
+	EventDeliveryHeader  = "X-Chaosblade-Delivery"
+	EventTimestampHeader = "X-Chaosblade-Timestamp"
+	EventSignatureHeader = "X-Chaosblade-Signature"
+
+	eventBackoffBase = 5 * time.Second
+	eventBackoffMax  = 10 * time.Minute
+)
+
+// deliveryClock tells the time of the attempts and waits for the next attempt
+type deliveryClock interface {
+	now() time.Time
+	sleep(duration time.Duration)
+}
+
+// systemClock is the deliveryClock of the system time
+type systemClock struct{}
+
+func (systemClock) now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) sleep(duration time.Duration) {
+	time.Sleep(duration)
+}
+
+// eventDeliverer attempts the due deliveries of the outbox
+type eventDeliverer struct {
+	outbox data.EventOutbox
+	sinks  map[string]*eventSink
+	clock  deliveryClock
+}
+
+func newEventDeliverer(outbox data.EventOutbox, sinks []*eventSink, clock deliveryClock) *eventDeliverer {
+	deliverer := &eventDeliverer{
+		outbox: outbox,
+		sinks:  make(map[string]*eventSink, len(sinks)),
+		clock:  clock,
+	}
+	for _, sink := range sinks {
+		deliverer.sinks[sink.Name] = sink
+	}
+	return deliverer
+}
+
+// deliverDue attempts the due Pending deliveries once, and returns the attempted ones with the results.
+// The deliveries claimed by other processes are skipped.
+func (d *eventDeliverer) deliverDue() ([]*data.EventDelivery, error) {
+	deliveries, err := d.outbox.QueryEventDeliveries(data.DeliveryPending, d.clock.now().Unix())
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.DbQueryFailed, "event_outbox", err)
+	}
+	attempted := make([]*data.EventDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		claimed, err := d.attempt(delivery)
+		if err != nil {
+			return attempted, spec.ResponseFailWithFlags(spec.DatabaseError, "update", err)
+		}
+		if claimed {
+			attempted = append(attempted, delivery)
+		}
+	}
+	return attempted, nil
+}
+
+// attempt claims the delivery and sends it, then records the result. The failed delivery is retried with backoff
+// until the max attempts, and the delivery of the removed sink is failed at once.
+func (d *eventDeliverer) attempt(delivery *data.EventDelivery) (bool, error) {
+	now := d.clock.now()
+	sink := d.sinks[delivery.Sink]
+	timeout := defaultEventTimeout
+	if sink != nil {
+		timeout = sink.timeout
+	}
+	// the lease is longer than the request, so the delivery is attempted by another process only if this one exits
+	claimed, err := d.outbox.ClaimEventDelivery(delivery.Id, delivery.NextAttempt, now.Add(timeout+time.Minute).Unix())
+	if err != nil || !claimed {
+		return false, err
+	}
+	delivery.Attempts++
+	if sink == nil {
+		err = fmt.Errorf("the %s sink is not configured", delivery.Sink)
+	} else {
+		err = sendEvent(sink, delivery, now)
+	}
+	switch {
+	case err == nil:
+		delivery.Status, delivery.NextAttempt, delivery.Error = data.DeliveryDelivered, 0, ""
+	case sink == nil || delivery.Attempts >= sink.MaxAttempts:
+		delivery.Status, delivery.NextAttempt, delivery.Error = data.DeliveryFailed, 0, err.Error()
+	default:
+		delivery.NextAttempt, delivery.Error = now.Add(eventBackoff(delivery.Attempts)).Unix(), err.Error()
+	}
+	if err != nil {
+		log.Warnf(context.Background(), "deliver the %s event of %s to the %s sink failed, attempts: %d, %v",
+			delivery.EventType, delivery.Uid, delivery.Sink, delivery.Attempts, err)
+	}
+	return true, d.outbox.UpdateEventDelivery(delivery.Id, delivery.Status, delivery.Attempts, delivery.NextAttempt,
+		delivery.Error)
+}
+
+// eventBackoff doubles the interval after every failed attempt
+func eventBackoff(attempts int) time.Duration {
+	backoff := eventBackoffBase
+	for i := 1; i < attempts && backoff < eventBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > eventBackoffMax {
+		return eventBackoffMax
+	}
+	return backoff
+}
+
+// drain delivers the due deliveries and waits for the next attempt until no delivery is Pending
+func (d *eventDeliverer) drain() error {
+	for {
+		if _, err := d.deliverDue(); err != nil {
+			return err
+		}
+		pending, err := d.outbox.QueryEventDeliveries(data.DeliveryPending, 0)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.DbQueryFailed, "event_outbox", err)
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		next := pending[0].NextAttempt
+		for _, delivery := range pending {
+			if delivery.NextAttempt < next {
+				next = delivery.NextAttempt
+			}
+		}
+		wait := time.Unix(next, 0).Sub(d.clock.now())
+		if wait < time.Second {
+			wait = time.Second
+		}
+		d.clock.sleep(wait)
+	}
+}
+
+// signEvent returns the hex HMAC-SHA256 of the timestamp and the payload
+func signEvent(secret string, timestamp int64, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendEvent sends the delivery in the format of the sink
+func sendEvent(sink *eventSink, delivery *data.EventDelivery, now time.Time) error {
+	if sink.Format == eventFormatCloudEvents {
+		return sendCloudEvent(sink, delivery, now)
+	}
+	return postEvent(sink, delivery, now, delivery.Payload, map[string]string{"Content-Type": "application/json"})
+}
+
+// postEvent posts the body with the headers to the sink, the response which is not 2xx fails the attempt
+func postEvent(sink *eventSink, delivery *data.EventDelivery, now time.Time, body string, headers map[string]string) error {
+	request, err := http.NewRequest(http.MethodPost, sink.Url, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := now.Unix()
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	request.Header.Set(EventIdHeader, delivery.EventId)
+	request.Header.Set(EventTypeHeader, delivery.EventType)
+	request.Header.Set(EventDeliveryHeader, strconv.FormatInt(delivery.Id, 10))
+	request.Header.Set(EventTimestampHeader, strconv.FormatInt(timestamp, 10))
+	if sink.Secret != "" {
+		request.Header.Set(EventSignatureHeader, "sha256="+signEvent(sink.Secret, timestamp, body))
+	}
+	client := &http.Client{Timeout: sink.timeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	message, _ := io.ReadAll(io.LimitReader(response.Body, 256))
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("the sink responds %s, %s", response.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// EventDeliverCommand delivers the due events of the outbox
+type EventDeliverCommand struct {
+	baseCommand
+	watch bool
+}
+
+func (edc *EventDeliverCommand) Init() {
+	edc.command = &cobra.Command{
+		Use:   "deliver",
+		Short: "Deliver the pending events",
+		Long: "Deliver the pending events whose next attempt is due. With --watch, it waits for the next attempts " +
+			"until no event is pending, which is started by blade automatically after the events are published.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return edc.runDeliver(cmd)
+		},
+		Example: eventDeliverExample(),
+	}
+	edc.command.Flags().BoolVar(&edc.watch, WatchFlag, false, "Wait for the next attempts until no event is pending")
+}
+
+func (edc *EventDeliverCommand) runDeliver(cmd *cobra.Command) error {
+	file := getEventSinksFile()
+	sinks, err := loadEventSinks(file)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.ParameterInvalid, EventSinksFileEnv, file, err)
+	}
+	deliverer := newEventDeliverer(GetDS(), sinks, systemClock{})
+	if edc.watch {
+		return deliverer.drain()
+	}
+	deliveries, err := deliverer.deliverDue()
+	if err != nil {
+		return err
+	}
+	response := spec.ReturnSuccess(deliveries)
+	return printResponse(cmd, response, func() { cmd.Println(response.Print()) })
+}
+
+// EventRedeliverCommand queues the deliveries again
+type EventRedeliverCommand struct {
+	baseCommand
+	failed bool
+}
+
+func (erc *EventRedeliverCommand) Init() {
+	erc.command = &cobra.Command{
+		Use:   "redeliver [ID]...",
+		Short: "Deliver the events again",
+		Long: "Deliver the events again, the deliveries are Pending with zero attempts and delivered by the " +
+			"background deliverer. The Delivered deliveries can be redelivered by the ids.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return erc.runRedeliver(cmd, args)
+		},
+		Example: eventRedeliverExample(),
+	}
+	erc.command.Flags().BoolVar(&erc.failed, FailedFlag, false, "Redeliver all Failed deliveries")
+}
+
+func (erc *EventRedeliverCommand) runRedeliver(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !erc.failed {
+		return spec.ResponseFailWithFlags(spec.ParameterLess, "id or --failed")
+	}
+	ids, err := redeliverEvents(GetDS(), args, erc.failed, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		if _, err := startEventDeliverer(nohupLauncher{}); err != nil {
+			log.Warnf(context.Background(), "start the event deliverer failed, %v", err)
+		}
+	}
+	cmd.Println(spec.ReturnSuccess(ids).Print())
+	return nil
+}
+
+// redeliverEvents sets the deliveries of the ids, or all Failed ones, to Pending with zero attempts
+func redeliverEvents(outbox data.EventOutbox, args []string, failed bool, now time.Time) ([]int64, error) {
+	deliveries, err := outbox.QueryEventDeliveries("", 0)
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.DbQueryFailed, "event_outbox", err)
+	}
+	selected := make(map[int64]bool)
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, "id", arg, err)
+		}
+		selected[id] = false
+	}
+	ids := make([]int64, 0)
+	for _, delivery := range deliveries {
+		if _, ok := selected[delivery.Id]; !ok && !(failed && delivery.Status == data.DeliveryFailed) {
+			continue
+		}
+		selected[delivery.Id] = true
+		if err := outbox.UpdateEventDelivery(delivery.Id, data.DeliveryPending, 0, now.Unix(), ""); err != nil {
+			return ids, spec.ResponseFailWithFlags(spec.DatabaseError, "update", err)
+		}
+		ids = append(ids, delivery.Id)
+	}
+	for id, found := range selected {
+		if !found {
+			return ids, spec.ResponseFailWithFlags(spec.DataNotFound, id)
+		}
+	}
+	return ids, nil
+}
+
+func eventDeliverExample() string {
+	return `# Deliver the due events once
+blade event deliver`
+}
+
+func eventRedeliverExample() string {
+	return `# Deliver the events of the deliveries 12 and 13 again
+blade event redeliver 12 13
+
+# Deliver all failed deliveries again
+blade event redeliver --failed`
+}
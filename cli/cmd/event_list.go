@@ -0,0 +1,76 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+const PayloadFlag = "payload"
+
+// EventListCommand lists the deliveries of the outbox
+type EventListCommand struct {
+	baseCommand
+	status  string
+	payload bool
+}
+
+func (elc *EventListCommand) Init() {
+	elc.command = &cobra.Command{
+		Use:   "list",
+		Short: "List the event deliveries",
+		Long:  "List the event deliveries of the outbox order by id",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return elc.runList(cmd)
+		},
+		Example: eventListExample(),
+	}
+	elc.command.Flags().StringVar(&elc.status, StatusFlag, "", "The status of the deliveries, one of Pending|Delivered|Failed")
+	elc.command.Flags().BoolVar(&elc.payload, PayloadFlag, false, "Show the payloads of the events")
+}
+
+func (elc *EventListCommand) runList(cmd *cobra.Command) error {
+	status := elc.status
+	if status != "" {
+		status = data.UpperFirst(status)
+		if status != data.DeliveryPending && status != data.DeliveryDelivered && status != data.DeliveryFailed {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, StatusFlag, elc.status,
+				"only support Pending, Delivered and Failed")
+		}
+	}
+	deliveries, err := GetDS().QueryEventDeliveries(status, 0)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DbQueryFailed, "event_outbox", err)
+	}
+	if !elc.payload {
+		for _, delivery := range deliveries {
+			delivery.Payload = ""
+		}
+	}
+	response := spec.ReturnSuccess(deliveries)
+	return printResponse(cmd, response, func() { cmd.Println(response.Print()) })
+}
+
+func eventListExample() string {
+	return `blade event list --status Pending
+
+blade event list -o table`
+}
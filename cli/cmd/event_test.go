@@ -0,0 +1,229 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+func Test_loadEventSinks(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedSinks int
+		expectedErr   bool
+	}{
+		{"valid", "sinks:\n- name: ops\n  url: https://hooks.example.com\n  events: [\"experiment.*\"]\n- name: audit\n  url: http://127.0.0.1:8080\n  timeout: 1s\n", 2, false},
+		{"duplicated", "sinks:\n- name: ops\n  url: https://hooks.example.com\n- name: ops\n  url: https://hooks.example.com\n", 0, true},
+		{"no name", "sinks:\n- url: https://hooks.example.com\n", 0, true},
+		{"not http", "sinks:\n- name: ops\n  url: ftp://hooks.example.com\n", 0, true},
+		{"illegal timeout", "sinks:\n- name: ops\n  url: https://hooks.example.com\n  timeout: 5\n", 0, true},
+		{"unknown key", "sinks:\n- name: ops\n  uri: https://hooks.example.com\n", 0, true},
+		{"cloudevents spool", "sinks:\n- name: pipeline\n  format: cloudevents\n  url: file:///var/spool/chaosblade/events.jsonl\n", 1, false},
+		{"cloudevents binary", "sinks:\n- name: pipeline\n  format: cloudevents\n  mode: binary\n  url: http://127.0.0.1:8080\n", 1, false},
+		{"binary spool", "sinks:\n- name: pipeline\n  format: cloudevents\n  mode: binary\n  url: unix:///run/chaosblade.sock\n", 0, true},
+		{"webhook spool", "sinks:\n- name: ops\n  url: file:///var/spool/chaosblade/events.jsonl\n", 0, true},
+		{"unknown format", "sinks:\n- name: ops\n  format: kafka\n  url: http://127.0.0.1:8080\n", 0, true},
+	}
+	for _, tt := range tests {
+		file := filepath.Join(t.TempDir(), "event-sinks.yaml")
+		if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+			t.Fatalf("write sinks file failed, %v", err)
+		}
+		sinks, err := loadEventSinks(file)
+		if (err != nil) != tt.expectedErr || len(sinks) != tt.expectedSinks {
+			t.Errorf("%s: unexpected sinks %v, error: %v", tt.name, sinks, err)
+		}
+	}
+	if sinks, err := loadEventSinks(filepath.Join(t.TempDir(), "absent.yaml")); err != nil || sinks != nil {
+		t.Errorf("unexpected sinks %v of the absent file, error: %v", sinks, err)
+	}
+}
+
+func Test_eventSink_accept(t *testing.T) {
+	sink := &eventSink{Events: []string{"experiment.*", "preparation.attached"}}
+	for eventType, expected := range map[string]bool{
+		data.EventExperimentCreated:   true,
+		data.EventPreparationAttached: true,
+		data.EventPreparationRevoked:  false,
+	} {
+		if sink.accept(eventType) != expected {
+			t.Errorf("unexpected accept of %s", eventType)
+		}
+	}
+	if !(&eventSink{}).accept(data.EventPreparationRevoked) {
+		t.Errorf("the sink without events does not accept all events")
+	}
+}
+
+// testClock moves to the next attempt when waiting
+type testClock struct {
+	current time.Time
+	waits   []time.Duration
+}
+
+func (c *testClock) now() time.Time {
+	return c.current
+}
+
+func (c *testClock) sleep(duration time.Duration) {
+	c.waits = append(c.waits, duration)
+	c.current = c.current.Add(duration)
+}
+
+func Test_eventDeliverer(t *testing.T) {
+	const secret = "7b0e4c1d"
+	failures := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(EventTimestampHeader), 10, 64)
+		if r.Header.Get(EventSignatureHeader) != "sha256="+signEvent(secret, timestamp, string(body)) ||
+			r.Header.Get(EventTypeHeader) != data.EventExperimentCreated {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name             string
+		sink             string
+		failures         int
+		expectedStatus   string
+		expectedAttempts int
+	}{
+		{"delivered", "ops", 0, data.DeliveryDelivered, 1},
+		{"retried", "ops", 2, data.DeliveryDelivered, 3},
+		{"failed", "ops", 5, data.DeliveryFailed, 3},
+		{"removed sink", "removed", 0, data.DeliveryFailed, 1},
+	}
+	for _, tt := range tests {
+		src := data.NewMemorySource()
+		if err := src.InsertEventDeliveries([]*data.EventDelivery{{
+			EventId: "e1", EventType: data.EventExperimentCreated, Uid: "a1", Sink: tt.sink, Payload: `{"uid":"a1"}`,
+			Status: data.DeliveryPending, NextAttempt: 100,
+		}}); err != nil {
+			t.Fatalf("insert deliveries failed, %v", err)
+		}
+		sink := &eventSink{Name: "ops", Url: server.URL, Secret: secret, MaxAttempts: 3}
+		if err := sink.validate(); err != nil {
+			t.Fatalf("validate the sink failed, %v", err)
+		}
+		clock := &testClock{current: time.Unix(100, 0)}
+		deliverer := newEventDeliverer(src, []*eventSink{sink}, clock)
+		failures = tt.failures
+		if err := deliverer.drain(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		deliveries, _ := src.QueryEventDeliveries("", 0)
+		delivery := deliveries[0]
+		if delivery.Status != tt.expectedStatus || delivery.Attempts != tt.expectedAttempts {
+			t.Errorf("%s: unexpected delivery %+v", tt.name, delivery)
+		}
+		// the backoff doubles after every failed attempt
+		if waits := clock.waits; len(waits) != tt.expectedAttempts-1 || (len(waits) == 2 && waits[1] != 2*waits[0]) {
+			t.Errorf("%s: unexpected waits %v", tt.name, waits)
+		}
+	}
+}
+
+func Test_redeliverEvents(t *testing.T) {
+	src := data.NewMemorySource()
+	if err := src.InsertEventDeliveries([]*data.EventDelivery{
+		{EventId: "e1", Sink: "ops", Status: data.DeliveryFailed, Attempts: 10},
+		{EventId: "e2", Sink: "ops", Status: data.DeliveryDelivered, Attempts: 1},
+		{EventId: "e3", Sink: "ops", Status: data.DeliveryFailed, Attempts: 10},
+	}); err != nil {
+		t.Fatalf("insert deliveries failed, %v", err)
+	}
+	tests := []struct {
+		name        string
+		args        []string
+		failed      bool
+		expectedIds int
+		expectedErr bool
+	}{
+		{"failed", nil, true, 2, false},
+		{"ids", []string{"2"}, false, 1, false},
+		{"unknown id", []string{"9"}, false, 0, true},
+		{"illegal id", []string{"e1"}, false, 0, true},
+	}
+	for _, tt := range tests {
+		ids, err := redeliverEvents(src, tt.args, tt.failed, time.Unix(100, 0))
+		if (err != nil) != tt.expectedErr || len(ids) != tt.expectedIds {
+			t.Errorf("%s: unexpected ids %v, error: %v", tt.name, ids, err)
+		}
+	}
+	pending, _ := src.QueryEventDeliveries(data.DeliveryPending, 100)
+	if len(pending) != 3 || pending[0].Attempts != 0 {
+		t.Errorf("unexpected pending deliveries %+v", pending)
+	}
+}
+
+func Test_eventRouter_Notify(t *testing.T) {
+	starts := make(chan chan error, 4)
+	router := &eventRouter{launcher: launcherFunc(func(env []string, args ...string) (func() error, error) {
+		exit := make(chan error)
+		starts <- exit
+		return func() error { return <-exit }, nil
+	})}
+	waitStart := func() chan error {
+		select {
+		case exit := <-starts:
+			return exit
+		case <-time.After(time.Second):
+			t.Fatalf("the deliverer is not started")
+		}
+		return nil
+	}
+
+	router.Notify(nil)
+	exit := waitStart()
+	// the running deliverer is not started again
+	router.Notify(nil)
+	router.Notify(nil)
+	if len(starts) != 0 {
+		t.Fatalf("the deliverer is started while running")
+	}
+	// the deliveries notified while running are delivered by the deliverer started after it exits
+	exit <- nil
+	exit = waitStart()
+	exit <- nil
+	select {
+	case <-starts:
+		t.Fatalf("the deliverer is started without the notified deliveries")
+	case <-time.After(50 * time.Millisecond):
+	}
+	// the deliverer is started again in the long-running process after it exits
+	router.Notify(nil)
+	close(waitStart())
+}
@@ -17,7 +17,6 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"path"
 
@@ -26,7 +25,6 @@ import (
 
 	"github.com/chaosblade-io/chaosblade-exec-cri/exec"
 	"github.com/chaosblade-io/chaosblade-spec-go/channel"
-	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 	specutil "github.com/chaosblade-io/chaosblade-spec-go/util"
 
@@ -90,6 +88,8 @@ type actionCommandService interface {
 type baseExpCommandService struct {
 	commands           map[string]*modelCommand
 	executors          map[string]spec.Executor
+	actionSpecs        map[string]*expActionSpec
+	parentFlags        map[string][]spec.ExpFlagSpec
 	bindFlagsFunc      func(commandFlags map[string]func() string, cmd *cobra.Command, specFlags []spec.ExpFlagSpec)
 	actionRunEFunc     func(target, scope string, actionCommand *actionCommand, actionCommandSpec spec.ExpActionCommandSpec) func(cmd *cobra.Command, args []string) error
 	actionPostRunEFunc func(actionCommand *actionCommand) func(cmd *cobra.Command, args []string) error
@@ -99,6 +99,8 @@ func newBaseExpCommandService(actionService actionCommandService) *baseExpComman
 	service := &baseExpCommandService{
 		commands:           make(map[string]*modelCommand, 0),
 		executors:          make(map[string]spec.Executor, 0),
+		actionSpecs:        make(map[string]*expActionSpec, 0),
+		parentFlags:        make(map[string][]spec.ExpFlagSpec, 0),
 		bindFlagsFunc:      actionService.bindFlagsFunction(),
 		actionRunEFunc:     actionService.actionRunEFunc,
 		actionPostRunEFunc: actionService.actionPostRunEFunc,
@@ -115,6 +117,72 @@ func (ec *baseExpCommandService) GetExecutor(target, actionTarget, action string
 	return ec.executors[key]
 }
 
+// expActionSpec caches the action spec and the flags declared by its model command
+type expActionSpec struct {
+	spec.ExpActionCommandSpec
+	// parent is the parent target command, for example docker or k8s
+	parent string
+	// modelFlags are the flags of the model command
+	modelFlags []spec.ExpFlagSpec
+}
+
+// GetActionSpec returns the action spec of the experiment model, the lookup key is the same as the executor
+func (ec *baseExpCommandService) GetActionSpec(expModel *spec.ExpModel) *expActionSpec {
+	parent, actionTarget := getParentAndActionTarget(expModel.Target, expModel.Scope)
+	return ec.actionSpecs[createExecutorKey(parent, actionTarget, expModel.ActionName)]
+}
+
+// getParentAndActionTarget returns the parent command and the action target command by the experiment target and scope
+func getParentAndActionTarget(target, scope string) (string, string) {
+	switch scope {
+	case "", "host":
+		return "", target
+	case "docker", "cri":
+		return scope, target
+	default:
+		return "k8s", fmt.Sprintf("%s-%s", scope, target)
+	}
+}
+
+// getCommandAndSubCommand returns the command and sub command recorded in the experiment table
+func getCommandAndSubCommand(expModel *spec.ExpModel) (string, string) {
+	parent, actionTarget := getParentAndActionTarget(expModel.Target, expModel.Scope)
+	if parent == "" {
+		return actionTarget, expModel.ActionName
+	}
+	return parent, fmt.Sprintf("%s %s", actionTarget, expModel.ActionName)
+}
+
+// checkExpModelFlags checks the experiment flags against the action spec and sets the default flag values
+func (ec *baseExpCommandService) checkExpModelFlags(expModel *spec.ExpModel, actionSpec *expActionSpec) error {
+	flagSpecs := make(map[string]spec.ExpFlagSpec, 0)
+	flags := append(append([]spec.ExpFlagSpec{}, actionSpec.modelFlags...), ec.parentFlags[actionSpec.parent]...)
+	flags = append(append(flags, addTimeoutFlag(actionSpec.Flags())...), actionSpec.Matchers()...)
+	for _, flag := range flags {
+		flagSpecs[flag.FlagName()] = flag
+	}
+	if expModel.ActionFlags == nil {
+		expModel.ActionFlags = make(map[string]string, 0)
+	}
+	for name := range expModel.ActionFlags {
+		if _, ok := flagSpecs[name]; !ok && name != UidFlag {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, name, expModel.ActionFlags[name], "unknown flag")
+		}
+	}
+	for name, flag := range flagSpecs {
+		if expModel.ActionFlags[name] != "" {
+			continue
+		}
+		if flag.FlagRequired() {
+			return spec.ResponseFailWithFlags(spec.ParameterLess, name)
+		}
+		if flag.FlagDefault() != "" {
+			expModel.ActionFlags[name] = flag.FlagDefault()
+		}
+	}
+	return nil
+}
+
 func (ec *baseExpCommandService) registerSubCommands() {
 	// register os type command
 	ec.registerOsExpCommands()
@@ -354,6 +422,9 @@ func (ec *baseExpCommandService) registerExpCommand(commandSpec spec.ExpModelCom
 	}
 	// add command flags
 	ec.bindFlagsFunc(command.CommandFlags, cmd, commandSpec.Flags())
+	if parentTargetCmd == "" {
+		ec.parentFlags[cmdName] = commandSpec.Flags()
+	}
 	// add action to command
 	for idx := range commandSpec.Actions() {
 		action := commandSpec.Actions()[idx]
@@ -365,7 +436,13 @@ func (ec *baseExpCommandService) registerExpCommand(commandSpec spec.ExpModelCom
 		if executor != nil {
 			executor.SetChannel(channel.NewLocalChannel())
 		}
-		ec.executors[createExecutorKey(parentTargetCmd, cmdName, action.Name())] = executor
+		key := createExecutorKey(parentTargetCmd, cmdName, action.Name())
+		ec.executors[key] = executor
+		ec.actionSpecs[key] = &expActionSpec{
+			ExpActionCommandSpec: action,
+			parent:               parentTargetCmd,
+			modelFlags:           commandSpec.Flags(),
+		}
 	}
 
 	if parentTargetCmd == "" {
@@ -423,14 +500,6 @@ func addTimeoutFlag(flags []spec.ExpFlagSpec) []spec.ExpFlagSpec {
 	return flags
 }
 
-// checkError for db operation
-func checkError(err error) {
-	if err != nil {
-		log.Warnf(context.Background(), "%s", err.Error())
-		// log.V(-1).Info(err.Error())
-	}
-}
-
 func createExpModel(target, scope, actionName string, cmd *cobra.Command) *spec.ExpModel {
 	expModel := &spec.ExpModel{
 		Target:      target,
@@ -440,7 +509,8 @@ func createExpModel(target, scope, actionName string, cmd *cobra.Command) *spec.
 	}
 
 	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
-		if flag.Value.String() == "false" {
+		// the output format of the root command is not a flag of the experiment
+		if flag.Value.String() == "false" || flag.Name == OutputFlag {
 			return
 		}
 		expModel.ActionFlags[flag.Name] = flag.Value.String()
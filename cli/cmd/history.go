@@ -0,0 +1,310 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+const (
+	HistoryFormatJSON   = "json"
+	HistoryFormatCSV    = "csv"
+	HistoryFormatNDJSON = "ndjson"
+
+	historyKindExperiment  = "experiment"
+	historyKindPreparation = "preparation"
+)
+
+// HistoryCommand exports and imports the experiment and preparation records
+type HistoryCommand struct {
+	baseCommand
+}
+
+func (hc *HistoryCommand) Init() {
+	hc.command = &cobra.Command{
+		Use:   "history",
+		Short: "Export and import the experiment history",
+		Long:  "Export the experiment and preparation records for audits, and import them into another data file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return spec.ResponseFailWithFlags(spec.CommandIllegal, "less export or import command")
+		},
+		Example: historyExample(),
+	}
+}
+
+func historyExample() string {
+	return `blade history export --format ndjson --since 30d > history.ndjson`
+}
+
+// historyRecord is an exported experiment or preparation record, the Host is the hostname of the exporting host
+type historyRecord struct {
+	Kind        string            `json:"kind"`
+	Uid         string            `json:"uid"`
+	Host        string            `json:"host,omitempty"`
+	Command     string            `json:"command,omitempty"`
+	SubCommand  string            `json:"subCommand,omitempty"`
+	Flag        string            `json:"flag,omitempty"`
+	Flags       map[string]string `json:"flags,omitempty"`
+	GroupUid    string            `json:"groupUid,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	ProgramType string            `json:"programType,omitempty"`
+	Process     string            `json:"process,omitempty"`
+	Port        string            `json:"port,omitempty"`
+	Pid         string            `json:"pid,omitempty"`
+	Status      string            `json:"status"`
+	Error       string            `json:"error,omitempty"`
+	CreateTime  string            `json:"createTime"`
+	UpdateTime  string            `json:"updateTime"`
+}
+
+// historyCSVHeader is the header of the csv format, the maps are encoded as JSON
+var historyCSVHeader = []string{
+	"kind", "uid", "host", "command", "sub_command", "flag", "flags", "group_uid", "labels",
+	"program_type", "process", "port", "pid", "status", "error", "create_time", "update_time",
+}
+
+func newExperimentHistoryRecord(model *data.ExperimentModel, host string) *historyRecord {
+	return &historyRecord{
+		Kind:       historyKindExperiment,
+		Uid:        model.Uid,
+		Host:       host,
+		Command:    model.Command,
+		SubCommand: model.SubCommand,
+		Flag:       model.Flag,
+		Flags:      model.Flags,
+		GroupUid:   model.GroupUid,
+		Labels:     model.Labels,
+		Status:     model.Status,
+		Error:      model.Error,
+		CreateTime: model.CreateTime,
+		UpdateTime: model.UpdateTime,
+	}
+}
+
+func newPreparationHistoryRecord(record *data.PreparationRecord, host string) *historyRecord {
+	return &historyRecord{
+		Kind:        historyKindPreparation,
+		Uid:         record.Uid,
+		Host:        host,
+		ProgramType: record.ProgramType,
+		Process:     record.Process,
+		Port:        record.Port,
+		Pid:         record.Pid,
+		Status:      record.Status,
+		Error:       record.Error,
+		CreateTime:  record.CreateTime,
+		UpdateTime:  record.UpdateTime,
+	}
+}
+
+func (r *historyRecord) experimentModel() *data.ExperimentModel {
+	return &data.ExperimentModel{
+		Uid:        r.Uid,
+		Command:    r.Command,
+		SubCommand: r.SubCommand,
+		Flag:       r.Flag,
+		Status:     r.Status,
+		Error:      r.Error,
+		CreateTime: r.CreateTime,
+		UpdateTime: r.UpdateTime,
+		GroupUid:   r.GroupUid,
+		Labels:     r.Labels,
+		Flags:      r.Flags,
+	}
+}
+
+func (r *historyRecord) preparationRecord() *data.PreparationRecord {
+	return &data.PreparationRecord{
+		Uid:         r.Uid,
+		ProgramType: r.ProgramType,
+		Process:     r.Process,
+		Port:        r.Port,
+		Pid:         r.Pid,
+		Status:      r.Status,
+		Error:       r.Error,
+		CreateTime:  r.CreateTime,
+		UpdateTime:  r.UpdateTime,
+	}
+}
+
+func checkHistoryFormat(format string) error {
+	switch format {
+	case HistoryFormatJSON, HistoryFormatCSV, HistoryFormatNDJSON:
+		return nil
+	}
+	return fmt.Errorf("unsupported format %s, only support json, csv and ndjson", format)
+}
+
+// writeHistoryRecords writes the records in the format
+func writeHistoryRecords(writer io.Writer, format string, records []*historyRecord) error {
+	switch format {
+	case HistoryFormatJSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case HistoryFormatNDJSON:
+		encoder := json.NewEncoder(writer)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case HistoryFormatCSV:
+		csvWriter := csv.NewWriter(writer)
+		if err := csvWriter.Write(historyCSVHeader); err != nil {
+			return err
+		}
+		for _, record := range records {
+			flags, err := marshalHistoryMap(record.Flags)
+			if err != nil {
+				return err
+			}
+			labels, err := marshalHistoryMap(record.Labels)
+			if err != nil {
+				return err
+			}
+			if err := csvWriter.Write([]string{
+				record.Kind, record.Uid, record.Host, record.Command, record.SubCommand, record.Flag, flags,
+				record.GroupUid, labels, record.ProgramType, record.Process, record.Port, record.Pid,
+				record.Status, record.Error, record.CreateTime, record.UpdateTime,
+			}); err != nil {
+				return err
+			}
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	}
+	return checkHistoryFormat(format)
+}
+
+// readHistoryRecords reads the records in the format, the format is detected by the content if empty
+func readHistoryRecords(reader io.Reader, format string) ([]*historyRecord, error) {
+	buffered := bufio.NewReader(reader)
+	if format == "" {
+		format = detectHistoryFormat(buffered)
+	}
+	records := make([]*historyRecord, 0)
+	switch format {
+	case HistoryFormatJSON:
+		if err := json.NewDecoder(buffered).Decode(&records); err != nil {
+			return nil, fmt.Errorf("decode json records err, %s", err)
+		}
+	case HistoryFormatNDJSON:
+		decoder := json.NewDecoder(buffered)
+		for {
+			record := &historyRecord{}
+			if err := decoder.Decode(record); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("decode ndjson record %d err, %s", len(records)+1, err)
+			}
+			records = append(records, record)
+		}
+	case HistoryFormatCSV:
+		rows, err := csv.NewReader(buffered).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("read csv records err, %s", err)
+		}
+		if len(rows) == 0 {
+			return records, nil
+		}
+		columns := make(map[string]int, len(rows[0]))
+		for idx, column := range rows[0] {
+			columns[column] = idx
+		}
+		for _, column := range []string{"kind", "uid"} {
+			if _, ok := columns[column]; !ok {
+				return nil, fmt.Errorf("the csv header has no %s column", column)
+			}
+		}
+		for line, row := range rows[1:] {
+			value := func(column string) string {
+				if idx, ok := columns[column]; ok && idx < len(row) {
+					return row[idx]
+				}
+				return ""
+			}
+			record := &historyRecord{
+				Kind: value("kind"), Uid: value("uid"), Host: value("host"), Command: value("command"),
+				SubCommand: value("sub_command"), Flag: value("flag"), GroupUid: value("group_uid"),
+				ProgramType: value("program_type"), Process: value("process"), Port: value("port"), Pid: value("pid"),
+				Status: value("status"), Error: value("error"),
+				CreateTime: value("create_time"), UpdateTime: value("update_time"),
+			}
+			if record.Flags, err = unmarshalHistoryMap(value("flags")); err != nil {
+				return nil, fmt.Errorf("decode the flags of csv line %d err, %s", line+2, err)
+			}
+			if record.Labels, err = unmarshalHistoryMap(value("labels")); err != nil {
+				return nil, fmt.Errorf("decode the labels of csv line %d err, %s", line+2, err)
+			}
+			records = append(records, record)
+		}
+	default:
+		return nil, checkHistoryFormat(format)
+	}
+	return records, nil
+}
+
+// detectHistoryFormat returns json if the content starts with [, ndjson if starts with {, else csv
+func detectHistoryFormat(reader *bufio.Reader) string {
+	for size := 64; ; size *= 2 {
+		peek, err := reader.Peek(size)
+		trimmed := bytes.TrimLeft(peek, " \t\r\n")
+		if len(trimmed) > 0 {
+			switch trimmed[0] {
+			case '[':
+				return HistoryFormatJSON
+			case '{':
+				return HistoryFormatNDJSON
+			}
+			return HistoryFormatCSV
+		}
+		if err != nil {
+			return HistoryFormatJSON
+		}
+	}
+}
+
+func marshalHistoryMap(values map[string]string) (string, error) {
+	if len(values) == 0 {
+		return "", nil
+	}
+	bytes, err := json.Marshal(values)
+	return string(bytes), err
+}
+
+func unmarshalHistoryMap(value string) (map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var values map[string]string
+	err := json.Unmarshal([]byte(value), &values)
+	return values, err
+}
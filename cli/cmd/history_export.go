@@ -0,0 +1,143 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+// HistoryExportCommand writes the experiment and preparation records to the stdout or a file
+type HistoryExportCommand struct {
+	baseCommand
+	format string
+	since  string
+	output string
+}
+
+func (ec *HistoryExportCommand) Init() {
+	ec.command = &cobra.Command{
+		Use:   "export",
+		Short: "Export the experiment and preparation records",
+		Long: "Export the experiment and preparation records in json, csv or ndjson format. " +
+			"Every record contains the hostname, so the records exported from the hosts can be imported into one data file.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ec.runExport(cmd)
+		},
+		Example: historyExportExample(),
+	}
+	ec.command.Flags().StringVar(&ec.format, "format", HistoryFormatJSON, "the output format, json|csv|ndjson")
+	ec.command.Flags().StringVar(&ec.since, "since", "", "only export the records created since the time, "+
+		"the value is a RFC3339 time like 2025-01-01T00:00:00Z or an age like 30d, 12h")
+	ec.command.Flags().StringVar(&ec.output, "output-file", "", "the file to write, default is the stdout")
+}
+
+func (ec *HistoryExportCommand) runExport(cmd *cobra.Command) error {
+	if err := checkHistoryFormat(ec.format); err != nil {
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, "format", ec.format, err)
+	}
+	var since time.Time
+	if ec.since != "" {
+		var err error
+		if since, err = parseSince(ec.since, time.Now()); err != nil {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, "since", ec.since, err)
+		}
+	}
+	records, err := queryHistoryRecords(since)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
+	}
+	var writer io.Writer = cmd.OutOrStdout()
+	if ec.output != "" {
+		file, err := os.OpenFile(ec.output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.FileCantReadOrOpen, ec.output)
+		}
+		defer file.Close()
+		writer = file
+	}
+	if err := writeHistoryRecords(writer, ec.format, records); err != nil {
+		return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, "export", err)
+	}
+	return nil
+}
+
+// parseSince parses the RFC3339 time or the age before now
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if since, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return since, nil
+	}
+	age, err := data.ParseAge(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("the value must be a RFC3339 time or an age like 30d")
+	}
+	return now.Add(-age), nil
+}
+
+// queryHistoryRecords returns the experiments and preparations created since the time, all records if it is zero
+func queryHistoryRecords(since time.Time) ([]*historyRecord, error) {
+	host, _ := os.Hostname()
+	models, err := GetDS().QueryExperimentModels("", "", "", "", "", true)
+	if err != nil {
+		return nil, err
+	}
+	preparations, err := GetDS().QueryPreparationRecords("", "", "", "", "", true)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*historyRecord, 0, len(models)+len(preparations))
+	for _, model := range models {
+		if createdSince(model.CreateTime, since) {
+			records = append(records, newExperimentHistoryRecord(model, host))
+		}
+	}
+	for _, preparation := range preparations {
+		if createdSince(preparation.CreateTime, since) {
+			records = append(records, newPreparationHistoryRecord(preparation, host))
+		}
+	}
+	return records, nil
+}
+
+// createdSince returns true if the time is zero or the record is created since the time,
+// the record of the unknown create time is excluded if the time is not zero
+func createdSince(createTime string, since time.Time) bool {
+	if since.IsZero() {
+		return true
+	}
+	created, err := time.Parse(time.RFC3339Nano, createTime)
+	return err == nil && !created.Before(since)
+}
+
+func historyExportExample() string {
+	return `# Export all records in json format
+blade history export
+
+# Export the records created in 30 days in ndjson format to a file
+blade history export --format ndjson --since 30d --output-file history.ndjson
+
+# Export the records created since the time in csv format
+blade history export --format csv --since 2025-01-01T00:00:00Z`
+}
@@ -0,0 +1,259 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+const (
+	// HostLabel is the label of the imported experiments, the value is the hostname of the exporting host
+	HostLabel = "host"
+	// OriginUidLabel is the label of the imported experiments whose uids are changed, the value is the exported uid
+	OriginUidLabel = "origin-uid"
+)
+
+// HistoryImportCommand merges the exported records into the data file
+type HistoryImportCommand struct {
+	baseCommand
+	file   string
+	format string
+}
+
+// importResult contains the count of the imported and skipped records, and the changed uids
+type importResult struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+	// Renamed maps the exported uids to the new uids of the records whose uids are used by other records
+	Renamed map[string]string `json:"renamed,omitempty"`
+}
+
+func (ic *HistoryImportCommand) Init() {
+	ic.command = &cobra.Command{
+		Use:   "import",
+		Short: "Import the exported experiment and preparation records",
+		Long: "Import the records exported by blade history export into the data source selected by CHAOSBLADE_DATASOURCE, " +
+			"which must not be the data file of this host, otherwise the running experiments of the other hosts are " +
+			"destroyed and reported as the local ones. The record already imported is skipped, and the record whose uid " +
+			"is used by another record gets a new uid, so the history of the hosts can be merged into one data source.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ic.runImport(cmd)
+		},
+		Example: historyImportExample(),
+	}
+	ic.command.Flags().StringVarP(&ic.file, "file", "f", "", "the exported file, - means the stdin")
+	ic.command.Flags().StringVar(&ic.format, "format", "", "the file format, json|csv|ndjson, detected by the content if not set")
+	ic.command.MarkFlagRequired("file")
+}
+
+func (ic *HistoryImportCommand) runImport(cmd *cobra.Command) error {
+	if err := checkImportDataSource(); err != nil {
+		return spec.ResponseFailWithFlags(spec.ParameterInvalid, data.DataSourceEnv, os.Getenv(data.DataSourceEnv), err)
+	}
+	if ic.format != "" {
+		if err := checkHistoryFormat(ic.format); err != nil {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, "format", ic.format, err)
+		}
+	}
+	var reader io.Reader = cmd.InOrStdin()
+	if ic.file != "-" {
+		file, err := os.Open(ic.file)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.FileCantReadOrOpen, ic.file)
+		}
+		defer file.Close()
+		reader = file
+	}
+	records, err := readHistoryRecords(reader, ic.format)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, "file", ic.file, err)
+	}
+	result, err := importHistoryRecords(GetDS(), records)
+	if err != nil {
+		return &spec.Response{
+			Code:    spec.DatabaseError.Code,
+			Success: false,
+			Err:     spec.DatabaseError.Sprintf("import", err),
+			Result:  result,
+		}
+	}
+	cmd.Println(spec.ReturnSuccess(result).Print())
+	return nil
+}
+
+// checkImportDataSource requires the explicit data source which is not the data file of this host, because the
+// panic, doctor, reconcile commands and the server metrics treat the imported running experiments as the local ones
+func checkImportDataSource() error {
+	dataSource := os.Getenv(data.DataSourceEnv)
+	if dataSource == "" {
+		return fmt.Errorf("the import requires an explicit target data source, such as sqlite:///data/fleet.dat")
+	}
+	scheme, dataPath, err := data.ParseDataSource(dataSource)
+	if err != nil {
+		return err
+	}
+	if scheme != data.SQLiteScheme {
+		return nil
+	}
+	if dataPath == "" || filepath.Clean(dataPath) == filepath.Clean(data.GetDataFilePath()) {
+		return fmt.Errorf("the data file of this host can't be the import target")
+	}
+	return nil
+}
+
+// importHistoryRecords inserts the records, the record with the same uid, create time and command is skipped,
+// and the record whose uid is used by a different record is inserted with a new uid
+func importHistoryRecords(src data.SourceI, records []*historyRecord) (*importResult, error) {
+	result := &importResult{}
+	for idx, record := range records {
+		var err error
+		var imported bool
+		switch record.Kind {
+		case historyKindExperiment:
+			imported, err = importExperimentRecord(src, record, result)
+		case historyKindPreparation:
+			imported, err = importPreparationRecord(src, record, result)
+		default:
+			err = fmt.Errorf("unknown kind %s", record.Kind)
+		}
+		if err != nil {
+			return result, fmt.Errorf("import the record %d of %s uid failed, %v", idx+1, record.Uid, err)
+		}
+		if imported {
+			result.Imported++
+		} else {
+			result.Skipped++
+		}
+	}
+	return result, nil
+}
+
+func importExperimentRecord(src data.SourceI, record *historyRecord, result *importResult) (bool, error) {
+	if record.Command == "" {
+		return false, fmt.Errorf("the command is empty")
+	}
+	model := record.experimentModel()
+	imported := func(uid string) (bool, bool, error) {
+		existing, err := src.QueryExperimentModelByUid(uid)
+		if err != nil || existing == nil {
+			return false, false, err
+		}
+		return true, existing.CreateTime == model.CreateTime &&
+			existing.Command == model.Command && existing.SubCommand == model.SubCommand, nil
+	}
+	used, same, err := imported(model.Uid)
+	if err != nil || same {
+		return false, err
+	}
+	labels := make(map[string]string, len(model.Labels)+2)
+	for key, value := range model.Labels {
+		labels[key] = value
+	}
+	if used || model.Uid == "" {
+		uid, same, err := importUid(record, imported)
+		if err != nil || same {
+			return false, err
+		}
+		if model.Uid != "" {
+			result.renamed(model.Uid, uid)
+			labels[OriginUidLabel] = model.Uid
+		}
+		model.Uid = uid
+	}
+	if _, ok := labels[HostLabel]; !ok && record.Host != "" {
+		labels[HostLabel] = record.Host
+	}
+	model.Labels = labels
+	return true, src.InsertExperimentModel(model)
+}
+
+func importPreparationRecord(src data.SourceI, record *historyRecord, result *importResult) (bool, error) {
+	if record.ProgramType == "" {
+		return false, fmt.Errorf("the program type is empty")
+	}
+	preparation := record.preparationRecord()
+	imported := func(uid string) (bool, bool, error) {
+		existing, err := src.QueryPreparationByUid(uid)
+		if err != nil || existing == nil {
+			return false, false, err
+		}
+		return true, existing.CreateTime == preparation.CreateTime && existing.ProgramType == preparation.ProgramType, nil
+	}
+	used, same, err := imported(preparation.Uid)
+	if err != nil || same {
+		return false, err
+	}
+	if used || preparation.Uid == "" {
+		uid, same, err := importUid(record, imported)
+		if err != nil || same {
+			return false, err
+		}
+		if preparation.Uid != "" {
+			result.renamed(preparation.Uid, uid)
+		}
+		preparation.Uid = uid
+	}
+	return true, src.InsertPreparationRecord(preparation)
+}
+
+func (r *importResult) renamed(uid, newUid string) {
+	if r.Renamed == nil {
+		r.Renamed = make(map[string]string)
+	}
+	r.Renamed[uid] = newUid
+}
+
+// importUid returns the new uid of the record whose uid is used by another record. The uid is derived from the
+// origin of the record, which is the host, uid, create time and command, so the renamed record gets the same uid and
+// is skipped when it is imported again. The imported func returns whether the uid is used, and whether it is used by
+// the same record. A random uid is generated if the derived uid is used by another record.
+func importUid(record *historyRecord, imported func(uid string) (used, same bool, err error)) (string, bool, error) {
+	digest := sha256.Sum256([]byte(strings.Join([]string{record.Kind, record.Host, record.Uid, record.CreateTime,
+		record.Command, record.SubCommand, record.ProgramType}, "\x00")))
+	uid := hex.EncodeToString(digest[:8])
+	for {
+		used, same, err := imported(uid)
+		if err != nil || same {
+			return uid, same, err
+		}
+		if !used {
+			return uid, false, nil
+		}
+		if uid, err = util.GenerateUid(); err != nil {
+			return "", false, err
+		}
+	}
+}
+
+func historyImportExample() string {
+	return `# Merge the history exported from the hosts into one data file
+CHAOSBLADE_DATASOURCE=sqlite:///data/fleet.dat blade history import -f host-a.ndjson
+CHAOSBLADE_DATASOURCE=sqlite:///data/fleet.dat blade history import -f host-b.csv`
+}
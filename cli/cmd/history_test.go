@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+func testHistoryRecords() []*historyRecord {
+	return []*historyRecord{
+		{
+			Kind: historyKindExperiment, Uid: "e1", Host: "host-a", Command: "cpu", SubCommand: "fullload",
+			Flag: " --cpu-percent=50", Flags: map[string]string{"cpu-percent": "50"}, GroupUid: "g1",
+			Labels: map[string]string{"team": "sre"}, Status: Destroyed,
+			CreateTime: "2025-01-01T00:00:00Z", UpdateTime: "2025-01-01T00:10:00Z",
+		},
+		{
+			Kind: historyKindPreparation, Uid: "p1", Host: "host-a", ProgramType: "jvm", Process: "tomcat",
+			Port: "8080", Pid: "100", Status: Revoked, Error: "revoke failed, \"quoted\", comma",
+			CreateTime: "2025-01-02T00:00:00Z", UpdateTime: "2025-01-02T00:00:00Z",
+		},
+	}
+}
+
+func Test_historyRecords_roundTrip(t *testing.T) {
+	for _, format := range []string{HistoryFormatJSON, HistoryFormatNDJSON, HistoryFormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			if err := writeHistoryRecords(buffer, format, testHistoryRecords()); err != nil {
+				t.Fatalf("write records failed, %v", err)
+			}
+			// the format is detected by the content
+			records, err := readHistoryRecords(buffer, "")
+			if err != nil {
+				t.Fatalf("read records failed, %v", err)
+			}
+			if !reflect.DeepEqual(records, testHistoryRecords()) {
+				t.Errorf("unexpected records %+v", records)
+			}
+		})
+	}
+}
+
+func Test_parseSince(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		expect  time.Time
+		wantErr bool
+	}{
+		{"2025-01-01T00:00:00Z", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"1d", now.Add(-24 * time.Hour), false},
+		{"yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if (err != nil) != tt.wantErr || !got.Equal(tt.expect) {
+			t.Errorf("parseSince(%s) = %v, %v, expect %v", tt.value, got, err, tt.expect)
+		}
+	}
+}
+
+func Test_importHistoryRecords(t *testing.T) {
+	src := data.NewMemorySource()
+	// the same experiment imported before and a different experiment using the uid of the preparation
+	if err := src.InsertExperimentModel(testHistoryRecords()[0].experimentModel()); err != nil {
+		t.Fatalf("insert experiment failed, %v", err)
+	}
+	if err := src.InsertPreparationRecord(&data.PreparationRecord{
+		Uid: "p1", ProgramType: "cplus", CreateTime: "2024-01-01T00:00:00Z",
+	}); err != nil {
+		t.Fatalf("insert preparation failed, %v", err)
+	}
+	records := append(testHistoryRecords(), &historyRecord{
+		Kind: historyKindExperiment, Uid: "e1", Host: "host-b", Command: "mem", SubCommand: "load",
+		Status: Destroyed, CreateTime: "2025-01-03T00:00:00Z",
+	})
+	result, err := importHistoryRecords(src, records)
+	if err != nil {
+		t.Fatalf("import records failed, %v", err)
+	}
+	if result.Imported != 2 || result.Skipped != 1 || len(result.Renamed) != 2 {
+		t.Fatalf("unexpected import result %+v", result)
+	}
+	preparation, _ := src.QueryPreparationByUid(result.Renamed["p1"])
+	if preparation == nil || preparation.ProgramType != "jvm" || preparation.Process != "tomcat" {
+		t.Errorf("unexpected imported preparation %+v", preparation)
+	}
+	model, _ := src.QueryExperimentModelByUid(result.Renamed["e1"])
+	expectLabels := map[string]string{HostLabel: "host-b", OriginUidLabel: "e1"}
+	if model == nil || model.Command != "mem" || !reflect.DeepEqual(model.Labels, expectLabels) {
+		t.Errorf("unexpected imported experiment %+v", model)
+	}
+	// import again, the renamed records are skipped too
+	renamed := result.Renamed
+	if result, err = importHistoryRecords(src, records); err != nil || result.Imported != 0 || result.Skipped != 3 {
+		t.Errorf("unexpected result of importing again %+v, %v", result, err)
+	}
+	if models, _ := src.QueryExperimentModels("", "", "", "", "", true); len(models) != 2 {
+		t.Errorf("unexpected experiments after importing again %d", len(models))
+	}
+	if preparation, _ := src.QueryPreparationByUid(renamed["p1"]); preparation == nil {
+		t.Errorf("the renamed preparation %s is not found", renamed["p1"])
+	}
+}
+
+func Test_checkImportDataSource(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "chaosblade.dat")
+	t.Setenv("CHAOSBLADE_DATAFILE_PATH", dataFile)
+	tests := []struct {
+		dataSource  string
+		expectedErr bool
+	}{
+		{"", true},
+		{"sqlite://", true},
+		{"sqlite://" + dataFile, true},
+		{"sqlite:///data/fleet.dat", false},
+		{"file:///data/fleet.jsonl", false},
+		{"ftp://fleet", true},
+	}
+	for _, tt := range tests {
+		t.Setenv(data.DataSourceEnv, tt.dataSource)
+		if err := checkImportDataSource(); (err != nil) != tt.expectedErr {
+			t.Errorf("%s: unexpected error: %v", tt.dataSource, err)
+		}
+	}
+}
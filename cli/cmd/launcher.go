@@ -0,0 +1,57 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+)
+
+// processLauncher starts the blade commands in the background, such as the watchers of the experiments
+type processLauncher interface {
+	// launch starts `blade args...` with the env appended to the environment of the blade, the process keeps
+	// running after the blade exits. The returned function waits for the process to exit.
+	launch(env []string, args ...string) (func() error, error)
+}
+
+// nohupLauncher launches the processes by nohup and discards their output
+type nohupLauncher struct{}
+
+func (nohupLauncher) launch(env []string, args ...string) (func() error, error) {
+	command := exec.Command("nohup", append([]string{path.Join(util.GetProgramPath(), "blade")}, args...)...)
+	if len(env) > 0 {
+		command.Env = append(os.Environ(), env...)
+	}
+	if err := command.Start(); err != nil {
+		return nil, err
+	}
+	return command.Wait, nil
+}
+
+// launchInBackground launches the process which is not waited by the caller, it is released after exiting
+// if the blade is still running, such as the blade server
+func launchInBackground(launcher processLauncher, env []string, args ...string) error {
+	wait, err := launcher.launch(env, args...)
+	if err != nil {
+		return err
+	}
+	go wait()
+	return nil
+}
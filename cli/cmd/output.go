@@ -0,0 +1,356 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+const (
+	OutputFlag = "output"
+
+	OutputTable = "table"
+	OutputWide  = "wide"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+
+	outputJSONPathPrefix   = "jsonpath="
+	outputGoTemplatePrefix = "go-template="
+)
+
+// outputFormat is the value of the global --output flag, the empty value keeps the default output of the commands
+var outputFormat string
+
+// outputPrinter prints the response in the format of the --output flag. The json and yaml formats print the whole
+// response, the table, wide, jsonpath and go-template formats print the result.
+type outputPrinter struct {
+	format   string
+	template string
+}
+
+func newOutputPrinter(output string) (*outputPrinter, error) {
+	switch output {
+	case OutputTable, OutputWide, OutputJSON, OutputYAML:
+		return &outputPrinter{format: output}, nil
+	}
+	for _, prefix := range []string{outputJSONPathPrefix, outputGoTemplatePrefix} {
+		if tmpl, found := strings.CutPrefix(output, prefix); found {
+			if tmpl == "" {
+				return nil, fmt.Errorf("the template of %s is empty", strings.TrimSuffix(prefix, "="))
+			}
+			return &outputPrinter{format: strings.TrimSuffix(prefix, "="), template: tmpl}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported output format, only support table, wide, json, yaml, jsonpath=TEMPLATE and go-template=TEMPLATE")
+}
+
+// printResponse prints the response in the format of the --output flag, or by the defaultPrint function if the flag
+// is not set
+func printResponse(cmd *cobra.Command, response *spec.Response, defaultPrint func()) error {
+	if outputFormat == "" {
+		defaultPrint()
+		return nil
+	}
+	printer, err := newOutputPrinter(outputFormat)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, OutputFlag, outputFormat, err)
+	}
+	// the errors are mostly of the templates which do not match the result
+	if err := printer.print(cmd.OutOrStdout(), response); err != nil {
+		return spec.ResponseFailWithFlags(spec.ParameterInvalid, OutputFlag, outputFormat, err)
+	}
+	return nil
+}
+
+func (p *outputPrinter) print(writer io.Writer, response *spec.Response) error {
+	switch p.format {
+	case OutputJSON:
+		bytes, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(writer, string(bytes))
+		return err
+	case OutputYAML:
+		value, err := toJSONValue(response)
+		if err != nil {
+			return err
+		}
+		bytes, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(bytes)
+		return err
+	case OutputTable, OutputWide:
+		header, rows, err := tableOf(response.Result, p.format == OutputWide)
+		if err != nil {
+			return err
+		}
+		table := tablewriter.NewWriter(writer)
+		table.SetHeader(header)
+		table.SetAutoWrapText(false)
+		table.SetAutoFormatHeaders(false)
+		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetBorder(false)
+		table.SetHeaderLine(false)
+		table.SetColumnSeparator("")
+		table.SetCenterSeparator("")
+		table.SetRowSeparator("")
+		table.SetTablePadding("   ")
+		table.SetNoWhiteSpace(true)
+		table.AppendBulk(rows)
+		table.Render()
+		return nil
+	case "jsonpath":
+		value, err := toJSONValue(response.Result)
+		if err != nil {
+			return err
+		}
+		tmpl := p.template
+		// the relaxed template without braces, such as .status
+		if !strings.Contains(tmpl, "{") {
+			tmpl = "{" + tmpl + "}"
+		}
+		parser := jsonpath.New(OutputFlag)
+		if err := parser.Parse(tmpl); err != nil {
+			return err
+		}
+		if err := parser.Execute(writer, value); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(writer)
+		return err
+	case "go-template":
+		value, err := toJSONValue(response.Result)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New(OutputFlag).Parse(p.template)
+		if err != nil {
+			return err
+		}
+		if err := tmpl.Execute(writer, value); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(writer)
+		return err
+	}
+	return fmt.Errorf("unsupported output format %s", p.format)
+}
+
+// toJSONValue converts the value to the maps and slices decoded from its JSON, so the field names are the JSON names
+func toJSONValue(value interface{}) (interface{}, error) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	err = json.Unmarshal(bytes, &decoded)
+	return decoded, err
+}
+
+// tableOf returns the header and rows of the result. The experiments and preparations have their columns and
+// the wide format adds the details, the other results are printed as the key value rows or the columns of the keys.
+func tableOf(result interface{}, wide bool) ([]string, [][]string, error) {
+	switch records := result.(type) {
+	case *data.ExperimentModel:
+		header, rows := experimentTable([]*data.ExperimentModel{records}, wide)
+		return header, rows, nil
+	case []*data.ExperimentModel:
+		header, rows := experimentTable(records, wide)
+		return header, rows, nil
+	case *data.PreparationRecord:
+		header, rows := preparationTable([]*data.PreparationRecord{records}, wide)
+		return header, rows, nil
+	case []*data.PreparationRecord:
+		header, rows := preparationTable(records, wide)
+		return header, rows, nil
+	case []*destroyResult:
+		header, rows := destroyResultTable(records)
+		return header, rows, nil
+	case *doctorResult:
+		header, rows := doctorTable(records.Findings, wide)
+		return header, rows, nil
+	}
+	value, err := toJSONValue(result)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch values := value.(type) {
+	case map[string]interface{}:
+		rows := make([][]string, 0, len(values))
+		for _, key := range sortedKeys(values) {
+			rows = append(rows, []string{key, tableCell(values[key])})
+		}
+		return []string{"KEY", "VALUE"}, rows, nil
+	case []interface{}:
+		return sliceTable(values)
+	}
+	return []string{"VALUE"}, [][]string{{tableCell(value)}}, nil
+}
+
+func experimentTable(models []*data.ExperimentModel, wide bool) ([]string, [][]string) {
+	header := []string{"UID", "TARGET", "ACTION", "STATUS", "CREATE TIME"}
+	if wide {
+		header = append(header, "GROUP", "LABELS", "FLAG", "ERROR", "UPDATE TIME")
+	}
+	rows := make([][]string, 0, len(models))
+	for _, model := range models {
+		row := []string{model.Uid, model.Command, model.SubCommand, model.Status, model.CreateTime}
+		if wide {
+			row = append(row, model.GroupUid, joinKeyValues(model.Labels), model.Flag, model.Error, model.UpdateTime)
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+func preparationTable(records []*data.PreparationRecord, wide bool) ([]string, [][]string) {
+	header := []string{"UID", "TYPE", "PROCESS", "PORT", "STATUS", "CREATE TIME"}
+	if wide {
+		header = append(header, "PID", "ERROR", "UPDATE TIME")
+	}
+	rows := make([][]string, 0, len(records))
+	for _, record := range records {
+		row := []string{record.Uid, record.ProgramType, record.Process, record.Port, record.Status, record.CreateTime}
+		if wide {
+			row = append(row, record.Pid, record.Error, record.UpdateTime)
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+func destroyResultTable(results []*destroyResult) ([]string, [][]string) {
+	header := []string{"UID", "TARGET", "RESULT", "ERROR", "REMOVE ERROR"}
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		state := "destroyed"
+		if !result.Success {
+			state = "failed"
+		}
+		rows = append(rows, []string{result.Uid, result.Target, state, result.Error, result.RemoveError})
+	}
+	return header, rows
+}
+
+func doctorTable(findings []*doctorFinding, wide bool) ([]string, [][]string) {
+	header := []string{"KIND", "UID", "TARGET", "CHECK", "STATE", "ACTION"}
+	if wide {
+		header = append(header, "STATUS", "REASON", "ERROR")
+	}
+	rows := make([][]string, 0, len(findings))
+	for _, finding := range findings {
+		row := []string{finding.Kind, finding.Uid, finding.Target, finding.Check, finding.State, finding.Action}
+		if wide {
+			row = append(row, finding.Status, finding.Reason, finding.Error)
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+// sliceTable prints the objects with the columns of all their keys, or the values in one column
+func sliceTable(values []interface{}) ([]string, [][]string, error) {
+	columns := make(map[string]interface{})
+	for _, value := range values {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			columns = nil
+			break
+		}
+		for key := range object {
+			columns[key] = nil
+		}
+	}
+	rows := make([][]string, 0, len(values))
+	if len(columns) == 0 {
+		for _, value := range values {
+			rows = append(rows, []string{tableCell(value)})
+		}
+		return []string{"VALUE"}, rows, nil
+	}
+	keys := sortedKeys(columns)
+	header := make([]string, 0, len(keys))
+	for _, key := range keys {
+		header = append(header, strings.ToUpper(key))
+	}
+	for _, value := range values {
+		object := value.(map[string]interface{})
+		row := make([]string, 0, len(keys))
+		for _, key := range keys {
+			row = append(row, tableCell(object[key]))
+		}
+		rows = append(rows, row)
+	}
+	return header, rows, nil
+}
+
+// tableCell prints the scalar value directly and the others as JSON
+func tableCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(bytes)
+}
+
+// joinKeyValues returns the comma separated key=value pairs order by key
+func joinKeyValues(values map[string]string) string {
+	pairs := make([]string, 0, len(values))
+	for key, value := range values {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
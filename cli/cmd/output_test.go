@@ -0,0 +1,76 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+func Test_outputPrinter_print(t *testing.T) {
+	models := []*data.ExperimentModel{
+		{Uid: "uid1", Command: "cpu", SubCommand: "fullload", Status: Success, Labels: map[string]string{"team": "sre", "env": "dev"}},
+		{Uid: "uid2", Command: "mem", SubCommand: "load", Status: Destroyed},
+	}
+	tests := []struct {
+		output   string
+		result   interface{}
+		expected []string
+	}{
+		{"table", models, []string{"UID", "STATUS", "uid1", "fullload", "uid2", "Destroyed"}},
+		{"wide", models, []string{"LABELS", "env=dev,team=sre"}},
+		{"table", &data.PreparationRecord{Uid: "uid3", ProgramType: "jvm", Status: Running}, []string{"TYPE", "uid3", "jvm"}},
+		{"table", map[string]string{"status": "up", "port": "9526"}, []string{"KEY", "VALUE", "port", "9526"}},
+		{"table", []string{"eth0", "lo"}, []string{"VALUE", "eth0", "lo"}},
+		{"json", models[1], []string{`"code": 200`, `"Uid": "uid2"`}},
+		{"yaml", models[1], []string{"code: 200", "Uid: uid2"}},
+		{"jsonpath={.Status}", models[0], []string{"Success"}},
+		{"jsonpath={range [*]}{.Uid} {end}", models, []string{"uid1 uid2"}},
+		{"jsonpath=.Uid", models[0], []string{"uid1"}},
+		{"go-template={{range .}}{{.Uid}},{{end}}", models, []string{"uid1,uid2,"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			printer, err := newOutputPrinter(tt.output)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			buffer := &bytes.Buffer{}
+			if err := printer.print(buffer, spec.ReturnSuccess(tt.result)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(buffer.String(), expected) {
+					t.Errorf("the output does not contain %q: %s", expected, buffer.String())
+				}
+			}
+		})
+	}
+}
+
+func Test_newOutputPrinter_illegal(t *testing.T) {
+	for _, output := range []string{"xml", "jsonpath=", "go-template="} {
+		if _, err := newOutputPrinter(output); err == nil {
+			t.Errorf("expected the %s output refused", output)
+		}
+	}
+}
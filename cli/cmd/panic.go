@@ -0,0 +1,215 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+)
+
+const (
+	// PanicLockPathEnv overrides the path of the panic lock file, which is in the program directory by default
+	PanicLockPathEnv = "CHAOSBLADE_PANIC_LOCK_PATH"
+	panicLockFile    = "chaosblade.panic.lock"
+
+	ReleaseFlag = "release"
+)
+
+// ChaosPanicLocked is returned when creating experiments after blade panic and before the lock is released
+var ChaosPanicLocked = spec.CodeType{Code: 43003,
+	Msg: "all chaos experiments are stopped by blade panic at %s, run `blade panic --release` before creating experiments"}
+
+// PanicCommand destroys all active experiments and revokes all preparations, and refuses the new experiments
+// until the lock is released
+type PanicCommand struct {
+	baseCommand
+	release bool
+}
+
+// panicLock is the content of the lock file
+type panicLock struct {
+	Time string `json:"time"`
+	User string `json:"user,omitempty"`
+	Pid  int    `json:"pid"`
+}
+
+// revokeResult is the revoking result of a preparation
+type revokeResult struct {
+	Uid     string `json:"uid"`
+	Type    string `json:"type"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// panicResult contains the lock file and the results of the experiments and preparations
+type panicResult struct {
+	LockFile     string           `json:"lockFile"`
+	Experiments  []*destroyResult `json:"experiments"`
+	Preparations []*revokeResult  `json:"preparations"`
+}
+
+func (pc *PanicCommand) Init() {
+	pc.command = &cobra.Command{
+		Use:   "panic",
+		Short: "Stop all chaos experiments on the host now",
+		Long: "Stop all chaos experiments on the host now. It locks the new experiments, destroys all Created and " +
+			"Success experiments, then revokes all Running preparations, so the jvm rules are destroyed before " +
+			"the sandboxes are detached and the cplus experiments are destroyed before the proxies are revoked. " +
+			"The experiments can not be created until the lock is released by --release.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if pc.release {
+				return pc.runRelease(cmd)
+			}
+			return pc.runPanic(cmd)
+		},
+		Example: panicExample(),
+	}
+	pc.command.Flags().BoolVar(&pc.release, ReleaseFlag, false, "Release the lock to create experiments again")
+}
+
+func (pc *PanicCommand) runPanic(cmd *cobra.Command) error {
+	destroyCommand := &DestroyCommand{}
+	destroyCommand.Init()
+	response := stopAllChaos(destroyCommand)
+	if !response.Success {
+		return response
+	}
+	cmd.Println(response.Print())
+	return nil
+}
+
+func (pc *PanicCommand) runRelease(cmd *cobra.Command) error {
+	response := releasePanicLock()
+	if !response.Success {
+		return response
+	}
+	cmd.Println(response.Print())
+	return nil
+}
+
+// stopAllChaos takes the lock, destroys the active experiments and then revokes the running preparations.
+// The lock is kept even if some of them fail.
+func stopAllChaos(dc *DestroyCommand) *spec.Response {
+	ctx := context.Background()
+	lockFile, err := writePanicLock(time.Now())
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, "lock", err)
+	}
+	log.Warnf(ctx, "stop all chaos experiments, the lock file is %s", lockFile)
+	result := &panicResult{LockFile: lockFile, Preparations: make([]*revokeResult, 0)}
+	filter := &bulkDestroyFilter{statuses: []string{Created, Success}}
+	models, err := filter.selectExperiments(GetDS())
+	if err != nil {
+		return err.(*spec.Response)
+	}
+	result.Experiments = dc.destroyExperimentsInParallel(models, 4)
+	failed := 0
+	for _, experiment := range result.Experiments {
+		if !experiment.Success {
+			failed++
+		}
+	}
+	// the preparations are revoked after all experiments, which may be executed by them
+	records, err := GetDS().QueryPreparationRecords("", Running, "", "", "", true)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DbQueryFailed, "preparations", err)
+	}
+	for _, record := range records {
+		revoked := &revokeResult{Uid: record.Uid, Type: record.ProgramType, Success: true}
+		if response := revokePreparation(record.Uid); !response.Success {
+			revoked.Success = false
+			revoked.Error = response.Err
+			failed++
+		}
+		result.Preparations = append(result.Preparations, revoked)
+	}
+	if failed > 0 {
+		return &spec.Response{
+			Code:    spec.OsCmdExecFailed.Code,
+			Success: false,
+			Err:     fmt.Sprintf("stop %d experiments or preparations failed, the experiments are still locked", failed),
+			Result:  result,
+		}
+	}
+	return spec.ReturnSuccess(result)
+}
+
+// getPanicLockPath returns the path of the lock file
+func getPanicLockPath() string {
+	if lockPath := os.Getenv(PanicLockPathEnv); lockPath != "" {
+		return lockPath
+	}
+	return path.Join(util.GetProgramPath(), panicLockFile)
+}
+
+// writePanicLock writes the lock file and returns its path
+func writePanicLock(now time.Time) (string, error) {
+	lock := &panicLock{Time: now.Format(time.RFC3339), Pid: os.Getpid()}
+	if current, err := user.Current(); err == nil {
+		lock.User = current.Username
+	}
+	bytes, err := json.Marshal(lock)
+	if err != nil {
+		return "", err
+	}
+	lockPath := getPanicLockPath()
+	return lockPath, os.WriteFile(lockPath, bytes, 0o644)
+}
+
+// releasePanicLock removes the lock file, it succeeds if the lock does not exist
+func releasePanicLock() *spec.Response {
+	lockPath := getPanicLockPath()
+	if err := os.Remove(lockPath); err != nil && !os.IsNotExist(err) {
+		return spec.ResponseFailWithFlags(spec.OsCmdExecFailed, "release", err)
+	}
+	return spec.ReturnSuccess(lockPath)
+}
+
+// checkPanicLock returns the failed response if the experiments are locked by blade panic.
+// The lock file which can not be read is also regarded as locked.
+func checkPanicLock() *spec.Response {
+	bytes, err := os.ReadFile(getPanicLockPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	lock := &panicLock{Time: "unknown time"}
+	if err == nil {
+		if err := json.Unmarshal(bytes, lock); err != nil || lock.Time == "" {
+			lock.Time = "unknown time"
+		}
+	}
+	return spec.ResponseFailWithFlags(ChaosPanicLocked, lock.Time)
+}
+
+func panicExample() string {
+	return `# Destroy all experiments, revoke all preparations and refuse the new experiments
+blade panic
+
+# Allow creating experiments again
+blade panic --release`
+}
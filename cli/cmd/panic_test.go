@@ -0,0 +1,85 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+func Test_stopAllChaos(t *testing.T) {
+	t.Setenv(PanicLockPathEnv, filepath.Join(t.TempDir(), panicLockFile))
+	defer SetDS(&MockSource{})
+	src := data.NewMemorySource()
+	for _, model := range []*data.ExperimentModel{
+		{Uid: "created", Command: "unknown", SubCommand: "action", Status: Created},
+		{Uid: "success", Command: "unknown", SubCommand: "action", Status: Success},
+		{Uid: "destroyed", Command: "unknown", SubCommand: "action", Status: Destroyed},
+	} {
+		if err := src.InsertExperimentModel(model); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+	}
+	if err := src.InsertPreparationRecord(&data.PreparationRecord{
+		Uid: "unknown", ProgramType: "unknown", Status: Running,
+	}); err != nil {
+		t.Fatalf("insert preparation failed, %v", err)
+	}
+	SetDS(src)
+	dc := &DestroyCommand{}
+	dc.Init()
+
+	if response := checkPanicLock(); response != nil {
+		t.Fatalf("unexpected lock: %v", response)
+	}
+	response := stopAllChaos(dc)
+	if response.Success {
+		t.Fatalf("expected the failures of the unknown executors")
+	}
+	result := response.Result.(*panicResult)
+	if len(result.Experiments) != 2 || len(result.Preparations) != 1 || result.Preparations[0].Success {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if response := checkPanicLock(); response == nil || response.Code != ChaosPanicLocked.Code {
+		t.Errorf("expected the panic lock, got %v", response)
+	}
+
+	handler := newAPIServer(nil).Handler()
+	createRequest := func() int {
+		request := httptest.NewRequest(http.MethodPost, "/v1/experiments",
+			strings.NewReader(`{"target":"cpu","action":"fullload"}`))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+	if code := createRequest(); code != http.StatusLocked {
+		t.Errorf("unexpected status code when locked: %d", code)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/v1/panic", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("unexpected status code of releasing: %d, body: %s", recorder.Code, recorder.Body.String())
+	}
+	if code := createRequest(); code != http.StatusBadRequest {
+		t.Errorf("unexpected status code when released: %d", code)
+	}
+}
@@ -0,0 +1,323 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/process"
+	"gopkg.in/yaml.v2"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+const (
+	// PolicyFileEnv overrides the path of the policy file
+	PolicyFileEnv     = "CHAOSBLADE_POLICY_FILE"
+	defaultPolicyFile = "/etc/chaosblade/policy.yaml"
+)
+
+var (
+	// PolicyViolated is returned if the experiment violates the policy file
+	PolicyViolated = spec.CodeType{Code: 43004, Msg: "the experiment violates the policy %s, %s"}
+	// PolicyInvalid is returned if the policy file can not be loaded, no experiment is allowed until it is fixed
+	PolicyInvalid = spec.CodeType{Code: 43005, Msg: "the policy %s is invalid, %v"}
+)
+
+// experimentPolicy limits the blast radius of the experiments created on the host, for example:
+//
+//	deny:
+//	  - "process kill"
+//	  - {scope: node, target: "*", action: "*"}
+//	maxFlags:
+//	  cpu-percent: 80
+//	  mem-percent: 70
+//	protectedProcesses: ["sshd", "systemd"]
+//	protectedInterfaces: ["eth0"]
+//	protectedNamespaces: ["kube-system"]
+//	requireTimeout: true
+type experimentPolicy struct {
+	// Deny contains the experiments which must not be created
+	Deny []*denyRule `yaml:"deny"`
+	// MaxFlags are the max values of the numeric flags, the flag of the action must have the value or the default
+	MaxFlags map[string]float64 `yaml:"maxFlags"`
+	// ProtectedProcesses are the names of the processes which can not be the targets by process, process-cmd or pid.
+	// The processes matched by the process and process-cmd flags are checked, and the flag values are also compared
+	// with the names, so the processes not running yet are protected.
+	ProtectedProcesses []string `yaml:"protectedProcesses"`
+	// ProtectedInterfaces are the network interfaces which can not be the targets
+	ProtectedInterfaces []string `yaml:"protectedInterfaces"`
+	// ProtectedNamespaces are the kubernetes namespaces which can not be the targets
+	ProtectedNamespaces []string `yaml:"protectedNamespaces"`
+	// RequireTimeout requires the positive timeout flag, so the experiments are destroyed automatically
+	RequireTimeout bool `yaml:"requireTimeout"`
+}
+
+// denyRule matches the experiments by the patterns of the target, action and scope, such as `process kill` of the
+// host, docker, cri and kubernetes pod scopes. The string form is `target action`, which matches all the scopes.
+type denyRule struct {
+	Target string `yaml:"target"`
+	Action string `yaml:"action"`
+	// Scope is host, docker, cri, pod, container or node, the empty scope matches all
+	Scope string `yaml:"scope"`
+}
+
+func (r *denyRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err == nil {
+		fields := strings.Fields(value)
+		if len(fields) != 2 {
+			return fmt.Errorf("the deny rule %q is not `target action`", value)
+		}
+		r.Target, r.Action = fields[0], fields[1]
+		return nil
+	}
+	type plain denyRule
+	if err := unmarshal((*plain)(r)); err != nil {
+		return err
+	}
+	if r.Target == "" || r.Action == "" {
+		return fmt.Errorf("the target and action of the deny rule are required")
+	}
+	return nil
+}
+
+// matches returns true if the experiment matches the patterns
+func (r *denyRule) matches(expModel *spec.ExpModel) bool {
+	scope := expModel.Scope
+	if scope == "" {
+		scope = "host"
+	}
+	patterns, values := []string{r.Target, r.Action, r.Scope}, []string{expModel.Target, expModel.ActionName, scope}
+	for idx, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		if matched, _ := path.Match(pattern, values[idx]); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// getPolicyFile returns the path of the policy file
+func getPolicyFile() string {
+	if file := os.Getenv(PolicyFileEnv); file != "" {
+		return file
+	}
+	return defaultPolicyFile
+}
+
+// loadExperimentPolicy reads the policy file, it returns nil if the file does not exist
+func loadExperimentPolicy(file string) (*experimentPolicy, error) {
+	bytes, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	policy := &experimentPolicy{}
+	if err := yaml.UnmarshalStrict(bytes, policy); err != nil {
+		return nil, err
+	}
+	for _, rule := range policy.Deny {
+		for _, pattern := range []string{rule.Target, rule.Action, rule.Scope} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("the deny pattern %s is illegal, %v", pattern, err)
+			}
+		}
+	}
+	return policy, nil
+}
+
+// checkExperimentPolicy evaluates the experiment of the action against the policy file before executing,
+// returns nil if the experiment is allowed or there is no policy file
+func checkExperimentPolicy(expModel *spec.ExpModel, actionSpec spec.ExpActionCommandSpec) *spec.Response {
+	file := getPolicyFile()
+	policy, err := loadExperimentPolicy(file)
+	if err != nil {
+		return spec.ResponseFailWithFlags(PolicyInvalid, file, err)
+	}
+	if policy == nil {
+		return nil
+	}
+	if reason := policy.violation(expModel, actionFlagDefaults(actionSpec), listProcesses); reason != "" {
+		return spec.ResponseFailWithFlags(PolicyViolated, file, reason)
+	}
+	return nil
+}
+
+// actionFlagDefaults returns the default values of the flags of the action, which are empty if there is no default
+func actionFlagDefaults(actionSpec spec.ExpActionCommandSpec) map[string]string {
+	defaults := make(map[string]string)
+	if actionSpec == nil {
+		return defaults
+	}
+	for _, flag := range append(append([]spec.ExpFlagSpec{}, actionSpec.Flags()...), actionSpec.Matchers()...) {
+		defaults[flag.FlagName()] = flag.FlagDefault()
+	}
+	return defaults
+}
+
+// policyProcess is a running process checked by the protected processes
+type policyProcess struct {
+	pid     int32
+	name    string
+	cmdline string
+}
+
+// listProcesses returns the running processes
+func listProcesses() ([]*policyProcess, error) {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil, err
+	}
+	processes := make([]*policyProcess, 0, len(procs))
+	for _, proc := range procs {
+		name, err := proc.Name()
+		if err != nil {
+			continue
+		}
+		cmdline, _ := proc.Cmdline()
+		processes = append(processes, &policyProcess{pid: proc.Pid, name: name, cmdline: cmdline})
+	}
+	return processes, nil
+}
+
+// violation returns the reason why the experiment is not allowed, or empty if it is allowed.
+// The flagDefaults are the default values of the flags of the action.
+func (p *experimentPolicy) violation(expModel *spec.ExpModel, flagDefaults map[string]string,
+	listProcesses func() ([]*policyProcess, error),
+) string {
+	for _, rule := range p.Deny {
+		if rule.matches(expModel) {
+			command, subCommand := getCommandAndSubCommand(expModel)
+			return fmt.Sprintf("`%s %s` is denied", command, subCommand)
+		}
+	}
+	flags := expModel.ActionFlags
+	if p.RequireTimeout {
+		if timeout, err := parseTimeout(flags["timeout"]); flags["timeout"] == "" || err != nil || timeout == 0 {
+			return "the timeout flag is required"
+		}
+	}
+	names := make([]string, 0, len(p.MaxFlags))
+	for name := range p.MaxFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		flagValue := flags[name]
+		if strings.TrimSpace(flagValue) == "" {
+			defaultValue, declared := flagDefaults[name]
+			if !declared {
+				continue
+			}
+			// the executor uses its own value if the flag has no default, which is not limited
+			if defaultValue == "" {
+				return fmt.Sprintf("the %s flag is required by the max value %v", name, p.MaxFlags[name])
+			}
+			flagValue = defaultValue
+		}
+		number, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(flagValue), "%"), 64)
+		if err != nil {
+			return fmt.Sprintf("the %s flag value %s is not a number", name, flagValue)
+		}
+		if number > p.MaxFlags[name] {
+			return fmt.Sprintf("the %s flag value %s exceeds the max value %v", name, flagValue, p.MaxFlags[name])
+		}
+	}
+	if reason := p.protectedProcessViolation(flags, listProcesses); reason != "" {
+		return reason
+	}
+	for _, name := range splitFlagValues(flags["interface"]) {
+		if containsString(p.ProtectedInterfaces, name) {
+			return fmt.Sprintf("the %s interface is protected", name)
+		}
+	}
+	for _, name := range splitFlagValues(flags["namespace"]) {
+		if containsString(p.ProtectedNamespaces, name) {
+			return fmt.Sprintf("the %s namespace is protected", name)
+		}
+	}
+	return ""
+}
+
+// protectedProcessViolation returns the reason if the processes matched by the process, process-cmd or pid flags
+// are protected. The process flag matches the command lines containing it and the process-cmd flag matches the name
+// like the executors, and the flag values which contain or are contained by the protected names are also denied.
+func (p *experimentPolicy) protectedProcessViolation(flags map[string]string,
+	listProcesses func() ([]*policyProcess, error),
+) string {
+	if len(p.ProtectedProcesses) == 0 || flags["process"]+flags["process-cmd"]+flags["pid"] == "" {
+		return ""
+	}
+	for _, name := range []string{"process", "process-cmd"} {
+		if protected := matchProtected(flags[name], p.ProtectedProcesses); protected != "" {
+			return fmt.Sprintf("the %s process is protected", protected)
+		}
+	}
+	processes, err := listProcesses()
+	if err != nil {
+		return fmt.Sprintf("the target processes can not be checked, %v", err)
+	}
+	pids := splitFlagValues(flags["pid"])
+	for _, proc := range processes {
+		if !containsString(p.ProtectedProcesses, proc.name) {
+			continue
+		}
+		switch {
+		case flags["process"] != "" && strings.Contains(proc.cmdline, flags["process"]):
+			return fmt.Sprintf("the %s process matched by %s is protected", proc.name, flags["process"])
+		case flags["process-cmd"] != "" && proc.name == flags["process-cmd"]:
+			return fmt.Sprintf("the %s process is protected", proc.name)
+		case containsString(pids, strconv.Itoa(int(proc.pid))):
+			return fmt.Sprintf("the %s process of the pid %d is protected", proc.name, proc.pid)
+		}
+	}
+	return ""
+}
+
+// matchProtected returns the protected name which contains or is contained by the flag value
+func matchProtected(value string, protected []string) string {
+	if value = strings.TrimSpace(value); value == "" {
+		return ""
+	}
+	for _, name := range protected {
+		if name != "" && (strings.Contains(value, name) || strings.Contains(name, value)) {
+			return name
+		}
+	}
+	return ""
+}
+
+// splitFlagValues splits the comma separated flag value
+func splitFlagValues(value string) []string {
+	values := make([]string, 0)
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func Test_experimentPolicy_violation(t *testing.T) {
+	policy := &experimentPolicy{
+		Deny:                []*denyRule{{Target: "process", Action: "kill"}, {Target: "*", Action: "*", Scope: "node"}},
+		MaxFlags:            map[string]float64{"cpu-percent": 80},
+		ProtectedProcesses:  []string{"sshd"},
+		ProtectedInterfaces: []string{"eth0"},
+		ProtectedNamespaces: []string{"kube-system"},
+		RequireTimeout:      true,
+	}
+	listProcesses := func() ([]*policyProcess, error) {
+		return []*policyProcess{
+			{pid: 22, name: "sshd", cmdline: "/usr/sbin/sshd -D"},
+			{pid: 1024, name: "java", cmdline: "java -jar app.jar"},
+		}, nil
+	}
+	tests := []struct {
+		name     string
+		model    *spec.ExpModel
+		defaults map[string]string
+		expected string
+	}{
+		{"allowed", &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+			ActionFlags: map[string]string{"cpu-percent": "60", "timeout": "60"}}, nil, ""},
+		{"denied", &spec.ExpModel{Target: "process", ActionName: "kill",
+			ActionFlags: map[string]string{"process": "java", "timeout": "60"}}, nil, "is denied"},
+		{"denied k8s", &spec.ExpModel{Target: "cpu", Scope: "node", ActionName: "fullload",
+			ActionFlags: map[string]string{"timeout": "60"}}, nil, "is denied"},
+		{"without timeout", &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+			ActionFlags: map[string]string{"cpu-percent": "60"}}, nil, "timeout flag is required"},
+		{"zero timeout", &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+			ActionFlags: map[string]string{"timeout": "0"}}, nil, "timeout flag is required"},
+		{"exceeds", &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+			ActionFlags: map[string]string{"cpu-percent": "100", "timeout": "1m"}}, nil, "exceeds the max value"},
+		{"not a number", &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+			ActionFlags: map[string]string{"cpu-percent": "all", "timeout": "60"}}, nil, "is not a number"},
+		{"protected process", &spec.ExpModel{Target: "process", ActionName: "stop",
+			ActionFlags: map[string]string{"process-cmd": "/usr/sbin/sshd -D", "timeout": "60"}}, nil, "sshd process is protected"},
+		{"protected pid", &spec.ExpModel{Target: "process", ActionName: "stop",
+			ActionFlags: map[string]string{"pid": "1024,22", "timeout": "60"}}, nil, "pid 22 is protected"},
+		{"protected interface", &spec.ExpModel{Target: "network", ActionName: "loss",
+			ActionFlags: map[string]string{"interface": "eth0", "percent": "50", "timeout": "60"}}, nil, "eth0 interface is protected"},
+		{"protected namespace", &spec.ExpModel{Target: "cpu", Scope: "pod", ActionName: "fullload",
+			ActionFlags: map[string]string{"namespace": "default,kube-system", "timeout": "60"}}, nil, "kube-system namespace is protected"},
+		{"denied docker", &spec.ExpModel{Target: "process", Scope: "docker", ActionName: "kill",
+			ActionFlags: map[string]string{"process": "java", "timeout": "60"}}, nil, "`docker process kill` is denied"},
+		{"denied k8s pod", &spec.ExpModel{Target: "process", Scope: "pod", ActionName: "kill",
+			ActionFlags: map[string]string{"process": "java", "timeout": "60"}}, nil, "`k8s pod-process kill` is denied"},
+		{"allowed pod", &spec.ExpModel{Target: "cpu", Scope: "pod", ActionName: "fullload",
+			ActionFlags: map[string]string{"timeout": "60"}}, nil, ""},
+		{"default exceeds", &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+			ActionFlags: map[string]string{"timeout": "60"}}, map[string]string{"cpu-percent": "100"}, "value 100 exceeds"},
+		{"default allowed", &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+			ActionFlags: map[string]string{"cpu-percent": "", "timeout": "60"}}, map[string]string{"cpu-percent": "50"}, ""},
+		{"capped without default", &spec.ExpModel{Target: "cpu", ActionName: "fullload",
+			ActionFlags: map[string]string{"timeout": "60"}}, map[string]string{"cpu-percent": ""}, "cpu-percent flag is required"},
+		{"protected name contains value", &spec.ExpModel{Target: "process", ActionName: "stop",
+			ActionFlags: map[string]string{"process": "ssh", "timeout": "60"}}, nil, "sshd process is protected"},
+		{"protected matched process", &spec.ExpModel{Target: "process", ActionName: "stop",
+			ActionFlags: map[string]string{"process": "/usr/sbin/", "timeout": "60"}}, nil, "sshd process matched by /usr/sbin/"},
+		{"protected process-cmd", &spec.ExpModel{Target: "process", ActionName: "stop",
+			ActionFlags: map[string]string{"process-cmd": "java", "timeout": "60"}}, nil, ""},
+		{"not running pid", &spec.ExpModel{Target: "process", ActionName: "stop",
+			ActionFlags: map[string]string{"pid": "2048", "timeout": "60"}}, nil, ""},
+	}
+	for _, tt := range tests {
+		reason := policy.violation(tt.model, tt.defaults, listProcesses)
+		if (tt.expected == "") != (reason == "") || !strings.Contains(reason, tt.expected) {
+			t.Errorf("%s: unexpected violation %q, expected %q", tt.name, reason, tt.expected)
+		}
+	}
+}
+
+func Test_checkExperimentPolicy(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	t.Setenv(PolicyFileEnv, file)
+	model := &spec.ExpModel{Target: "cpu", ActionName: "fullload", ActionFlags: map[string]string{}}
+	if response := checkExperimentPolicy(model, nil); response != nil {
+		t.Errorf("unexpected response without the policy file: %v", response)
+	}
+	tests := []struct {
+		content  string
+		expected int32
+	}{
+		{"requireTimeout: true\n", PolicyViolated.Code},
+		{"requireTimeout: false\n", 0},
+		{"requireTimeouts: true\n", PolicyInvalid.Code},
+		{"deny: [\"cpu fullload\"]\n", PolicyViolated.Code},
+		{"deny: [{target: cpu, action: fullload, scope: docker}]\n", 0},
+		{"deny: [\"k8s node-cpu fullload\"]\n", PolicyInvalid.Code},
+		{"deny: [{target: \"[\", action: fullload}]\n", PolicyInvalid.Code},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+			t.Fatalf("write policy file failed, %v", err)
+		}
+		response := checkExperimentPolicy(model, nil)
+		code := int32(0)
+		if response != nil {
+			code = response.Code
+		}
+		if code != tt.expected {
+			t.Errorf("%s: unexpected response %v", strings.TrimSpace(tt.content), response)
+		}
+	}
+}
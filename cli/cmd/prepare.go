@@ -86,12 +86,15 @@ func insertPrepareRecord(prepareType string, processName, port, processId string
 func handlePrepareResponseWithoutExit(ctx context.Context, uid string, cmd *cobra.Command, response *spec.Response) error {
 	response.Result = uid
 	if !response.Success {
-		GetDS().UpdatePreparationRecordByUid(uid, Error, response.Err)
+		data.SetEventCode(uid, response.Code)
+		if err := GetDS().UpdatePreparationRecordByUid(uid, Error, response.Err); err != nil {
+			log.Warnf(ctx, "update preparation record error: %s", err.Error())
+		}
 		return response
 	}
-	err := GetDS().UpdatePreparationRecordByUid(uid, Running, "")
-	if err != nil {
+	if err := GetDS().UpdatePreparationRecordByUid(uid, Running, ""); err != nil {
 		log.Warnf(ctx, "update preparation record error: %s", err.Error())
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "update", err)
 	}
 	return nil
 }
@@ -100,13 +103,15 @@ func handlePrepareResponse(ctx context.Context, cmd *cobra.Command, response *sp
 	uid = ctx.Value(spec.Uid).(string)
 	response.Result = uid
 	if !response.Success {
-		GetDS().UpdatePreparationRecordByUid(uid, Error, response.Err)
+		data.SetEventCode(uid, response.Code)
+		if err := GetDS().UpdatePreparationRecordByUid(uid, Error, response.Err); err != nil {
+			log.Warnf(ctx, "update preparation record error: %s", err.Error())
+		}
 		return response
 	}
-	err := GetDS().UpdatePreparationRecordByUid(uid, Running, "")
-	if err != nil {
+	if err := GetDS().UpdatePreparationRecordByUid(uid, Running, ""); err != nil {
 		log.Warnf(ctx, "update preparation record error: %s", err.Error())
-		// log.V(-1).Info("update preparation record error", "err_msg", err.Error())
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "update", err)
 	}
 	response.Result = uid
 	cmd.Println(response.Print())
@@ -144,8 +144,12 @@ func (pc *PrepareJvmCommand) reportAttachedResult(ctx context.Context, response
 	}
 }
 
-// attachAgent
-func (pc *PrepareJvmCommand) attachAgent(ctx context.Context) *spec.Response {
+// attachAgent attaches the sandbox agent and observes the attaching duration
+func (pc *PrepareJvmCommand) attachAgent(ctx context.Context) (response *spec.Response) {
+	start := time.Now()
+	defer func() {
+		sandboxAttachDuration.WithLabelValues(resultLabel(response)).Observe(time.Since(start).Seconds())
+	}()
 	response, username, userid := jvm.Attach(ctx, strconv.Itoa(pc.port), pc.javaHome, pc.processId)
 	if !response.Success && (username != "" || userid != "") && strings.Contains(response.Err, "connection refused") {
 		// if attach failed, search port from ~/.sandbox.token
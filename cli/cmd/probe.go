@@ -0,0 +1,430 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+const (
+	ProbeFlag         = "probe"
+	ProbeIntervalFlag = "probe-interval"
+	WatchFlag         = "watch"
+	IntervalFlag      = "interval"
+	AfterFlag         = "after"
+
+	defaultProbeInterval = "10s"
+	defaultProbeTimeout  = 5 * time.Second
+	// defaultProbeSamples is the samples of the probe which limits the latency percentiles
+	defaultProbeSamples = 10
+)
+
+// The kinds of the probes
+const (
+	probeHttp = "http"
+	probeTcp  = "tcp"
+	probeExec = "exec"
+)
+
+// The phases of the experiment in which the probes are evaluated
+const (
+	probeBefore = "before"
+	probeDuring = "during"
+	probeAfter  = "after"
+)
+
+// SteadyStateViolated is returned if a probe fails before injecting or when evaluated by blade probe
+var SteadyStateViolated = spec.CodeType{Code: 43006, Msg: "the steady state is violated %s, the probe %s failed, %s"}
+
+var percentilePattern = regexp.MustCompile(`^p(\d{1,2}(\.\d+)?|100)<(\S+)$`)
+
+// steadyStateProbe is the parsed definition of a probe, the options follow the target:
+//
+//	http://svc/health expect=200 p99<300ms samples=20 timeout=2s
+//	tcp://10.0.0.1:3306 p95<50ms
+//	exec:pgrep -x nginx expect=0
+type steadyStateProbe struct {
+	kind   string
+	target string
+	// expect is the http status code or the exit code of the command
+	expect  int
+	samples int
+	timeout time.Duration
+	bounds  []latencyBound
+}
+
+// latencyBound limits the latency percentile of the samples
+type latencyBound struct {
+	percentile float64
+	max        time.Duration
+}
+
+// parseProbe parses the probe definition
+func parseProbe(definition string) (*steadyStateProbe, error) {
+	fields := strings.Fields(definition)
+	end := len(fields)
+	for end > 1 && isProbeOption(fields[end-1]) {
+		end--
+	}
+	target := strings.Join(fields[:end], " ")
+	probe := &steadyStateProbe{target: target, samples: 1, timeout: defaultProbeTimeout}
+	switch {
+	case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"):
+		if _, err := url.ParseRequestURI(target); err != nil {
+			return nil, fmt.Errorf("the url of the probe is illegal, %v", err)
+		}
+		probe.kind, probe.expect = probeHttp, http.StatusOK
+	case strings.HasPrefix(target, "tcp://"):
+		probe.kind, probe.target = probeTcp, strings.TrimPrefix(target, "tcp://")
+		if _, _, err := net.SplitHostPort(probe.target); err != nil {
+			return nil, fmt.Errorf("the address of the probe is illegal, %v", err)
+		}
+	case strings.HasPrefix(target, "exec:"):
+		probe.kind, probe.target = probeExec, strings.TrimSpace(strings.TrimPrefix(target, "exec:"))
+		if probe.target == "" {
+			return nil, fmt.Errorf("the command of the probe is empty")
+		}
+	default:
+		return nil, fmt.Errorf("the probe %q must start with http://, https://, tcp:// or exec:", definition)
+	}
+	samplesSet := false
+	for _, option := range fields[end:] {
+		if matches := percentilePattern.FindStringSubmatch(option); matches != nil {
+			percentile, _ := strconv.ParseFloat(matches[1], 64)
+			max, err := time.ParseDuration(matches[3])
+			if err != nil || percentile <= 0 {
+				return nil, fmt.Errorf("the latency bound %s is illegal", option)
+			}
+			probe.bounds = append(probe.bounds, latencyBound{percentile: percentile, max: max})
+			continue
+		}
+		name, value, _ := strings.Cut(option, "=")
+		switch name {
+		case "expect":
+			if probe.kind == probeTcp {
+				return nil, fmt.Errorf("the tcp probe does not support the expect option")
+			}
+			expect, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("the expect option %s is not a number", value)
+			}
+			probe.expect = expect
+		case "samples":
+			samples, err := strconv.Atoi(value)
+			if err != nil || samples < 1 {
+				return nil, fmt.Errorf("the samples option %s must be a positive number", value)
+			}
+			probe.samples, samplesSet = samples, true
+		case "timeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("the timeout option %s must be a positive duration", value)
+			}
+			probe.timeout = timeout
+		}
+	}
+	if len(probe.bounds) > 0 && !samplesSet {
+		probe.samples = defaultProbeSamples
+	}
+	return probe, nil
+}
+
+// isProbeOption returns true if the field of the definition is an option instead of a part of the target
+func isProbeOption(field string) bool {
+	if percentilePattern.MatchString(field) {
+		return true
+	}
+	name, _, found := strings.Cut(field, "=")
+	return found && (name == "expect" || name == "samples" || name == "timeout")
+}
+
+// newProbeRecords validates the probe definitions and returns the probes recorded with the experiment
+func newProbeRecords(definitions []string) ([]*data.Probe, error) {
+	probes := make([]*data.Probe, 0, len(definitions))
+	for _, definition := range definitions {
+		if _, err := parseProbe(definition); err != nil {
+			return nil, err
+		}
+		probes = append(probes, &data.Probe{Definition: strings.Join(strings.Fields(definition), " ")})
+	}
+	return probes, nil
+}
+
+// evaluate samples the probe, it fails if any sample fails or the latency percentiles exceed the bounds
+func (p *steadyStateProbe) evaluate(ctx context.Context) *data.ProbeResult {
+	latencies := make([]time.Duration, 0, p.samples)
+	for i := 0; i < p.samples; i++ {
+		start := time.Now()
+		if err := p.sample(ctx); err != nil {
+			return newProbeResult(false, fmt.Sprintf("sample %d of %d failed, %v", i+1, p.samples, err))
+		}
+		latencies = append(latencies, time.Since(start))
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	success := true
+	messages := []string{fmt.Sprintf("%d samples", p.samples)}
+	for _, bound := range p.bounds {
+		latency := percentileOf(latencies, bound.percentile)
+		message := fmt.Sprintf("p%s %s", strconv.FormatFloat(bound.percentile, 'f', -1, 64), latency.Round(time.Microsecond))
+		if latency > bound.max {
+			success = false
+			message = fmt.Sprintf("%s exceeds %s", message, bound.max)
+		}
+		messages = append(messages, message)
+	}
+	return newProbeResult(success, strings.Join(messages, ", "))
+}
+
+// sample requests the url, connects the address or executes the command once
+func (p *steadyStateProbe) sample(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	switch p.kind {
+	case probeHttp:
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.target, nil)
+		if err != nil {
+			return err
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return err
+		}
+		response.Body.Close()
+		if response.StatusCode != p.expect {
+			return fmt.Errorf("status %d, expect %d", response.StatusCode, p.expect)
+		}
+	case probeTcp:
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", p.target)
+		if err != nil {
+			return err
+		}
+		conn.Close()
+	case probeExec:
+		code := 0
+		if err := exec.CommandContext(ctx, "/bin/sh", "-c", p.target).Run(); err != nil {
+			exitErr, ok := err.(*exec.ExitError)
+			if !ok || ctx.Err() != nil {
+				return err
+			}
+			code = exitErr.ExitCode()
+		}
+		if code != p.expect {
+			return fmt.Errorf("exit code %d, expect %d", code, p.expect)
+		}
+	}
+	return nil
+}
+
+// percentileOf returns the nearest-rank percentile of the sorted latencies
+func percentileOf(latencies []time.Duration, percentile float64) time.Duration {
+	rank := int(math.Ceil(percentile / 100 * float64(len(latencies))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(latencies) {
+		rank = len(latencies)
+	}
+	return latencies[rank-1]
+}
+
+func newProbeResult(success bool, message string) *data.ProbeResult {
+	return &data.ProbeResult{Success: success, Message: message, Time: time.Now().Format(time.RFC3339Nano)}
+}
+
+// probeResultOf returns the result of the probe in the phase
+func probeResultOf(probe *data.Probe, phase string) *data.ProbeResult {
+	switch phase {
+	case probeBefore:
+		return probe.Before
+	case probeDuring:
+		return probe.During
+	}
+	return probe.After
+}
+
+// evaluateProbes evaluates the probes and sets the results of the phase, it returns the first failed probe
+func evaluateProbes(ctx context.Context, probes []*data.Probe, phase string) *data.Probe {
+	var failed *data.Probe
+	for _, probe := range probes {
+		var result *data.ProbeResult
+		if steadyState, err := parseProbe(probe.Definition); err != nil {
+			result = newProbeResult(false, err.Error())
+		} else {
+			result = steadyState.evaluate(ctx)
+		}
+		switch phase {
+		case probeBefore:
+			probe.Before = result
+		case probeDuring:
+			probe.During = result
+		default:
+			probe.After = result
+		}
+		if !result.Success && failed == nil {
+			failed = probe
+		}
+	}
+	return failed
+}
+
+// startAfterDestroyProbes starts a background process which evaluates the probes of the destroyed experiment,
+// so the destroying is not delayed by the probes, the failures do not fail the destroying
+func startAfterDestroyProbes(launcher processLauncher, uid string) {
+	ctx := context.WithValue(context.Background(), spec.Uid, uid)
+	model, err := GetDS().QueryExperimentModelByUid(uid)
+	if err != nil || model == nil || len(model.Probes) == 0 {
+		return
+	}
+	if err := launchInBackground(launcher, nil, "probe", uid, "--"+AfterFlag); err != nil {
+		log.Warnf(ctx, "start the probes after destroying failed, %v", err)
+	}
+}
+
+// startProbeWatcher starts a background process which evaluates the probes during the experiment
+func startProbeWatcher(launcher processLauncher, uid, interval string) error {
+	return launchInBackground(launcher, nil, "probe", uid, "--"+WatchFlag, "--"+IntervalFlag, interval)
+}
+
+// ProbeCommand evaluates the steady-state probes of the experiment
+type ProbeCommand struct {
+	baseCommand
+	watch    bool
+	after    bool
+	interval string
+}
+
+func (pc *ProbeCommand) Init() {
+	pc.command = &cobra.Command{
+		Use:   "probe UID",
+		Short: "Evaluate the steady-state probes of the experiment",
+		Long: "Evaluate the steady-state probes of the experiment and record the results. With --watch, the probes " +
+			"are evaluated by the interval while the experiment is running, and the experiment is aborted if any " +
+			"probe fails. The watcher is started by blade create --probe automatically, and the probes are " +
+			"evaluated with --after in the background after the experiment is destroyed.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pc.runProbe(cmd, args[0])
+		},
+		Example: probeExample(),
+	}
+	pc.command.Flags().BoolVar(&pc.watch, WatchFlag, false, "Evaluate the probes until the experiment is not running, and abort it if any probe fails")
+	pc.command.Flags().BoolVar(&pc.after, AfterFlag, false, "Record the results as the ones after the experiment is destroyed")
+	pc.command.Flags().StringVar(&pc.interval, IntervalFlag, defaultProbeInterval, "The interval of evaluating the probes when watching")
+}
+
+func (pc *ProbeCommand) runProbe(cmd *cobra.Command, uid string) error {
+	model, err := GetDS().QueryExperimentModelByUid(uid)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
+	}
+	if model == nil {
+		return spec.ResponseFailWithFlags(spec.DataNotFound, uid)
+	}
+	if len(model.Probes) == 0 {
+		return spec.ResponseFailWithFlags(spec.ParameterInvalid, "uid", uid, "the experiment has no probe")
+	}
+	if pc.watch {
+		interval, err := time.ParseDuration(pc.interval)
+		if err != nil || interval <= 0 {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, IntervalFlag, pc.interval, "must be a positive duration")
+		}
+		destroyCommand := &DestroyCommand{}
+		destroyCommand.Init()
+		return watchProbes(destroyCommand, uid, interval, time.Sleep)
+	}
+	ctx := context.WithValue(context.Background(), spec.Uid, uid)
+	phase := probeDuring
+	if pc.after {
+		phase = probeAfter
+	}
+	failed := evaluateProbes(ctx, model.Probes, phase)
+	if err := GetDS().UpdateExperimentProbesByUid(uid, model.Probes); err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "update", err)
+	}
+	if failed != nil {
+		result := failed.During
+		if pc.after {
+			result = failed.After
+			log.Warnf(ctx, "the steady state is not recovered after destroying, the probe %s failed, %s",
+				failed.Definition, result.Message)
+		}
+		response := spec.ResponseFailWithFlags(SteadyStateViolated, phase, failed.Definition, result.Message)
+		response.Result = model.Probes
+		return response
+	}
+	response := spec.ReturnSuccess(model.Probes)
+	return printResponse(cmd, response, func() { cmd.Println(response.Print()) })
+}
+
+// watchProbes evaluates the probes by the interval while the experiment is Success, and aborts the experiment
+// by the normal destroy path if any probe fails
+func watchProbes(dc *DestroyCommand, uid string, interval time.Duration, sleep func(time.Duration)) error {
+	ctx := context.WithValue(context.Background(), spec.Uid, uid)
+	for {
+		sleep(interval)
+		model, err := GetDS().QueryExperimentModelByUid(uid)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
+		}
+		if model == nil || model.Status != Success || len(model.Probes) == 0 {
+			return nil
+		}
+		failed := evaluateProbes(ctx, model.Probes, probeDuring)
+		// the experiment may be destroyed while evaluating, the results after destroying are kept
+		if current, err := GetDS().QueryExperimentModelByUid(uid); err != nil || current == nil || current.Status != Success {
+			return nil
+		}
+		if err := GetDS().UpdateExperimentProbesByUid(uid, model.Probes); err != nil {
+			log.Warnf(ctx, "record the probes failed, %v", err)
+		}
+		if failed == nil {
+			continue
+		}
+		reason := fmt.Sprintf("the probe %s failed during the experiment, %s", failed.Definition, failed.During.Message)
+		log.Warnf(ctx, "%s, abort the experiment", reason)
+		return abortExperiment(dc, model, reason)
+	}
+}
+
+func probeExample() string {
+	return `# Evaluate the probes of the experiment now
+blade probe 47cc0744f1bb
+
+# Evaluate the probes of the destroyed experiment again
+blade probe 47cc0744f1bb --after
+
+# Create the experiment with the probes, which are evaluated before injecting, every 10 seconds during it and after destroying
+blade create cpu load --cpu-percent 80 --probe "http://127.0.0.1:8080/health expect=200 p99<300ms" --probe "exec:pgrep -x nginx"`
+}
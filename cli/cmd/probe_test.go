@@ -0,0 +1,209 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+func Test_parseProbe(t *testing.T) {
+	tests := []struct {
+		definition      string
+		expectedKind    string
+		expectedTarget  string
+		expectedExpect  int
+		expectedSamples int
+		expectedErr     bool
+	}{
+		{"http://127.0.0.1:8080/health", probeHttp, "http://127.0.0.1:8080/health", 200, 1, false},
+		{"https://svc/health expect=204 p99<300ms", probeHttp, "https://svc/health", 204, defaultProbeSamples, false},
+		{"http://svc/health p50<100ms samples=3 timeout=1s", probeHttp, "http://svc/health", 200, 3, false},
+		{"tcp://10.0.0.1:3306 p95<50ms", probeTcp, "10.0.0.1:3306", 0, defaultProbeSamples, false},
+		{"exec:pgrep -x nginx expect=1", probeExec, "pgrep -x nginx", 1, 1, false},
+		{"exec: test -f /tmp/ready", probeExec, "test -f /tmp/ready", 0, 1, false},
+		{"tcp://10.0.0.1 p95<50ms", "", "", 0, 0, true},
+		{"tcp://10.0.0.1:3306 expect=0", "", "", 0, 0, true},
+		{"http://svc/health p99<fast", "", "", 0, 0, true},
+		{"http://svc/health samples=0", "", "", 0, 0, true},
+		{"exec: expect=0", "", "", 0, 0, true},
+		{"ftp://svc", "", "", 0, 0, true},
+	}
+	for _, tt := range tests {
+		probe, err := parseProbe(tt.definition)
+		if (err != nil) != tt.expectedErr {
+			t.Errorf("%s: unexpected error %v", tt.definition, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if probe.kind != tt.expectedKind || probe.target != tt.expectedTarget || probe.expect != tt.expectedExpect ||
+			probe.samples != tt.expectedSamples {
+			t.Errorf("%s: unexpected probe %+v", tt.definition, probe)
+		}
+	}
+}
+
+func Test_steadyStateProbe_evaluate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/unavailable" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed, %v", err)
+	}
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed.Close()
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	tests := []struct {
+		definition string
+		expected   bool
+	}{
+		{server.URL + "/health p99<5s samples=3", true},
+		{server.URL + "/unavailable", false},
+		{server.URL + "/unavailable expect=503", true},
+		{server.URL + "/health p99<1ns", false},
+		{"tcp://" + listener.Addr().String(), true},
+		{"tcp://" + closed.Addr().String() + " timeout=1s", false},
+		{"exec:exit 0", true},
+		{"exec:exit 3 expect=3", true},
+		{"exec:exit 1", false},
+		{"exec:sleep 2 timeout=100ms", false},
+	}
+	for _, tt := range tests {
+		probe, err := parseProbe(tt.definition)
+		if err != nil {
+			t.Fatalf("%s: parse failed, %v", tt.definition, err)
+		}
+		if result := probe.evaluate(context.Background()); result.Success != tt.expected {
+			t.Errorf("%s: unexpected result %+v", tt.definition, result)
+		}
+	}
+}
+
+// probeTestExecutor destroys the experiments successfully
+type probeTestExecutor struct{}
+
+func (*probeTestExecutor) Name() string { return "os" }
+
+func (*probeTestExecutor) SetChannel(channel spec.Channel) {}
+
+func (*probeTestExecutor) Exec(uid string, ctx context.Context, model *spec.ExpModel) *spec.Response {
+	return spec.ReturnSuccess(uid)
+}
+
+func Test_watchProbes(t *testing.T) {
+	tests := []struct {
+		name              string
+		probe             string
+		expectedIntervals int
+		expectedDuring    bool
+		expectedStatus    string
+	}{
+		{"steady", "exec:exit 0", 3, true, Destroyed},
+		{"violated", "exec:exit 1", 1, false, Aborted},
+	}
+	for _, tt := range tests {
+		src := data.NewMemorySource()
+		if err := src.InsertExperimentModel(&data.ExperimentModel{
+			Uid: "probe", Command: "cpu", SubCommand: "fullload", Status: Success,
+			Probes: []*data.Probe{{Definition: tt.probe}},
+		}); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+		SetDS(src)
+		dc := &DestroyCommand{}
+		dc.Init()
+		dc.executors[createExecutorKey("cpu", "", "fullload")] = &probeTestExecutor{}
+		launches := make([]string, 0)
+		dc.launcher = recordLaunches(&launches)
+		// the steady experiment is destroyed by others after two intervals
+		intervals := 0
+		sleep := func(time.Duration) {
+			if intervals++; intervals > 2 {
+				src.UpdateExperimentModelByUid("probe", Destroyed, "")
+			}
+		}
+		if err := watchProbes(dc, "probe", time.Second, sleep); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		model, _ := src.QueryExperimentModelByUid("probe")
+		probe := model.Probes[0]
+		if model.Status != tt.expectedStatus || intervals != tt.expectedIntervals || probe.During == nil ||
+			probe.During.Success != tt.expectedDuring {
+			t.Errorf("%s: unexpected experiment %+v, intervals %d, probe %+v", tt.name, model, intervals, probe)
+		}
+		// the experiment aborted by the watcher is probed in the background after destroying, and the failed
+		// probe is the reason
+		if !tt.expectedDuring && (!reflect.DeepEqual(launches, []string{"probe probe --after"}) ||
+			!strings.Contains(model.Error, tt.probe)) {
+			t.Errorf("%s: the probes are not started after destroying, %v, %+v", tt.name, launches, model)
+		}
+	}
+	SetDS(&MockSource{})
+}
+
+func TestProbeCommand_runProbe_after(t *testing.T) {
+	defer SetDS(&MockSource{})
+	src := data.NewMemorySource()
+	if err := src.InsertExperimentModel(&data.ExperimentModel{
+		Uid: "probe", Command: "cpu", SubCommand: "fullload", Status: Destroyed,
+		Probes: []*data.Probe{{Definition: "exec:exit 0"}, {Definition: "exec:exit 1"}},
+	}); err != nil {
+		t.Fatalf("insert experiment failed, %v", err)
+	}
+	SetDS(src)
+	command := &cobra.Command{}
+	command.SetOut(io.Discard)
+	err := (&ProbeCommand{after: true}).runProbe(command, "probe")
+	if response, ok := err.(*spec.Response); !ok || response.Code != SteadyStateViolated.Code {
+		t.Errorf("expected the steady state violation, got %v", err)
+	}
+	model, _ := src.QueryExperimentModelByUid("probe")
+	for idx, expected := range []bool{true, false} {
+		probe := model.Probes[idx]
+		if probe.During != nil || probe.After == nil || probe.After.Success != expected {
+			t.Errorf("unexpected probe %+v", probe)
+		}
+	}
+}
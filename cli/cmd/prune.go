@@ -0,0 +1,79 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+// PruneCommand deletes the old records of the finished experiments and preparations
+type PruneCommand struct {
+	baseCommand
+	olderThan string
+	status    string
+}
+
+func (pc *PruneCommand) Init() {
+	pc.command = &cobra.Command{
+		Use:   "prune",
+		Short: "Delete the old records of the finished experiments and preparations",
+		Long: "Delete the records of the experiments and preparations which are not updated in the age, " +
+			"and reclaim the space of the data file. The Created, Success and Running records are never pruned. " +
+			"Set the CHAOSBLADE_RETENTION environment variable, such as 30d, to prune the records automatically, " +
+			"and CHAOSBLADE_RETENTION_STATUS to change the statuses.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pc.runPrune(cmd)
+		},
+		Example: pruneExample(),
+	}
+	pc.command.Flags().StringVar(&pc.olderThan, "older-than", "", "prune the records not updated in the age, for example: 30d, 12h")
+	pc.command.Flags().StringVar(&pc.status, "status", strings.Join(data.DefaultPruneStatuses, ","),
+		"the comma separated statuses of the records to prune, Created, Success and Running are not supported")
+	pc.command.MarkFlagRequired("older-than")
+}
+
+func (pc *PruneCommand) runPrune(cmd *cobra.Command) error {
+	olderThan, err := data.ParseAge(pc.olderThan)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, "older-than", pc.olderThan, err)
+	}
+	statuses, err := data.ParseStatuses(pc.status)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, "status", pc.status, err)
+	}
+	result, err := data.Prune(GetDS(), &data.RetentionPolicy{OlderThan: olderThan, Statuses: statuses}, time.Now())
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "prune", err)
+	}
+	cmd.Println(spec.ReturnSuccess(result).Print())
+	return nil
+}
+
+func pruneExample() string {
+	return `# Prune the finished records not updated in 30 days
+blade prune --older-than 30d
+
+# Only prune the destroyed experiments
+blade prune --older-than 7d --status Destroyed`
+}
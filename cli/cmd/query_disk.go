@@ -71,9 +71,9 @@ func (qdc *QueryDiskCommand) queryDiskInfo(command *cobra.Command, arg string) e
 				result = append(result, arr[1])
 			}
 		}
-		command.Println(spec.ReturnSuccess(result))
+		response = spec.ReturnSuccess(result)
+		return printResponse(command, response, func() { command.Println(response) })
 	default:
 		return fmt.Errorf("the %s argument not found", arg)
 	}
-	return nil
 }
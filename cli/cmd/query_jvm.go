@@ -52,10 +52,8 @@ func (qjc *QueryJvmCommand) queryJvmExample() string {
 // queryJvmExpStatus by uid
 func (qjc *QueryJvmCommand) queryJvmExpStatus(ctx context.Context, command *cobra.Command) error {
 	response := jvm.NewExecutor().QueryStatus(ctx)
-	if response.Success {
-		command.Println(response.Print())
-	} else {
+	if !response.Success {
 		return errors.New(response.Error())
 	}
-	return nil
+	return printResponse(command, response, func() { command.Println(response.Print()) })
 }
@@ -61,10 +61,8 @@ blade query k8s create 29c3f9dab4abbc79 --kubectl-proxy http://localhost:8001`
 func (q *QueryK8sCommand) queryK8sExpStatus(command *cobra.Command, cmd, uid string) error {
 	ctx := context.WithValue(context.Background(), spec.Uid, uid)
 	response, _ := kubernetes.QueryStatus(ctx, cmd, q.kubeconfig, q.proxyURL, q.token)
-	if response.Success {
-		command.Println(response.Print())
-	} else {
+	if !response.Success {
 		return errors.New(response.Error())
 	}
-	return nil
+	return printResponse(command, response, func() { command.Println(response.Print()) })
 }
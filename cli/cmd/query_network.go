@@ -63,9 +63,9 @@ func (qnc *QueryNetworkCommand) queryNetworkInfo(command *cobra.Command, arg str
 				names = append(names, i.Name)
 			}
 		}
-		command.Println(spec.ReturnSuccess(names))
+		response := spec.ReturnSuccess(names)
+		return printResponse(command, response, func() { command.Println(response) })
 	default:
 		return fmt.Errorf("the %s argument not found", arg)
 	}
-	return nil
 }
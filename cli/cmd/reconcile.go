@@ -0,0 +1,170 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+// WaitFlag delays the reconciling of the background process started for the timeout of the experiment
+const WaitFlag = "wait"
+
+const (
+	// reconcileMinBackoff and reconcileMaxBackoff bound the time before retrying the failed destroying
+	reconcileMinBackoff = 10 * time.Second
+	reconcileMaxBackoff = time.Hour
+)
+
+// ReconcileCommand destroys the experiments whose timeout has expired
+type ReconcileCommand struct {
+	baseCommand
+	uid  string
+	wait string
+}
+
+// reconcileResult contains the expired experiment uids and the failed ones with the error messages
+type reconcileResult struct {
+	Expired []string          `json:"expired"`
+	Failed  map[string]string `json:"failed,omitempty"`
+}
+
+func (rc *ReconcileCommand) Init() {
+	rc.command = &cobra.Command{
+		Use:   "reconcile",
+		Short: "Destroy the experiments whose timeout has expired",
+		Long: "Destroy the experiments whose timeout has expired and mark them as Expired. " +
+			"The deadlines are stored with the experiments, so the command can be executed by cron or systemd timer " +
+			"to recover the experiments after the host reboots. The blade server executes it periodically.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rc.runReconcile(cmd)
+		},
+		Example: reconcileExample(),
+	}
+	rc.command.Flags().StringVar(&rc.uid, "uid", "", "only reconcile the experiment of the uid")
+	rc.command.Flags().StringVar(&rc.wait, WaitFlag, "", "wait the duration before reconciling, used to internal timeout, no need to config")
+}
+
+func (rc *ReconcileCommand) runReconcile(cmd *cobra.Command) error {
+	if rc.wait != "" {
+		wait, err := time.ParseDuration(rc.wait)
+		if err != nil || wait < 0 {
+			return spec.ResponseFailWithFlags(spec.ParameterIllegal, WaitFlag, rc.wait, "must be a duration")
+		}
+		time.Sleep(wait)
+	}
+	destroyCommand := &DestroyCommand{}
+	destroyCommand.Init()
+	result, err := reconcileExperiments(destroyCommand, rc.uid, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(result.Failed) > 0 {
+		return &spec.Response{
+			Code:    spec.OsCmdExecFailed.Code,
+			Success: false,
+			Err:     fmt.Sprintf("destroy %d expired experiments failed", len(result.Failed)),
+			Result:  result,
+		}
+	}
+	cmd.Println(spec.ReturnSuccess(result).Print())
+	return nil
+}
+
+// reconcileExperiments destroys the experiments whose deadline is not after now and marks them as Expired.
+// If uid is not empty, only the experiment is reconciled. The failed experiments keep the status, and are
+// retried after a backoff recorded with the deadline.
+func reconcileExperiments(dc *DestroyCommand, uid string, now time.Time) (*reconcileResult, error) {
+	models, err := GetDS().QueryOverdueExperimentModels(now)
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
+	}
+	result := &reconcileResult{Expired: make([]string, 0)}
+	for _, model := range models {
+		if uid != "" && model.Uid != uid {
+			continue
+		}
+		ctx := context.WithValue(context.Background(), spec.Uid, model.Uid)
+		log.Infof(ctx, "the experiment is expired, destroy it, command: %s %s %s",
+			model.Command, model.SubCommand, model.Flag)
+		executor, expModel, err := dc.getExecutorAndExpModelByRecord(model)
+		if err == nil {
+			err = dc.destroyExperimentWithStatus(model.Uid, executor, expModel, Expired, "")
+		}
+		if err != nil {
+			log.Warnf(ctx, "destroy the expired experiment failed, %v", err)
+			if result.Failed == nil {
+				result.Failed = make(map[string]string)
+			}
+			result.Failed[model.Uid] = err.Error()
+			if err := deferReconciling(model.Uid, now, err.Error()); err != nil {
+				result.Failed[model.Uid] = fmt.Sprintf("%s, record the retry failed, %v", result.Failed[model.Uid], err)
+			}
+			continue
+		}
+		result.Expired = append(result.Expired, model.Uid)
+	}
+	return result, nil
+}
+
+// deferReconciling records the failed destroying with the deadline of the experiment, which is retried after
+// the backoff doubled by every attempt
+func deferReconciling(uid string, now time.Time, errMsg string) error {
+	deadline, err := GetDS().QueryExperimentDeadline(uid)
+	if err != nil || deadline == nil {
+		return err
+	}
+	backoff := reconcileMinBackoff
+	for attempt := 0; attempt < deadline.Attempts && backoff < reconcileMaxBackoff; attempt++ {
+		backoff *= 2
+	}
+	if backoff > reconcileMaxBackoff {
+		backoff = reconcileMaxBackoff
+	}
+	return GetDS().DeferExperimentDeadline(uid, now.Add(backoff), errMsg)
+}
+
+// runReconcileLoop reconciles the expired experiments every interval until the context is done
+func runReconcileLoop(ctx context.Context, dc *DestroyCommand, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := reconcileExperiments(dc, "", time.Now()); err != nil {
+			dbErrors.WithLabelValues(dbErrorReconcile).Inc()
+			log.Warnf(ctx, "reconcile the expired experiments failed, %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func reconcileExample() string {
+	return `# Destroy all expired experiments
+blade reconcile
+
+# Reconcile every minute by a cron job, which also covers the experiments left by a host reboot
+* * * * * /opt/chaosblade/blade reconcile`
+}
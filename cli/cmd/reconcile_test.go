@@ -0,0 +1,107 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+type overdueMockSource struct {
+	MockSource
+	models    []*data.ExperimentModel
+	deadlines map[string]*data.ExperimentDeadline
+}
+
+func (s *overdueMockSource) QueryOverdueExperimentModels(deadline time.Time) ([]*data.ExperimentModel, error) {
+	return s.models, nil
+}
+
+func (s *overdueMockSource) QueryExperimentDeadline(uid string) (*data.ExperimentDeadline, error) {
+	return s.deadlines[uid], nil
+}
+
+func (s *overdueMockSource) DeferExperimentDeadline(uid string, nextRetry time.Time, errMsg string) error {
+	deadline := s.deadlines[uid]
+	deadline.Attempts++
+	deadline.LastError, deadline.NextRetry = errMsg, nextRetry.Unix()
+	return nil
+}
+
+func Test_reconcileExperiments(t *testing.T) {
+	SetDS(&overdueMockSource{models: []*data.ExperimentModel{
+		{Uid: "7c1f7afc281482c8", Command: "unknown", SubCommand: "action", Status: Success},
+		{Uid: "9b2e3c4d5f6a7b8c", Command: "unknown", SubCommand: "action", Status: Success},
+	}, deadlines: map[string]*data.ExperimentDeadline{
+		"7c1f7afc281482c8": {Uid: "7c1f7afc281482c8"},
+		"9b2e3c4d5f6a7b8c": {Uid: "9b2e3c4d5f6a7b8c"},
+	}})
+	defer SetDS(&MockSource{})
+	dc := &DestroyCommand{}
+	dc.Init()
+
+	result, err := reconcileExperiments(dc, "7c1f7afc281482c8", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Expired) != 0 || len(result.Failed) != 1 || result.Failed["7c1f7afc281482c8"] == "" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	result, err = reconcileExperiments(dc, "", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Failed) != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func Test_deferReconciling(t *testing.T) {
+	deadline := &data.ExperimentDeadline{Uid: "7c1f7afc281482c8"}
+	SetDS(&overdueMockSource{deadlines: map[string]*data.ExperimentDeadline{deadline.Uid: deadline}})
+	defer SetDS(&MockSource{})
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		backoff time.Duration
+	}{
+		{"first", reconcileMinBackoff},
+		{"second", 2 * reconcileMinBackoff},
+		{"third", 4 * reconcileMinBackoff},
+	}
+	for _, tt := range tests {
+		if err := deferReconciling(deadline.Uid, now, "destroy failed"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if deadline.NextRetry != now.Add(tt.backoff).Unix() || deadline.LastError != "destroy failed" {
+			t.Errorf("%s: unexpected deadline %+v", tt.name, deadline)
+		}
+	}
+	deadline.Attempts = 100
+	if err := deferReconciling(deadline.Uid, now, "destroy failed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deadline.NextRetry != now.Add(reconcileMaxBackoff).Unix() {
+		t.Errorf("unexpected max backoff %+v", deadline)
+	}
+	if err := deferReconciling("unknown", now, "destroy failed"); err != nil {
+		t.Errorf("unexpected error of the experiment without deadline: %v", err)
+	}
+}
@@ -49,7 +49,16 @@ func (rc *RevokeCommand) Init() {
 }
 
 func (rc *RevokeCommand) runRevoke(args []string) error {
-	uid := args[0]
+	response := revokePreparation(args[0])
+	if !response.Success {
+		return response
+	}
+	rc.command.Println(response.Print())
+	return nil
+}
+
+// revokePreparation undoes the preparation by uid and updates the preparation record
+func revokePreparation(uid string) *spec.Response {
 	ctx := context.WithValue(context.Background(), spec.Uid, uid)
 	record, err := GetDS().QueryPreparationByUid(uid)
 	if err != nil {
@@ -59,8 +68,7 @@ func (rc *RevokeCommand) runRevoke(args []string) error {
 		return spec.ResponseFailWithFlags(spec.DataNotFound, uid)
 	}
 	if record.Status == Revoked {
-		rc.command.Println(spec.ReturnSuccess("success").Print())
-		return nil
+		return spec.ReturnSuccess("success")
 	}
 	var response *spec.Response
 	channel := channel.NewLocalChannel()
@@ -76,18 +84,19 @@ func (rc *RevokeCommand) runRevoke(args []string) error {
 		return spec.ResponseFailWithFlags(spec.ParameterIllegal, "type", record.ProgramType, "not support the type")
 	}
 	if response.Success {
-		checkError(GetDS().UpdatePreparationRecordByUid(uid, Revoked, ""))
+		err = GetDS().UpdatePreparationRecordByUid(uid, Revoked, "")
 	} else if strings.Contains(response.Err, "connection refused") {
 		// sandbox has been detached, reset response value
 		response = spec.ReturnSuccess("success")
-		checkError(GetDS().UpdatePreparationRecordByUid(uid, Revoked, ""))
+		err = GetDS().UpdatePreparationRecordByUid(uid, Revoked, "")
 	} else {
 		// other failed reason
-		checkError(GetDS().UpdatePreparationRecordByUid(uid, record.Status, fmt.Sprintf("revoke failed. %s", response.Err)))
-		return response
+		err = GetDS().UpdatePreparationRecordByUid(uid, record.Status, fmt.Sprintf("revoke failed. %s", response.Err))
 	}
-	rc.command.Println(response.Print())
-	return nil
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "update", err)
+	}
+	return response
 }
 
 func revokeExample() string {
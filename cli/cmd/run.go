@@ -0,0 +1,367 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+const (
+	// PlanSequential creates the experiments one by one in the file order
+	PlanSequential = "sequential"
+	// PlanParallel creates all experiments at the same time
+	PlanParallel = "parallel"
+	// PlanStaged creates the experiments one by one after their delays from the plan start
+	PlanStaged = "staged"
+)
+
+// experimentPlan is the plan file format, yaml or json, for example:
+//
+//	name: gameday
+//	order: staged
+//	duration: 10m
+//	experiments:
+//	  - name: cpu
+//	    target: cpu
+//	    action: fullload
+//	    flags:
+//	      cpu-percent: 60
+//	  - name: delay
+//	    target: network
+//	    action: delay
+//	    delay: 1m
+//	    flags:
+//	      interface: eth0
+//	      time: 3000
+type experimentPlan struct {
+	Name string `yaml:"name"`
+	// Order is sequential, parallel or staged, default value is sequential
+	Order string `yaml:"order"`
+	// Duration destroys all experiments after the duration from the plan start, the value is a number of seconds
+	// or a time duration. The timeout flag of the experiment takes precedence over it.
+	Duration string `yaml:"duration"`
+	// Labels are added to all experiments of the plan
+	Labels      map[string]string `yaml:"labels"`
+	Experiments []*planExperiment `yaml:"experiments"`
+
+	duration time.Duration
+}
+
+// planExperiment is a step of the plan, the fields are the same as spec.ExpModel
+type planExperiment struct {
+	Name   string            `yaml:"name"`
+	Target string            `yaml:"target"`
+	Scope  string            `yaml:"scope"`
+	Action string            `yaml:"action"`
+	Flags  map[string]string `yaml:"flags"`
+	// Delay is the time after the plan start to create the experiment, only used by staged order
+	Delay string `yaml:"delay"`
+
+	delay time.Duration
+}
+
+func (pe *planExperiment) expModel() *spec.ExpModel {
+	flags := make(map[string]string, len(pe.Flags))
+	for name, value := range pe.Flags {
+		flags[name] = value
+	}
+	return &spec.ExpModel{
+		Target:      pe.Target,
+		Scope:       pe.Scope,
+		ActionName:  pe.Action,
+		ActionFlags: flags,
+	}
+}
+
+// planResult is the result of running the plan, the experiments are recorded as one group
+type planResult struct {
+	Group       string            `json:"group"`
+	Experiments []*planStepResult `json:"experiments"`
+}
+
+type planStepResult struct {
+	Name   string `json:"name"`
+	Uid    string `json:"uid,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// RunCommand creates the experiments declared in the plan file
+type RunCommand struct {
+	baseCommand
+	file string
+}
+
+func (rc *RunCommand) Init() {
+	rc.command = &cobra.Command{
+		Use:   "run",
+		Short: "Run the chaos experiments declared in a plan file",
+		Long: "Run the chaos experiments declared in a yaml or json plan file. The experiments are validated before " +
+			"creating and recorded as one group. If one of them fails, the created ones are destroyed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rc.runPlan(cmd)
+		},
+		Example: runExample(),
+	}
+	rc.command.Flags().StringVarP(&rc.file, "file", "f", "", "the plan file path (required)")
+	rc.command.MarkFlagRequired("file")
+}
+
+func (rc *RunCommand) runPlan(cmd *cobra.Command) error {
+	plan, err := loadExperimentPlan(rc.file)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.ParameterInvalid, "file", rc.file, err)
+	}
+	createCommand := &CreateCommand{}
+	createCommand.Init()
+	if err := validateExperimentPlan(createCommand, plan); err != nil {
+		return err
+	}
+	destroyCommand := &DestroyCommand{}
+	destroyCommand.Init()
+	response := runExperimentPlan(createCommand, destroyCommand, plan)
+	if !response.Success {
+		return response
+	}
+	cmd.Println(response.Print())
+	return nil
+}
+
+// loadExperimentPlan reads the plan file and checks the order, duration and delays
+func loadExperimentPlan(file string) (*experimentPlan, error) {
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	plan := &experimentPlan{}
+	if err := yaml.Unmarshal(bytes, plan); err != nil {
+		return nil, err
+	}
+	if plan.Order == "" {
+		plan.Order = PlanSequential
+	}
+	if plan.Order != PlanSequential && plan.Order != PlanParallel && plan.Order != PlanStaged {
+		return nil, fmt.Errorf("illegal order %s, only supports %s, %s and %s",
+			plan.Order, PlanSequential, PlanParallel, PlanStaged)
+	}
+	if len(plan.Experiments) == 0 {
+		return nil, fmt.Errorf("no experiment in the plan")
+	}
+	if plan.Duration != "" {
+		seconds, err := parseTimeout(plan.Duration)
+		if err != nil || seconds == 0 {
+			return nil, fmt.Errorf("illegal duration %s", plan.Duration)
+		}
+		plan.duration = time.Duration(seconds) * time.Second
+	}
+	for idx, experiment := range plan.Experiments {
+		if experiment.Name == "" {
+			experiment.Name = fmt.Sprintf("%s-%s-%d", experiment.Target, experiment.Action, idx)
+		}
+		if experiment.Target == "" || experiment.Action == "" {
+			return nil, fmt.Errorf("the target and action of %s experiment are required", experiment.Name)
+		}
+		if experiment.Delay == "" {
+			continue
+		}
+		if plan.Order != PlanStaged {
+			return nil, fmt.Errorf("the delay of %s experiment only works for the staged order", experiment.Name)
+		}
+		seconds, err := parseTimeout(experiment.Delay)
+		if err != nil {
+			return nil, fmt.Errorf("illegal delay %s of %s experiment", experiment.Delay, experiment.Name)
+		}
+		experiment.delay = time.Duration(seconds) * time.Second
+		if plan.duration > 0 && experiment.delay >= plan.duration {
+			return nil, fmt.Errorf("the delay of %s experiment exceeds the plan duration", experiment.Name)
+		}
+	}
+	if plan.Order == PlanStaged {
+		sort.SliceStable(plan.Experiments, func(i, j int) bool {
+			return plan.Experiments[i].delay < plan.Experiments[j].delay
+		})
+	}
+	return plan, nil
+}
+
+// validateExperimentPlan checks all experiments against the loaded experiment specs before creating any of them
+func validateExperimentPlan(cc *CreateCommand, plan *experimentPlan) error {
+	for _, experiment := range plan.Experiments {
+		expModel := experiment.expModel()
+		actionSpec := cc.GetActionSpec(expModel)
+		if actionSpec == nil || actionSpec.Executor() == nil {
+			parent, actionTarget := getParentAndActionTarget(expModel.Target, expModel.Scope)
+			return spec.ResponseFailWithFlags(spec.HandlerExecNotFound,
+				fmt.Sprintf("%s experiment, %s", experiment.Name, createExecutorKey(parent, actionTarget, expModel.ActionName)))
+		}
+		if err := cc.checkExpModelFlags(expModel, actionSpec); err != nil {
+			response := err.(*spec.Response)
+			response.Err = fmt.Sprintf("%s experiment, %s", experiment.Name, response.Err)
+			return response
+		}
+		if tt := expModel.ActionFlags["timeout"]; tt != "" {
+			if _, err := parseTimeout(tt); err != nil {
+				return spec.ResponseFailWithFlags(spec.ParameterIllegal, "timeout", tt, err)
+			}
+		}
+	}
+	return nil
+}
+
+// runExperimentPlan creates the experiments by the plan order. If one of them fails, the created experiments are
+// destroyed in the reverse order and the failed response is returned with the plan result.
+func runExperimentPlan(cc *CreateCommand, dc *DestroyCommand, plan *experimentPlan) *spec.Response {
+	groupUid, err := cc.generateUid()
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.GenerateUidFailed, err)
+	}
+	ctx := context.WithValue(context.Background(), spec.Uid, groupUid)
+	log.Infof(ctx, "run %s plan, order: %s, experiments: %d", plan.Name, plan.Order, len(plan.Experiments))
+	result := &planResult{Group: groupUid, Experiments: make([]*planStepResult, len(plan.Experiments))}
+	start := time.Now()
+
+	var failed *spec.Response
+	if plan.Order == PlanParallel {
+		var wg sync.WaitGroup
+		responses := make([]*spec.Response, len(plan.Experiments))
+		for idx, experiment := range plan.Experiments {
+			wg.Add(1)
+			go func(idx int, experiment *planExperiment) {
+				defer wg.Done()
+				result.Experiments[idx], responses[idx] = createPlanExperiment(cc, experiment, groupUid, plan.Labels)
+			}(idx, experiment)
+		}
+		wg.Wait()
+		for _, response := range responses {
+			if !response.Success {
+				failed = response
+				break
+			}
+		}
+	} else {
+		for idx, experiment := range plan.Experiments {
+			if failed != nil {
+				result.Experiments[idx] = &planStepResult{Name: experiment.Name, Status: "Skipped"}
+				continue
+			}
+			if wait := experiment.delay - time.Since(start); wait > 0 {
+				log.Infof(ctx, "wait %s to create %s experiment", wait, experiment.Name)
+				time.Sleep(wait)
+			}
+			var response *spec.Response
+			result.Experiments[idx], response = createPlanExperiment(cc, experiment, groupUid, plan.Labels)
+			if !response.Success {
+				failed = response
+			}
+		}
+	}
+
+	if failed != nil {
+		rollbackPlanExperiments(ctx, dc, plan, result)
+		return &spec.Response{
+			Code:    failed.Code,
+			Success: false,
+			Err:     fmt.Sprintf("run %s plan failed and the created experiments are destroyed, %s", plan.Name, failed.Err),
+			Result:  result,
+		}
+	}
+	if plan.duration > 0 {
+		for idx, experiment := range plan.Experiments {
+			if experiment.Flags["timeout"] != "" {
+				continue
+			}
+			var remaining uint64
+			if elapsed := time.Since(start); elapsed < plan.duration {
+				remaining = uint64((plan.duration - elapsed).Seconds())
+			}
+			if err := scheduleDestroy(cc.launcher, result.Experiments[idx].Uid, experiment.Scope, remaining); err != nil {
+				log.Warnf(ctx, "schedule the %s experiment destroying failed, %v", experiment.Name, err)
+				result.Experiments[idx].Error = err.Error()
+			}
+		}
+	}
+	return spec.ReturnSuccess(result)
+}
+
+func createPlanExperiment(cc *CreateCommand, experiment *planExperiment, groupUid string, labels map[string]string) (
+	*planStepResult, *spec.Response,
+) {
+	response := cc.createExperiment(experiment.expModel(), groupUid, labels)
+	stepResult := &planStepResult{Name: experiment.Name, Status: Success}
+	if uid, ok := response.Result.(string); ok {
+		stepResult.Uid = uid
+	}
+	if !response.Success {
+		stepResult.Status = Error
+		stepResult.Error = response.Err
+	}
+	return stepResult, response
+}
+
+// rollbackPlanExperiments destroys the created experiments of the plan in the reverse order
+func rollbackPlanExperiments(ctx context.Context, dc *DestroyCommand, plan *experimentPlan, result *planResult) {
+	for idx := len(result.Experiments) - 1; idx >= 0; idx-- {
+		stepResult := result.Experiments[idx]
+		if stepResult.Status != Success || stepResult.Uid == "" {
+			continue
+		}
+		model, err := GetDS().QueryExperimentModelByUid(stepResult.Uid)
+		if err == nil {
+			_, err = dc.destroyExperimentByUid(model, stepResult.Uid)
+		}
+		if err != nil {
+			log.Warnf(ctx, "rollback %s experiment of %s plan failed, %v", stepResult.Name, plan.Name, err)
+			stepResult.Error = fmt.Sprintf("rollback failed, %v", err)
+			continue
+		}
+		stepResult.Status = Destroyed
+	}
+}
+
+func runExample() string {
+	return `# Run the experiments in the plan file
+blade run -f gameday.yaml
+
+# gameday.yaml
+name: gameday
+order: staged
+duration: 10m
+experiments:
+  - name: cpu
+    target: cpu
+    action: fullload
+    flags:
+      cpu-percent: 60
+  - name: delay
+    target: network
+    action: delay
+    delay: 1m
+    flags:
+      interface: eth0
+      time: 3000`
+}
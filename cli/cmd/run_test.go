@@ -0,0 +1,92 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_loadExperimentPlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		order   []string
+		err     bool
+	}{
+		{"default order", `
+experiments:
+  - target: cpu
+    action: fullload
+    flags:
+      cpu-percent: 60
+      timeout: 30
+`, []string{"cpu-fullload-0"}, false},
+		{"staged order", `{"order":"staged","duration":"10m","experiments":[
+  {"name":"delay","target":"network","action":"delay","delay":"1m"},
+  {"name":"cpu","target":"cpu","action":"fullload"}]}`, []string{"cpu", "delay"}, false},
+		{"illegal order", "order: random\nexperiments: [{target: cpu, action: fullload}]", nil, true},
+		{"no experiment", "order: parallel", nil, true},
+		{"delay without staged", "experiments: [{target: cpu, action: fullload, delay: 10s}]", nil, true},
+		{"delay exceeds duration", "order: staged\nduration: 60\nexperiments: [{target: cpu, action: fullload, delay: 2m}]", nil, true},
+		{"less action", "experiments: [{target: cpu}]", nil, true},
+	}
+	for _, tt := range tests {
+		file := filepath.Join(t.TempDir(), "plan.yaml")
+		if err := os.WriteFile(file, []byte(tt.content), 0o600); err != nil {
+			t.Fatalf("write plan file failed, %v", err)
+		}
+		plan, err := loadExperimentPlan(file)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if len(plan.Experiments) != len(tt.order) {
+			t.Errorf("%s: unexpected experiments: %d, expected: %d", tt.name, len(plan.Experiments), len(tt.order))
+			continue
+		}
+		for idx, name := range tt.order {
+			if plan.Experiments[idx].Name != name {
+				t.Errorf("%s: unexpected experiment %d: %s, expected: %s", tt.name, idx, plan.Experiments[idx].Name, name)
+			}
+		}
+	}
+}
+
+func Test_loadExperimentPlan_flags(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "plan.yaml")
+	content := "duration: 5m\nexperiments: [{target: cpu, action: fullload, flags: {cpu-percent: 60, cpu-count: 2}}]"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("write plan file failed, %v", err)
+	}
+	plan, err := loadExperimentPlan(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.duration != 5*time.Minute {
+		t.Errorf("unexpected duration: %s", plan.duration)
+	}
+	expModel := plan.Experiments[0].expModel()
+	if expModel.ActionFlags["cpu-percent"] != "60" || expModel.ActionFlags["cpu-count"] != "2" {
+		t.Errorf("unexpected flags: %v", expModel.ActionFlags)
+	}
+}
@@ -0,0 +1,317 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+)
+
+const apiVersion = "/v1"
+
+// apiServer serves the experiment and preparation REST API of the blade server.
+// The requests are executed in process by the same executors as the create, destroy, prepare and revoke commands.
+type apiServer struct {
+	createCommand  *CreateCommand
+	destroyCommand *DestroyCommand
+	// auth authenticates the requests, nil means the server runs without authentication
+	auth *serverAuth
+	// prepareLock serializes the preparations because the prepare commands share the package states
+	prepareLock sync.Mutex
+}
+
+// preparationRequest is the request body for preparing, the fields are the same as the prepare command flags
+type preparationRequest struct {
+	Type     string `json:"type"`
+	Process  string `json:"process,omitempty"`
+	Pid      string `json:"pid,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	JavaHome string `json:"javaHome,omitempty"`
+	Ip       string `json:"ip,omitempty"`
+}
+
+func newAPIServer(auth *serverAuth) *apiServer {
+	createCommand := &CreateCommand{}
+	createCommand.Init()
+	destroyCommand := &DestroyCommand{}
+	destroyCommand.Init()
+	return &apiServer{
+		createCommand:  createCommand,
+		destroyCommand: destroyCommand,
+		auth:           auth,
+	}
+}
+
+// Handler returns the http handler which registers the versioned api routes
+func (s *apiServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+apiVersion+"/experiments", s.createExperiment)
+	mux.HandleFunc("GET "+apiVersion+"/experiments", s.queryExperiments)
+	mux.HandleFunc("GET "+apiVersion+"/experiments/{uid}", s.queryExperiment)
+	mux.HandleFunc("DELETE "+apiVersion+"/experiments/{uid}", s.destroyExperiment)
+	mux.HandleFunc("POST "+apiVersion+"/preparations", s.prepare)
+	mux.HandleFunc("GET "+apiVersion+"/preparations", s.queryPreparations)
+	mux.HandleFunc("GET "+apiVersion+"/preparations/{uid}", s.queryPreparation)
+	mux.HandleFunc("DELETE "+apiVersion+"/preparations/{uid}", s.revoke)
+	mux.HandleFunc("POST "+apiVersion+"/panic", s.panicAll)
+	mux.HandleFunc("DELETE "+apiVersion+"/panic", s.releasePanic)
+	mux.HandleFunc("GET "+metricsPath, s.metrics)
+	if s.auth != nil {
+		return s.auth.authenticate(mux)
+	}
+	return mux
+}
+
+// createExperiment creates the experiment by the request body shaped like spec.ExpModel, for example
+// {"target":"cpu","action":"fullload","flags":{"cpu-percent":"60","timeout":"60"}}
+func (s *apiServer) createExperiment(writer http.ResponseWriter, request *http.Request) {
+	expModel := &spec.ExpModel{}
+	if err := json.NewDecoder(request.Body).Decode(expModel); err != nil {
+		writeResponse(writer, spec.ReturnFail(spec.ParameterRequestFailed,
+			fmt.Sprintf("%s, %v", spec.ParameterRequestFailed.Msg, err)))
+		return
+	}
+	command, subCommand := getCommandAndSubCommand(expModel)
+	if response := authorize(request, fmt.Sprintf("%s %s", command, subCommand)); response != nil {
+		writeResponse(writer, response)
+		return
+	}
+	log.Infof(context.Background(), "create experiment by server, %+v", expModel)
+	writeResponse(writer, s.createCommand.createExperiment(expModel, "", nil))
+}
+
+func (s *apiServer) queryExperiments(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	asc, _ := strconv.ParseBool(query.Get("asc"))
+	models, err := GetDS().QueryExperimentModels(query.Get("target"), query.Get("action"), query.Get("flag"),
+		query.Get("status"), query.Get("limit"), asc)
+	if err != nil {
+		writeResponse(writer, spec.ResponseFailWithFlags(spec.DatabaseError, "query", err))
+		return
+	}
+	writeResponse(writer, spec.ReturnSuccess(models))
+}
+
+func (s *apiServer) queryExperiment(writer http.ResponseWriter, request *http.Request) {
+	uid := request.PathValue("uid")
+	model, err := GetDS().QueryExperimentModelByUid(uid)
+	if err != nil {
+		writeResponse(writer, spec.ResponseFailWithFlags(spec.DatabaseError, "query", err))
+		return
+	}
+	if model == nil {
+		writeResponse(writer, spec.ResponseFailWithFlags(spec.DataNotFound, uid))
+		return
+	}
+	writeResponse(writer, spec.ReturnSuccess(model))
+}
+
+// destroyExperiment destroys the experiment by uid, the record is removed if the force-remove parameter is true
+func (s *apiServer) destroyExperiment(writer http.ResponseWriter, request *http.Request) {
+	uid := request.PathValue("uid")
+	forceRemove, _ := strconv.ParseBool(request.URL.Query().Get(ForceRemoveFlag))
+	model, err := GetDS().QueryExperimentModelByUid(uid)
+	if err != nil {
+		writeResponse(writer, spec.ResponseFailWithFlags(spec.DatabaseError, "query", err))
+		return
+	}
+	if model == nil {
+		writeResponse(writer, spec.ResponseFailWithFlags(spec.DataNotFound, uid))
+		return
+	}
+	if response := authorize(request, fmt.Sprintf("%s %s", model.Command, model.SubCommand)); response != nil {
+		writeResponse(writer, response)
+		return
+	}
+	log.Infof(context.Background(), "destroy experiment by server, uid: %s, force-remove: %t", uid, forceRemove)
+	response, err := s.destroyCommand.destroyExperimentByUid(model, uid)
+	if forceRemove {
+		if removeErr := GetDS().DeleteExperimentModelByUid(uid); removeErr != nil {
+			writeResponse(writer, spec.ResponseFailWithFlags(spec.DatabaseError, "remove", removeErr))
+			return
+		}
+	}
+	if err != nil {
+		writeResponse(writer, toResponse(err))
+		return
+	}
+	writeResponse(writer, response)
+}
+
+// prepare executes the prepare command of the type in process and returns the printed response
+func (s *apiServer) prepare(writer http.ResponseWriter, request *http.Request) {
+	prepareRequest := &preparationRequest{}
+	if err := json.NewDecoder(request.Body).Decode(prepareRequest); err != nil {
+		writeResponse(writer, spec.ReturnFail(spec.ParameterRequestFailed,
+			fmt.Sprintf("%s, %v", spec.ParameterRequestFailed.Msg, err)))
+		return
+	}
+	if response := authorize(request, fmt.Sprintf("prepare %s", prepareRequest.Type)); response != nil {
+		writeResponse(writer, response)
+		return
+	}
+	s.prepareLock.Lock()
+	defer s.prepareLock.Unlock()
+
+	var command Command
+	var run func() error
+	switch prepareRequest.Type {
+	case PrepareJvmType:
+		jvmCommand := &PrepareJvmCommand{}
+		jvmCommand.Init()
+		jvmCommand.processName = prepareRequest.Process
+		jvmCommand.processId = prepareRequest.Pid
+		jvmCommand.port = prepareRequest.Port
+		jvmCommand.javaHome = prepareRequest.JavaHome
+		command = jvmCommand
+		run = func() error {
+			return jvmCommand.prepareJvm(context.WithValue(context.Background(), spec.Uid, jvmCommand.uid))
+		}
+	case PrepareCPlusType:
+		cplusCommand := &PrepareCPlusCommand{}
+		cplusCommand.Init()
+		cplusCommand.port = prepareRequest.Port
+		if cplusCommand.port == 0 {
+			cplusCommand.port = 8703
+		}
+		cplusCommand.ip = prepareRequest.Ip
+		command = cplusCommand
+		run = cplusCommand.prepareCPlus
+	default:
+		writeResponse(writer, spec.ResponseFailWithFlags(spec.ParameterIllegal, "type", prepareRequest.Type,
+			"only jvm and cplus are supported"))
+		return
+	}
+	output := &bytes.Buffer{}
+	command.CobraCmd().SetOut(output)
+	if err := run(); err != nil {
+		writeResponse(writer, toResponse(err))
+		return
+	}
+	writeResponse(writer, spec.Decode(output.String(), nil))
+}
+
+func (s *apiServer) queryPreparations(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	asc, _ := strconv.ParseBool(query.Get("asc"))
+	records, err := GetDS().QueryPreparationRecords(query.Get("type"), query.Get("status"), "", "",
+		query.Get("limit"), asc)
+	if err != nil {
+		writeResponse(writer, spec.ResponseFailWithFlags(spec.DatabaseError, "query", err))
+		return
+	}
+	writeResponse(writer, spec.ReturnSuccess(records))
+}
+
+func (s *apiServer) queryPreparation(writer http.ResponseWriter, request *http.Request) {
+	uid := request.PathValue("uid")
+	record, err := GetDS().QueryPreparationByUid(uid)
+	if err != nil {
+		writeResponse(writer, spec.ResponseFailWithFlags(spec.DatabaseError, "query", err))
+		return
+	}
+	if util.IsNil(record) {
+		writeResponse(writer, spec.ResponseFailWithFlags(spec.DataNotFound, uid))
+		return
+	}
+	writeResponse(writer, spec.ReturnSuccess(record))
+}
+
+func (s *apiServer) revoke(writer http.ResponseWriter, request *http.Request) {
+	uid := request.PathValue("uid")
+	record, err := GetDS().QueryPreparationByUid(uid)
+	if err != nil {
+		writeResponse(writer, spec.ResponseFailWithFlags(spec.DatabaseError, "query", err))
+		return
+	}
+	if util.IsNil(record) {
+		writeResponse(writer, spec.ResponseFailWithFlags(spec.DataNotFound, uid))
+		return
+	}
+	if response := authorize(request, fmt.Sprintf("revoke %s", record.ProgramType)); response != nil {
+		writeResponse(writer, response)
+		return
+	}
+	writeResponse(writer, revokePreparation(uid))
+}
+
+// panicAll stops all experiments and preparations like blade panic
+func (s *apiServer) panicAll(writer http.ResponseWriter, request *http.Request) {
+	if response := authorize(request, "panic"); response != nil {
+		writeResponse(writer, response)
+		return
+	}
+	log.Warnf(context.Background(), "stop all chaos experiments by server")
+	writeResponse(writer, stopAllChaos(s.destroyCommand))
+}
+
+// releasePanic releases the lock of blade panic
+func (s *apiServer) releasePanic(writer http.ResponseWriter, request *http.Request) {
+	if response := authorize(request, "panic release"); response != nil {
+		writeResponse(writer, response)
+		return
+	}
+	writeResponse(writer, releasePanicLock())
+}
+
+// toResponse converts the command error to response
+func toResponse(err error) *spec.Response {
+	if response, ok := err.(*spec.Response); ok {
+		return response
+	}
+	return spec.ReturnFail(spec.CommandIllegal, err.Error())
+}
+
+// writeResponse writes the response as json body with the http status code mapped from the response code
+func writeResponse(writer http.ResponseWriter, response *spec.Response) {
+	if databaseErrorCodes[response.Code] {
+		dbErrors.WithLabelValues(dbErrorAPI).Inc()
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(httpStatusCode(response))
+	fmt.Fprint(writer, response.Print())
+}
+
+func httpStatusCode(response *spec.Response) int {
+	if response.Success {
+		return http.StatusOK
+	}
+	switch response.Code {
+	case spec.DataNotFound.Code:
+		return http.StatusNotFound
+	case ServerUnauthorized.Code:
+		return http.StatusUnauthorized
+	case spec.Forbidden.Code, ServerForbidden.Code, PolicyViolated.Code:
+		return http.StatusForbidden
+	case ChaosPanicLocked.Code:
+		return http.StatusLocked
+	case spec.ParameterLess.Code, spec.ParameterIllegal.Code, spec.ParameterInvalid.Code,
+		spec.ParameterRequestFailed.Code, spec.CommandIllegal.Code, spec.HandlerExecNotFound.Code:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
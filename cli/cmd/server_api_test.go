@@ -0,0 +1,76 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+func TestAPIServer_Handler(t *testing.T) {
+	SetDS(&MockSource{})
+	handler := newAPIServer(nil).Handler()
+
+	tests := []struct {
+		method string
+		url    string
+		body   string
+		code   int
+	}{
+		{http.MethodGet, "/v1/experiments", "", http.StatusOK},
+		{http.MethodGet, "/v1/experiments/7c1f7afc281482c8", "", http.StatusNotFound},
+		{http.MethodDelete, "/v1/experiments/7c1f7afc281482c8", "", http.StatusNotFound},
+		{http.MethodPost, "/v1/experiments", "{", http.StatusBadRequest},
+		{http.MethodPost, "/v1/experiments", `{"target":"cpu","action":"fullload"}`, http.StatusBadRequest},
+		{http.MethodGet, "/v1/preparations", "", http.StatusOK},
+		{http.MethodPost, "/v1/preparations", `{"type":"k8s"}`, http.StatusBadRequest},
+		{http.MethodPut, "/v1/experiments", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/metrics", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		request := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != tt.code {
+			t.Errorf("%s %s: unexpected status code: %d, expected: %d, body: %s",
+				tt.method, tt.url, recorder.Code, tt.code, recorder.Body.String())
+		}
+	}
+}
+
+func Test_getCommandAndSubCommand(t *testing.T) {
+	tests := []struct {
+		input      *spec.ExpModel
+		command    string
+		subCommand string
+	}{
+		{&spec.ExpModel{Target: "cpu", ActionName: "fullload"}, "cpu", "fullload"},
+		{&spec.ExpModel{Target: "cpu", Scope: "host", ActionName: "fullload"}, "cpu", "fullload"},
+		{&spec.ExpModel{Target: "network", Scope: "docker", ActionName: "delay"}, "docker", "network delay"},
+		{&spec.ExpModel{Target: "cpu", Scope: "pod", ActionName: "fullload"}, "k8s", "pod-cpu fullload"},
+	}
+	for _, tt := range tests {
+		command, subCommand := getCommandAndSubCommand(tt.input)
+		if command != tt.command || subCommand != tt.subCommand {
+			t.Errorf("unexpected result: %s %s, expected: %s %s", command, subCommand, tt.command, tt.subCommand)
+		}
+	}
+}
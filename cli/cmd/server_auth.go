@@ -0,0 +1,239 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/log"
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+var (
+	// ServerUnauthorized is returned if the request carries neither a valid bearer token nor a client certificate
+	ServerUnauthorized = spec.CodeType{Code: 43001, Msg: "unauthorized, %s"}
+	// ServerForbidden is returned if the caller is not allowed to execute the command by its policy
+	ServerForbidden = spec.CodeType{Code: 43002, Msg: "`%s` is not allowed to execute `%s`"}
+)
+
+// serverAuthConfig is the authentication and authorization config of the blade server, for example:
+//
+//	identities:
+//	  - name: platform
+//	    token: 6b2bd1c0e8ba4f7c
+//	    allow: ["cpu fullload", "network *", "prepare jvm"]
+//	  - name: ops
+//	    commonName: ops.chaosblade.io
+//	    allow: ["*"]
+//	    deny: ["process kill"]
+type serverAuthConfig struct {
+	Identities []*serverIdentity `yaml:"identities"`
+}
+
+// serverIdentity is a caller of the blade server and the policy limits the commands it may execute
+type serverIdentity struct {
+	Name string `yaml:"name"`
+	// Token is the bearer token of the caller
+	Token string `yaml:"token"`
+	// TokenSha256 is the hex encoded sha256 digest of the bearer token, used to avoid storing the plain token
+	TokenSha256 string `yaml:"tokenSha256"`
+	// CommonName is the subject common name of the client certificate when mTLS is enabled
+	CommonName string `yaml:"commonName"`
+	// Allow contains the command patterns the caller may execute, such as `cpu fullload`, `docker network *`,
+	// `k8s pod-* *`, `prepare jvm` or `*`
+	Allow []string `yaml:"allow"`
+	// Deny contains the command patterns the caller must not execute, it takes precedence over Allow.
+	// A target action pattern, such as `process kill`, also denies the action in the docker, cri and k8s scopes.
+	Deny []string `yaml:"deny"`
+}
+
+// allows returns true if the command, such as `cpu fullload`, matches the allow patterns and none of the deny patterns
+func (si *serverIdentity) allows(command string) bool {
+	if !matchCommandPatterns(si.Allow, command) || matchCommandPatterns(si.Deny, command) {
+		return false
+	}
+	if targetAction, ok := scopelessCommand(command); ok {
+		return !matchCommandPatterns(si.Deny, targetAction)
+	}
+	return true
+}
+
+// scopelessCommand returns the target and action of the docker, cri and k8s command, such as `process kill` of
+// `k8s pod-process kill`, it returns false for the other commands
+func scopelessCommand(command string) (string, bool) {
+	scope, targetAction, _ := strings.Cut(command, " ")
+	switch scope {
+	case "docker", "cri":
+		return targetAction, strings.Contains(targetAction, " ")
+	case "k8s":
+		// the target of the k8s command is prefixed with the resource, such as pod-process
+		_, targetAction, found := strings.Cut(targetAction, "-")
+		return targetAction, found && strings.Contains(targetAction, " ")
+	}
+	return "", false
+}
+
+func matchCommandPatterns(patterns []string, command string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.Join(strings.Fields(pattern), " "), command); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// serverAuth authenticates the requests by bearer token or client certificate
+type serverAuth struct {
+	identities []*serverIdentity
+	// clientCertAuth is true if the client certificates are verified by mTLS
+	clientCertAuth bool
+	// allowAllClients allows all commands for the client certificates whose common names are not configured
+	allowAllClients bool
+}
+
+type identityKey struct{}
+
+// loadServerAuthConfig reads the auth config file, which is yaml or json format
+func loadServerAuthConfig(file string) (*serverAuthConfig, error) {
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	config := &serverAuthConfig{}
+	if err := yaml.Unmarshal(bytes, config); err != nil {
+		return nil, fmt.Errorf("parse %s file err, %v", file, err)
+	}
+	for idx, identity := range config.Identities {
+		if identity.Name == "" {
+			return nil, fmt.Errorf("the name of identity %d is empty", idx)
+		}
+		if identity.Token == "" && identity.TokenSha256 == "" && identity.CommonName == "" {
+			return nil, fmt.Errorf("the %s identity needs a token, tokenSha256 or commonName", identity.Name)
+		}
+		if identity.Token != "" {
+			digest := sha256.Sum256([]byte(identity.Token))
+			identity.TokenSha256 = hex.EncodeToString(digest[:])
+		}
+		identity.TokenSha256 = strings.ToLower(identity.TokenSha256)
+	}
+	return config, nil
+}
+
+// newServerAuth returns the authenticator by the auth config file and the mTLS settings
+func newServerAuth(authFile string, clientCertAuth, allowAllClients bool) (*serverAuth, error) {
+	auth := &serverAuth{clientCertAuth: clientCertAuth, allowAllClients: allowAllClients}
+	if authFile != "" {
+		config, err := loadServerAuthConfig(authFile)
+		if err != nil {
+			return nil, err
+		}
+		auth.identities = config.Identities
+	}
+	return auth, nil
+}
+
+// identify returns the identity of the request, the bearer token is checked before the client certificate
+func (sa *serverAuth) identify(request *http.Request) (*serverIdentity, error) {
+	if header := request.Header.Get("Authorization"); header != "" {
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found || token == "" {
+			return nil, fmt.Errorf("the authorization header must be a bearer token")
+		}
+		digest := sha256.Sum256([]byte(token))
+		tokenSha256 := hex.EncodeToString(digest[:])
+		for _, identity := range sa.identities {
+			if identity.TokenSha256 != "" &&
+				subtle.ConstantTimeCompare([]byte(identity.TokenSha256), []byte(tokenSha256)) == 1 {
+				return identity, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid bearer token")
+	}
+	if sa.clientCertAuth && request.TLS != nil && len(request.TLS.PeerCertificates) > 0 {
+		commonName := request.TLS.PeerCertificates[0].Subject.CommonName
+		for _, identity := range sa.identities {
+			if identity.CommonName != "" && identity.CommonName == commonName {
+				return identity, nil
+			}
+		}
+		if sa.allowAllClients {
+			// the client certificates verified by the ca are trusted by the explicit opt-in
+			return &serverIdentity{Name: commonName, Allow: []string{"*"}}, nil
+		}
+		return nil, fmt.Errorf("the client certificate %s is not configured", commonName)
+	}
+	return nil, fmt.Errorf("less bearer token or client certificate")
+}
+
+// authenticate wraps the handler and rejects the unauthenticated requests
+func (sa *serverAuth) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		identity, err := sa.identify(request)
+		if err != nil {
+			log.Warnf(context.Background(), "reject %s %s request from %s, %v",
+				request.Method, request.URL.Path, request.RemoteAddr, err)
+			writeResponse(writer, spec.ResponseFailWithFlags(ServerUnauthorized, err))
+			return
+		}
+		next.ServeHTTP(writer, request.WithContext(context.WithValue(request.Context(), identityKey{}, identity)))
+	})
+}
+
+// authorize checks the command against the policy of the request identity, returns nil if it is allowed.
+// All commands are allowed if the server runs without authentication.
+func authorize(request *http.Request, command string) *spec.Response {
+	identity, ok := request.Context().Value(identityKey{}).(*serverIdentity)
+	if !ok {
+		return nil
+	}
+	if identity.allows(command) {
+		return nil
+	}
+	log.Warnf(context.Background(), "%s", ServerForbidden.Sprintf(identity.Name, command))
+	return spec.ResponseFailWithFlags(ServerForbidden, identity.Name, command)
+}
+
+// newServerTLSConfig returns the tls config, the client certificates are required and verified if clientCAFile is set
+func newServerTLSConfig(clientCAFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if clientCAFile == "" {
+		return config, nil
+	}
+	bytes, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bytes) {
+		return nil, fmt.Errorf("no certificate found in %s", clientCAFile)
+	}
+	config.ClientCAs = pool
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	return config, nil
+}
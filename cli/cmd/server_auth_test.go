@@ -0,0 +1,149 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_serverIdentity_allows(t *testing.T) {
+	identity := &serverIdentity{
+		Allow: []string{"cpu fullload", "network  *", "k8s pod-* *"},
+		Deny:  []string{"network loss"},
+	}
+	tests := []struct {
+		command string
+		expect  bool
+	}{
+		{"cpu fullload", true},
+		{"cpu load", false},
+		{"network delay", true},
+		{"network loss", false},
+		{"docker network delay", false},
+		{"k8s pod-cpu fullload", true},
+		{"k8s pod-network delay", true},
+		{"k8s pod-network loss", false},
+		{"process kill", false},
+	}
+	for _, tt := range tests {
+		if got := identity.allows(tt.command); got != tt.expect {
+			t.Errorf("unexpected result of %s: %t, expected: %t", tt.command, got, tt.expect)
+		}
+	}
+}
+
+func TestServerAuth_authenticate(t *testing.T) {
+	SetDS(&MockSource{})
+	authFile := filepath.Join(t.TempDir(), "auth.yaml")
+	content := `identities:
+  - name: platform
+    token: platform-token
+    allow: ["cpu fullload"]
+`
+	if err := os.WriteFile(authFile, []byte(content), 0o600); err != nil {
+		t.Fatalf("write auth file failed, %v", err)
+	}
+	auth, err := newServerAuth(authFile, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	handler := newAPIServer(auth).Handler()
+
+	tests := []struct {
+		method string
+		url    string
+		token  string
+		body   string
+		code   int
+	}{
+		{http.MethodGet, "/v1/experiments", "", "", http.StatusUnauthorized},
+		{http.MethodGet, "/v1/experiments", "wrong-token", "", http.StatusUnauthorized},
+		{http.MethodGet, "/v1/experiments", "platform-token", "", http.StatusOK},
+		{http.MethodPost, "/v1/experiments", "platform-token", `{"target":"process","action":"kill"}`, http.StatusForbidden},
+		{http.MethodPost, "/v1/preparations", "platform-token", `{"type":"jvm"}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		request := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+		if tt.token != "" {
+			request.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != tt.code {
+			t.Errorf("%s %s: unexpected status code: %d, expected: %d, body: %s",
+				tt.method, tt.url, recorder.Code, tt.code, recorder.Body.String())
+		}
+	}
+}
+
+func TestServerAuth_identifyClientCertificate(t *testing.T) {
+	identities := []*serverIdentity{{Name: "ops", CommonName: "ops.chaosblade.io", Allow: []string{"cpu *"}}}
+	tests := []struct {
+		name            string
+		identities      []*serverIdentity
+		allowAllClients bool
+		commonName      string
+		expectedName    string
+		expectedErr     bool
+	}{
+		{"configured", identities, false, "ops.chaosblade.io", "ops", false},
+		{"not configured", identities, false, "dev.chaosblade.io", "", true},
+		{"no auth file", nil, false, "ops.chaosblade.io", "", true},
+		{"allow all", nil, true, "dev.chaosblade.io", "dev.chaosblade.io", false},
+	}
+	for _, tt := range tests {
+		auth := &serverAuth{identities: tt.identities, clientCertAuth: true, allowAllClients: tt.allowAllClients}
+		request := httptest.NewRequest(http.MethodGet, "/v1/experiments", nil)
+		request.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: tt.commonName}}},
+		}
+		identity, err := auth.identify(request)
+		if (err != nil) != tt.expectedErr {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if err == nil && identity.Name != tt.expectedName {
+			t.Errorf("%s: unexpected identity %s, expected: %s", tt.name, identity.Name, tt.expectedName)
+		}
+	}
+}
+
+func TestStartServerCommand_checkAuthFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		command     *StartServerCommand
+		expectedErr bool
+	}{
+		{"mTLS without identities", &StartServerCommand{tlsCert: "s.crt", tlsKey: "s.key", tlsClientCA: "ca.crt"}, true},
+		{"mTLS allows all", &StartServerCommand{tlsCert: "s.crt", tlsKey: "s.key", tlsClientCA: "ca.crt", tlsClientAllowAll: true}, false},
+		{"allow all without mTLS", &StartServerCommand{insecure: true, tlsClientAllowAll: true}, true},
+		{"insecure", &StartServerCommand{insecure: true}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.command.checkAuthFlags(); (err != nil) != tt.expectedErr {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+	}
+}
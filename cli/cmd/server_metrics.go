@@ -0,0 +1,165 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+)
+
+const metricsPath = "/metrics"
+
+// The components which count the database errors
+const (
+	dbErrorAPI       = "api"
+	dbErrorReconcile = "reconcile"
+	dbErrorMetrics   = "metrics"
+)
+
+var (
+	executorBuckets      = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+	sandboxAttachBuckets = []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120}
+)
+
+// The metrics are collected in process, so they only cover the experiments and preparations executed by the
+// blade server, except the active experiments which are queried from the data source by every scrape
+var (
+	experimentsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "chaosblade_experiments_created_total",
+		Help: "The experiments created successfully",
+	}, []string{"target", "scope", "action"})
+	experimentsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "chaosblade_experiments_failed_total",
+		Help: "The experiments failed to create",
+	}, []string{"target", "scope", "action"})
+	experimentsDestroyed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "chaosblade_experiments_destroyed_total",
+		Help: "The experiments destroyed, expired or aborted",
+	}, []string{"target", "scope", "action", "status"})
+	executorDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "chaosblade_executor_duration_seconds",
+		Help:    "The latency of the executors creating and destroying experiments",
+		Buckets: executorBuckets,
+	}, []string{"executor", "operation", "result"})
+	sandboxAttachDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "chaosblade_sandbox_attach_duration_seconds",
+		Help:    "The duration of attaching the sandbox agent to the jvm process",
+		Buckets: sandboxAttachBuckets,
+	}, []string{"result"})
+	dbErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "chaosblade_db_errors_total",
+		Help: "The database errors",
+	}, []string{"component"})
+
+	// metricsRegistry only contains the chaosblade metrics, not the go runtime and process metrics
+	metricsRegistry = prometheus.NewRegistry()
+	metricsHandler  = promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})
+)
+
+func init() {
+	for _, component := range []string{dbErrorAPI, dbErrorReconcile, dbErrorMetrics} {
+		dbErrors.WithLabelValues(component)
+	}
+	metricsRegistry.MustRegister(experimentsCreated, experimentsFailed, experimentsDestroyed, executorDuration,
+		sandboxAttachDuration, dbErrors, newActiveExperimentsCollector())
+}
+
+// databaseErrorCodes are the response codes of the failed database operations
+var databaseErrorCodes = map[int32]bool{
+	spec.DatabaseError.Code: true,
+	spec.DbQueryFailed.Code: true,
+}
+
+// activeExperimentsCollector collects the gauge of the successful experiments grouped by the target, scope and
+// action, which are queried from the data source by every scrape
+type activeExperimentsCollector struct {
+	desc *prometheus.Desc
+}
+
+func newActiveExperimentsCollector() *activeExperimentsCollector {
+	return &activeExperimentsCollector{desc: prometheus.NewDesc("chaosblade_experiments_active",
+		"The experiments in the Success status", []string{"target", "scope", "action"}, nil)}
+}
+
+func (c *activeExperimentsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *activeExperimentsCollector) Collect(ch chan<- prometheus.Metric) {
+	models, err := GetDS().QueryExperimentModels("", "", "", Success, "", false)
+	if err != nil {
+		dbErrors.WithLabelValues(dbErrorMetrics).Inc()
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	counts := map[[3]string]float64{}
+	for _, model := range models {
+		target, scope, action := recordTargetScopeAction(model)
+		counts[[3]string{target, scope, action}]++
+	}
+	for labelValues, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, count, labelValues[:]...)
+	}
+}
+
+// expModelLabels returns the target, scope and action labels of the experiment, the empty scope is host
+func expModelLabels(expModel *spec.ExpModel) []string {
+	scope := expModel.Scope
+	if scope == "" {
+		scope = "host"
+	}
+	return []string{expModel.Target, scope, expModel.ActionName}
+}
+
+// resultLabel returns the result label of the response
+func resultLabel(response *spec.Response) string {
+	if response.Success {
+		return "success"
+	}
+	return "failure"
+}
+
+// execExperiment executes the experiment by the executor and observes the latency, the operation is create or destroy
+func execExperiment(ctx context.Context, executor spec.Executor, uid string, expModel *spec.ExpModel,
+	operation string,
+) *spec.Response {
+	start := time.Now()
+	response := executor.Exec(uid, ctx, expModel)
+	executorDuration.WithLabelValues(executor.Name(), operation, resultLabel(response)).
+		Observe(time.Since(start).Seconds())
+	return response
+}
+
+// countCreatedExperiment counts the experiment as created or failed by the response
+func countCreatedExperiment(expModel *spec.ExpModel, response *spec.Response) {
+	if response.Success {
+		experimentsCreated.WithLabelValues(expModelLabels(expModel)...).Inc()
+	} else {
+		experimentsFailed.WithLabelValues(expModelLabels(expModel)...).Inc()
+	}
+}
+
+// metrics serves the metrics in the prometheus text exposition format
+func (s *apiServer) metrics(writer http.ResponseWriter, request *http.Request) {
+	metricsHandler.ServeHTTP(writer, request)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+func TestAPIServer_metrics(t *testing.T) {
+	src := data.NewMemorySource()
+	for _, model := range []*data.ExperimentModel{
+		{Uid: "a1", Command: "cpu", SubCommand: "fullload", Status: Success},
+		{Uid: "a2", Command: "cpu", SubCommand: "fullload", Status: Success},
+		{Uid: "a3", Command: "k8s", SubCommand: "pod-network delay", Status: Success},
+		{Uid: "a4", Command: "mem", SubCommand: "load", Status: Destroyed},
+	} {
+		if err := src.InsertExperimentModel(model); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+	}
+	SetDS(src)
+	defer SetDS(&MockSource{})
+
+	request := httptest.NewRequest(http.MethodGet, metricsPath, nil)
+	recorder := httptest.NewRecorder()
+	newAPIServer(nil).Handler().ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("unexpected response %d, %s", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+	body := recorder.Body.String()
+	for _, line := range []string{
+		`chaosblade_experiments_active{action="fullload",scope="host",target="cpu"} 2`,
+		`chaosblade_experiments_active{action="delay",scope="pod",target="network"} 1`,
+		"# TYPE chaosblade_db_errors_total counter",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("the metrics does not contain %s:\n%s", line, body)
+		}
+	}
+	if strings.Contains(body, `action="load"`) {
+		t.Errorf("the destroyed experiment is active:\n%s", body)
+	}
+}
+
+func Test_writeResponse_dbErrors(t *testing.T) {
+	tests := []struct {
+		response *spec.Response
+		counted  bool
+	}{
+		{spec.ResponseFailWithFlags(spec.DatabaseError, "query", "failed"), true},
+		{spec.ResponseFailWithFlags(spec.DbQueryFailed, "experiments", "failed"), true},
+		{spec.ResponseFailWithFlags(spec.DataNotFound, "uid"), false},
+		{spec.ReturnSuccess("ok"), false},
+	}
+	for _, tt := range tests {
+		before := apiDbErrors(t)
+		writeResponse(httptest.NewRecorder(), tt.response)
+		if counted := apiDbErrors(t) == before+1; counted != tt.counted {
+			t.Errorf("the %d code is counted: %t, expected: %t", tt.response.Code, counted, tt.counted)
+		}
+	}
+}
+
+// apiDbErrors returns the database errors of the api scraped from the metrics
+func apiDbErrors(t *testing.T) int {
+	recorder := httptest.NewRecorder()
+	metricsHandler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+	prefix := `chaosblade_db_errors_total{component="api"} `
+	for _, line := range strings.Split(recorder.Body.String(), "\n") {
+		if strings.HasPrefix(line, prefix) {
+			count, err := strconv.Atoi(strings.TrimPrefix(line, prefix))
+			if err != nil {
+				t.Fatalf("parse the database errors failed, %v", err)
+			}
+			return count
+		}
+	}
+	t.Fatalf("the database errors of the api are not found:\n%s", recorder.Body.String())
+	return 0
+}
@@ -30,6 +30,8 @@ import (
 	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 	"github.com/chaosblade-io/chaosblade-spec-go/util"
+
+	"github.com/chaosblade-io/chaosblade/data"
 )
 
 const startServerKey = "blade server start --nohup"
@@ -39,6 +41,19 @@ type StartServerCommand struct {
 	ip    string
 	port  string
 	nohup bool
+	// authFile is the identities and policies file for bearer token and client certificate authentication
+	authFile string
+	// tlsCert and tlsKey serve https if both are set
+	tlsCert string
+	tlsKey  string
+	// tlsClientCA enables mTLS, the client certificates must be signed by the ca
+	tlsClientCA string
+	// tlsClientAllowAll allows all commands for the client certificates not configured in the auth file
+	tlsClientAllowAll bool
+	// insecure allows the server to run without any authentication
+	insecure bool
+	// reconcileInterval is the interval of destroying the expired experiments, 0 disables it
+	reconcileInterval time.Duration
 }
 
 func (ssc *StartServerCommand) Init() {
@@ -55,6 +70,41 @@ func (ssc *StartServerCommand) Init() {
 	ssc.command.Flags().StringVarP(&ssc.ip, "ip", "i", "", "service ip address, default value is *")
 	ssc.command.Flags().StringVarP(&ssc.port, "port", "p", "9526", "service port")
 	ssc.command.Flags().BoolVarP(&ssc.nohup, "nohup", "n", false, "used by internal")
+	ssc.command.Flags().StringVar(&ssc.authFile, "auth-file", "", "the yaml file contains the bearer tokens, client certificate names and their allowed commands")
+	ssc.command.Flags().StringVar(&ssc.tlsCert, "tls-cert", "", "the server certificate file, serves https if it is set with --tls-key")
+	ssc.command.Flags().StringVar(&ssc.tlsKey, "tls-key", "", "the server private key file")
+	ssc.command.Flags().StringVar(&ssc.tlsClientCA, "tls-client-ca", "", "the ca file to verify client certificates, enables mTLS authentication")
+	ssc.command.Flags().BoolVar(&ssc.tlsClientAllowAll, "tls-client-allow-all", false, "allow all commands for the client certificates signed by the ca whose common names are not in the auth file")
+	ssc.command.Flags().BoolVar(&ssc.insecure, "insecure", false, "allow running the server without authentication, anyone who can reach the port can execute experiments")
+	ssc.command.Flags().DurationVar(&ssc.reconcileInterval, "reconcile-interval", 10*time.Second, "the interval of destroying the expired experiments, 0 disables it")
+}
+
+// checkAuthFlags checks the authentication and tls flags before starting server
+func (ssc *StartServerCommand) checkAuthFlags() error {
+	if (ssc.tlsCert == "") != (ssc.tlsKey == "") {
+		return spec.ResponseFailWithFlags(spec.ParameterLess, "tls-cert|tls-key, both must be specified")
+	}
+	if ssc.tlsClientCA != "" && ssc.tlsCert == "" {
+		return spec.ResponseFailWithFlags(spec.ParameterLess, "tls-cert, mTLS requires the server certificate")
+	}
+	if ssc.tlsClientAllowAll && ssc.tlsClientCA == "" {
+		return spec.ResponseFailWithFlags(spec.ParameterLess, "tls-client-ca, --tls-client-allow-all requires mTLS")
+	}
+	if ssc.tlsClientCA != "" && ssc.authFile == "" && !ssc.tlsClientAllowAll {
+		return spec.ResponseFailWithFlags(spec.ParameterLess,
+			"auth-file|tls-client-allow-all, the client certificates need identities of their common names, "+
+				"add --tls-client-allow-all to allow all commands for any certificate signed by the ca")
+	}
+	if ssc.authFile == "" && ssc.tlsClientCA == "" && !ssc.insecure {
+		return spec.ResponseFailWithFlags(spec.ParameterLess,
+			"auth-file|tls-client-ca, the server requires authentication, add --insecure to run without it")
+	}
+	if ssc.authFile != "" {
+		if _, err := loadServerAuthConfig(ssc.authFile); err != nil {
+			return spec.ResponseFailWithFlags(spec.ParameterInvalid, "auth-file", ssc.authFile, err)
+		}
+	}
+	return nil
 }
 
 func (ssc *StartServerCommand) run(cmd *cobra.Command, args []string) error {
@@ -66,8 +116,11 @@ func (ssc *StartServerCommand) run(cmd *cobra.Command, args []string) error {
 	if len(pids) > 0 {
 		return spec.ResponseFailWithFlags(spec.ChaosbladeServerStarted)
 	}
+	if err := ssc.checkAuthFlags(); err != nil {
+		return err
+	}
 	if ssc.nohup {
-		ssc.start0()
+		return ssc.start0()
 	}
 	err = ssc.start()
 	if err != nil {
@@ -86,6 +139,20 @@ func (ssc *StartServerCommand) start() error {
 	if ssc.ip != "" {
 		args = fmt.Sprintf("%s --ip %s", args, ssc.ip)
 	}
+	for _, flag := range [][2]string{
+		{"auth-file", ssc.authFile}, {"tls-cert", ssc.tlsCert}, {"tls-key", ssc.tlsKey}, {"tls-client-ca", ssc.tlsClientCA},
+	} {
+		if flag[1] != "" {
+			args = fmt.Sprintf("%s --%s %s", args, flag[0], flag[1])
+		}
+	}
+	if ssc.tlsClientAllowAll {
+		args = fmt.Sprintf("%s --tls-client-allow-all", args)
+	}
+	if ssc.insecure {
+		args = fmt.Sprintf("%s --insecure", args)
+	}
+	args = fmt.Sprintf("%s --reconcile-interval %s", args, ssc.reconcileInterval)
 	ctx := context.Background()
 	response := cl.Run(ctx, "nohup", fmt.Sprintf("%s > /dev/null 2>&1 &", args))
 	if !response.Success {
@@ -120,25 +187,63 @@ func (ssc *StartServerCommand) start() error {
 }
 
 // start0 starts web service
-func (ssc *StartServerCommand) start0() {
+func (ssc *StartServerCommand) start0() error {
+	ctx := context.Background()
+	data.SetAuditSource(data.AuditSourceServer)
+	var auth *serverAuth
+	if ssc.authFile != "" || ssc.tlsClientCA != "" {
+		var err error
+		auth, err = newServerAuth(ssc.authFile, ssc.tlsClientCA != "", ssc.tlsClientAllowAll)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.ParameterInvalid, "auth-file", ssc.authFile, err)
+		}
+	} else {
+		log.Warnf(ctx, "the blade server runs without authentication")
+	}
+	api := newAPIServer(auth)
+	server := &http.Server{
+		Addr:    ssc.ip + ":" + ssc.port,
+		Handler: api.Handler(),
+	}
+	if ssc.tlsCert != "" {
+		tlsConfig, err := newServerTLSConfig(ssc.tlsClientCA)
+		if err != nil {
+			return spec.ResponseFailWithFlags(spec.ParameterInvalid, "tls-client-ca", ssc.tlsClientCA, err)
+		}
+		server.TLSConfig = tlsConfig
+	}
+	if ssc.reconcileInterval > 0 {
+		go runReconcileLoop(ctx, api.destroyCommand, ssc.reconcileInterval)
+	}
 	go func() {
-		err := http.ListenAndServe(ssc.ip+":"+ssc.port, nil)
+		var err error
+		if ssc.tlsCert != "" {
+			err = server.ListenAndServeTLS(ssc.tlsCert, ssc.tlsKey)
+		} else {
+			err = server.ListenAndServe()
+		}
 		if err != nil {
-			log.Errorf(context.Background(), "start blade server error, %v", err)
+			log.Errorf(ctx, "start blade server error, %v", err)
 			// log.Error(err, "start blade server error")
 			os.Exit(1)
 		}
 	}()
-	Register("/chaosblade")
 	util.Hold()
-}
-
-func Register(requestPath string) {
-	http.HandleFunc(requestPath, func(writer http.ResponseWriter, request *http.Request) {
-		fmt.Fprint(writer, spec.ReturnFail(spec.CommandIllegal, "Server mode is disabled").Print())
-	})
+	return nil
 }
 
 func startServerExample() string {
-	return `blade server start --port 8000`
+	return `blade server start --port 8000 --auth-file /etc/chaosblade/server-auth.yaml
+
+# Serve https and verify the client certificates
+blade server start --port 8000 --tls-cert server.crt --tls-key server.key --tls-client-ca ca.crt --auth-file /etc/chaosblade/server-auth.yaml
+
+# Create an experiment by the server
+curl -X POST http://127.0.0.1:8000/v1/experiments -H "Authorization: Bearer $TOKEN" -d '{"target":"cpu","action":"fullload","flags":{"cpu-percent":"60"}}'
+
+# Destroy the experiment
+curl -X DELETE http://127.0.0.1:8000/v1/experiments/7c1f7afc281482c8 -H "Authorization: Bearer $TOKEN"
+
+# Scrape the prometheus metrics, which are authenticated as the api
+curl http://127.0.0.1:8000/metrics -H "Authorization: Bearer $TOKEN"`
 }
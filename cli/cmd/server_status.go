@@ -72,11 +72,10 @@ func (ssc *StatusServerCommand) run(cmd *cobra.Command, args []string) error {
 				data["port"] = cmdlineSlice[idx+1]
 			}
 		}
-		ssc.command.Println(spec.ReturnSuccess(data).Print())
-	} else {
-		return spec.ResponseFailWithFlags(spec.ChaosbladeServiceStoped)
+		response := spec.ReturnSuccess(data)
+		return printResponse(cmd, response, func() { ssc.command.Println(response.Print()) })
 	}
-	return nil
+	return spec.ResponseFailWithFlags(spec.ChaosbladeServiceStoped)
 }
 
 func statusServerExample() string {
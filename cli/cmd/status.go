@@ -19,12 +19,16 @@ package cmd
 import (
 	"encoding/json"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 	"github.com/chaosblade-io/chaosblade-spec-go/util"
+
+	"github.com/chaosblade-io/chaosblade/data"
 )
 
 const (
@@ -34,18 +38,42 @@ const (
 	Error     = "Error"
 	Destroyed = "Destroyed"
 	Revoked   = "Revoked"
+	// Expired means the experiment was destroyed by the reconciler after its timeout
+	Expired = "Expired"
+	// Aborted means the experiment was destroyed because an abort condition or a steady-state probe failed
+	Aborted = "Aborted"
 )
 
+// expStatusTransitions are the statuses which the experiment can be changed from to the status
+var expStatusTransitions = map[string][]string{
+	Success:   {Created},
+	Error:     {Created},
+	Destroyed: {Created, Success, Error},
+	Expired:   {Created, Success},
+	Aborted:   {Created, Success},
+}
+
+// updateExpStatus changes the experiment status in a transaction, it fails if the experiment has been changed
+// to a status which can not be changed to the status, for example, the experiment destroyed by others
+func updateExpStatus(uid, status, errMsg string) error {
+	return GetDS().UpdateExperimentStatusByUid(uid, expStatusTransitions[status], status, errMsg)
+}
+
 type StatusCommand struct {
 	baseCommand
 	commandType string
 	target      string
 	action      string
 	flag        string
+	flags       []string
 	uid         string
 	limit       string
 	status      string
+	group       string
 	asc         bool
+	watch       bool
+	interval    time.Duration
+	until       string
 }
 
 func (sc *StatusCommand) Init() {
@@ -63,10 +91,15 @@ func (sc *StatusCommand) Init() {
 	sc.command.Flags().StringVar(&sc.target, "target", "", "experiment target, for example: dubbo")
 	sc.command.Flags().StringVar(&sc.action, "action", "", "sub command, for example:fullload")
 	sc.command.Flags().StringVar(&sc.flag, "flag-filter", "", "flag can do fuzzy search")
+	sc.command.Flags().StringArrayVar(&sc.flags, "flag", nil, "filter the experiments by the exact flag value in key=value format, can be specified multiple times, for example: --flag timeout=60")
 	sc.command.Flags().StringVar(&sc.limit, "limit", "", "limit the count of experiments, support OFFSET clause, for example, limit 4,3 returns only 3 items starting from the 5 position item")
-	sc.command.Flags().StringVar(&sc.status, "status", "", "experiment status. create type supports Created|Success|Error|Destroyed status. prepare type supports Created|Running|Error|Revoked status")
+	sc.command.Flags().StringVar(&sc.status, "status", "", "experiment status. create type supports Created|Success|Error|Destroyed|Expired|Aborted status. prepare type supports Created|Running|Error|Revoked status")
 	sc.command.Flags().StringVar(&sc.uid, "uid", "", "prepare or experiment uid")
+	sc.command.Flags().StringVar(&sc.group, GroupFlag, "", "query the experiments of the group")
 	sc.command.Flags().BoolVar(&sc.asc, "asc", false, "order by CreateTime, default value is false that means order by CreateTime desc")
+	sc.command.Flags().BoolVarP(&sc.watch, "watch", "w", false, "watch the status changes until all the records reach the --until statuses, the records found by the first query are followed even if they do not match the filters anymore, print a table on a terminal and NDJSON events if piped")
+	sc.command.Flags().DurationVar(&sc.interval, "interval", 2*time.Second, "the interval of querying the status in the watch mode")
+	sc.command.Flags().StringVar(&sc.until, "until", strings.Join(terminalStatuses, ","), "the comma separated statuses at which the watch mode stops")
 }
 
 func (sc *StatusCommand) runStatus(command *cobra.Command, args []string) error {
@@ -76,12 +109,40 @@ func (sc *StatusCommand) runStatus(command *cobra.Command, args []string) error
 	} else {
 		uid = sc.uid
 	}
+	if sc.watch {
+		return sc.runWatch(command, uid)
+	}
+	result, err := sc.queryStatus(uid)
+	if err != nil {
+		return err
+	}
+	response := spec.ReturnSuccess(result)
+	return printResponse(command, response, func() {
+		if term.IsTerminal(int(os.Stdout.Fd())) {
+			if bytes, err := json.MarshalIndent(response, "", "\t"); err == nil {
+				sc.command.Println(string(bytes))
+				return
+			}
+		}
+		sc.command.Println(response.Print())
+	})
+}
+
+// queryStatus returns the experiment or preparation of the uid, or the records filtered by the flags
+func (sc *StatusCommand) queryStatus(uid string) (interface{}, error) {
 	var result interface{}
 	var err error
+	if sc.group != "" {
+		sc.commandType = "create"
+	}
 	switch sc.commandType {
 	case "create", "destroy", "c", "d":
 		if uid != "" {
 			result, err = GetDS().QueryExperimentModelByUid(uid)
+		} else if sc.group != "" {
+			result, err = sc.queryExperimentModelsByGroup()
+		} else if len(sc.flags) > 0 {
+			result, err = sc.queryExperimentModelsByFlags()
 		} else {
 			result, err = GetDS().QueryExperimentModels(sc.target, sc.action, sc.flag, sc.status, sc.limit, sc.asc)
 		}
@@ -93,7 +154,7 @@ func (sc *StatusCommand) runStatus(command *cobra.Command, args []string) error
 		}
 	default:
 		if uid == "" {
-			return spec.ResponseFailWithFlags(spec.ParameterLess, "type|uid, must specify the right type or uid")
+			return nil, spec.ResponseFailWithFlags(spec.ParameterLess, "type|uid, must specify the right type or uid")
 		}
 		result, err = GetDS().QueryExperimentModelByUid(uid)
 		if util.IsNil(result) || err != nil {
@@ -101,23 +162,48 @@ func (sc *StatusCommand) runStatus(command *cobra.Command, args []string) error
 		}
 	}
 	if err != nil {
-		return spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
+		if response, ok := err.(*spec.Response); ok {
+			return nil, response
+		}
+		return nil, spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
 	}
 	if util.IsNil(result) {
-		return spec.ResponseFailWithFlags(spec.DataNotFound, uid)
+		return nil, spec.ResponseFailWithFlags(spec.DataNotFound, uid)
 	}
-	response := spec.ReturnSuccess(result)
+	return result, nil
+}
 
-	if term.IsTerminal(int(os.Stdout.Fd())) {
-		bytes, err := json.MarshalIndent(response, "", "\t")
-		if err != nil {
-			return response
+// queryExperimentModelsByGroup returns the experiments of the group, filtered by the status flag
+func (sc *StatusCommand) queryExperimentModelsByGroup() ([]*data.ExperimentModel, error) {
+	models, err := GetDS().QueryExperimentModelsByGroup(sc.group)
+	if err != nil || sc.status == "" {
+		return models, err
+	}
+	filtered := make([]*data.ExperimentModel, 0)
+	for _, model := range models {
+		if strings.EqualFold(model.Status, sc.status) {
+			filtered = append(filtered, model)
+		}
+	}
+	return filtered, nil
+}
+
+// queryExperimentModelsByFlags returns the experiments having all the flag values
+func (sc *StatusCommand) queryExperimentModelsByFlags() ([]*data.ExperimentModel, error) {
+	if sc.flag != "" {
+		return nil, spec.ResponseFailWithFlags(spec.ParameterInvalid, "flag-filter", sc.flag,
+			"the --flag-filter flag can not be used with --flag")
+	}
+	flags, err := parseKeyValues("flag", sc.flags)
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, "flag", sc.flags, err)
+	}
+	for name, value := range flags {
+		if value == "" {
+			return nil, spec.ResponseFailWithFlags(spec.ParameterIllegal, "flag", name, "the flag value is empty")
 		}
-		sc.command.Println(string(bytes))
-	} else {
-		sc.command.Println(response.Print())
 	}
-	return nil
+	return GetDS().QueryExperimentModelsByFlags(sc.target, sc.action, sc.status, flags, sc.limit, sc.asc)
 }
 
 func statusExample() string {
@@ -126,5 +212,13 @@ blade status cc015e9bd9c68406
 # Query chaos experiments
 blade status --type create
 # Query preparations
-blade status --type prepare`
+blade status --type prepare
+# Query the experiments of a group
+blade status --group gameday-1
+# Query the experiments by the flag values
+blade status --type create --flag timeout=60 --flag names=pod-a
+# Watch the experiment until it is destroyed or failed
+blade status cc015e9bd9c68406 --watch
+# Watch the async created experiment until it takes effect
+blade status cc015e9bd9c68406 --watch --until Success,Error`
 }
@@ -0,0 +1,293 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+	"github.com/chaosblade-io/chaosblade-spec-go/util"
+
+	"github.com/chaosblade-io/chaosblade/data"
+	"github.com/chaosblade-io/chaosblade/exec/kubernetes"
+)
+
+// terminalStatuses are the statuses which the experiments and preparations are not changed from
+var terminalStatuses = []string{Error, Destroyed, Expired, Aborted, Revoked}
+
+// statusEvent is a status change of an experiment or preparation, the K8s is the status of the chaosblade resource
+type statusEvent struct {
+	Time           string         `json:"time"`
+	Kind           string         `json:"kind"`
+	Uid            string         `json:"uid"`
+	Target         string         `json:"target"`
+	PreviousStatus string         `json:"previousStatus,omitempty"`
+	Status         string         `json:"status"`
+	Error          string         `json:"error,omitempty"`
+	K8s            *spec.Response `json:"k8s,omitempty"`
+}
+
+// statusWatcher polls the records and writes the events of the changed ones. The records returned by the first
+// query are followed by their uids, so the records changed out of the query filters are still watched.
+type statusWatcher struct {
+	query func() (interface{}, error)
+	// follow returns the record of the kind and uid, which is nil if the record is deleted
+	follow    func(kind, uid string) (interface{}, error)
+	k8sStatus func(model *data.ExperimentModel) *spec.Response
+	until     []string
+	interval  time.Duration
+	writer    io.Writer
+	// table prints the events as the table rows, else as NDJSON
+	table       bool
+	headerDone  bool
+	lastEvents  map[string]*statusEvent
+	lastMessage map[string]string
+	// followed are the events of the records which are followed, following is true after the first records found
+	followed  []*statusEvent
+	following bool
+}
+
+func (sc *StatusCommand) runWatch(command *cobra.Command, uid string) error {
+	until := make([]string, 0)
+	for _, status := range strings.Split(sc.until, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			until = append(until, data.UpperFirst(status))
+		}
+	}
+	if len(until) == 0 {
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, "until", sc.until, "the statuses are empty")
+	}
+	if sc.interval <= 0 {
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, "interval", sc.interval, "the interval must be positive")
+	}
+	// the table is printed on a terminal by default
+	table := term.IsTerminal(int(os.Stdout.Fd()))
+	switch outputFormat {
+	case "":
+	case OutputTable, OutputWide:
+		table = true
+	case OutputJSON:
+		table = false
+	default:
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, OutputFlag, outputFormat,
+			"only support table, wide and json in the watch mode")
+	}
+	watcher := &statusWatcher{
+		query:     func() (interface{}, error) { return sc.queryStatus(uid) },
+		follow:    followRecord,
+		k8sStatus: queryK8sStatus,
+		until:     until,
+		interval:  sc.interval,
+		writer:    command.OutOrStdout(),
+		table:     table,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return watcher.watch(ctx)
+}
+
+// watch polls the records until all of them reach the until statuses or the context is done
+func (w *statusWatcher) watch(ctx context.Context) error {
+	w.lastEvents = make(map[string]*statusEvent)
+	w.lastMessage = make(map[string]string)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		done, err := w.poll()
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// followRecord returns the experiment or preparation of the uid
+func followRecord(kind, uid string) (interface{}, error) {
+	var result interface{}
+	var err error
+	if kind == data.AuditKindExperiment {
+		result, err = GetDS().QueryExperimentModelByUid(uid)
+	} else {
+		result, err = GetDS().QueryPreparationByUid(uid)
+	}
+	if err != nil {
+		return nil, spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
+	}
+	return result, nil
+}
+
+// poll writes the events of the changed records, and returns true if all the records reach the until statuses
+// or all the followed records are deleted
+func (w *statusWatcher) poll() (bool, error) {
+	events, err := w.statusEventsOfRecords()
+	if err != nil {
+		return false, err
+	}
+	done := len(events) > 0 || w.following
+	for _, event := range events {
+		if !containsString(w.until, event.Status) {
+			done = false
+		}
+		// the event is compared without the time
+		eventTime := event.Time
+		event.Time = ""
+		message, err := json.Marshal(event)
+		if err != nil {
+			return false, spec.ResponseFailWithFlags(spec.ResultMarshalFailed, event.Uid, err)
+		}
+		event.Time = eventTime
+		key := event.Kind + "/" + event.Uid
+		if w.lastMessage[key] == string(message) {
+			continue
+		}
+		if last, ok := w.lastEvents[key]; ok {
+			event.PreviousStatus = last.Status
+		}
+		w.lastEvents[key], w.lastMessage[key] = event, string(message)
+		if err := w.write(event); err != nil {
+			return false, err
+		}
+	}
+	return done, nil
+}
+
+// statusEventsOfRecords queries the records until some are found, and then follows them by the uids.
+// The deleted records are not followed anymore.
+func (w *statusWatcher) statusEventsOfRecords() ([]*statusEvent, error) {
+	if !w.following {
+		result, err := w.query()
+		if err != nil {
+			return nil, err
+		}
+		events := w.statusEvents(result)
+		w.followed, w.following = events, len(events) > 0 && w.follow != nil
+		return events, nil
+	}
+	events := make([]*statusEvent, 0, len(w.followed))
+	for _, followed := range w.followed {
+		result, err := w.follow(followed.Kind, followed.Uid)
+		if err != nil {
+			return nil, err
+		}
+		if util.IsNil(result) {
+			continue
+		}
+		events = append(events, w.statusEvents(result)...)
+	}
+	w.followed = events
+	return events, nil
+}
+
+// statusEvents converts the query result to the events, the k8s experiments not at the until statuses
+// contain the status of the chaosblade resource
+func (w *statusWatcher) statusEvents(result interface{}) []*statusEvent {
+	now := time.Now().Format(time.RFC3339)
+	events := make([]*statusEvent, 0)
+	addExperiment := func(model *data.ExperimentModel) {
+		event := &statusEvent{
+			Time:   now,
+			Kind:   data.AuditKindExperiment,
+			Uid:    model.Uid,
+			Target: strings.TrimSpace(model.Command + " " + model.SubCommand),
+			Status: model.Status,
+			Error:  model.Error,
+		}
+		if model.Command == "k8s" && w.k8sStatus != nil && !containsString(w.until, model.Status) {
+			event.K8s = w.k8sStatus(model)
+		}
+		events = append(events, event)
+	}
+	addPreparation := func(record *data.PreparationRecord) {
+		events = append(events, &statusEvent{
+			Time:   now,
+			Kind:   data.AuditKindPreparation,
+			Uid:    record.Uid,
+			Target: record.ProgramType,
+			Status: record.Status,
+			Error:  record.Error,
+		})
+	}
+	switch records := result.(type) {
+	case *data.ExperimentModel:
+		addExperiment(records)
+	case []*data.ExperimentModel:
+		for _, model := range records {
+			addExperiment(model)
+		}
+	case *data.PreparationRecord:
+		addPreparation(records)
+	case []*data.PreparationRecord:
+		for _, record := range records {
+			addPreparation(record)
+		}
+	}
+	return events
+}
+
+func (w *statusWatcher) write(event *statusEvent) error {
+	if !w.table {
+		return json.NewEncoder(w.writer).Encode(event)
+	}
+	const rowFormat = "%-25s %-12s %-18s %-24s %-10s %-10s %s\n"
+	if !w.headerDone {
+		if _, err := fmt.Fprintf(w.writer, rowFormat, "TIME", "KIND", "UID", "TARGET", "PREVIOUS", "STATUS", "ERROR"); err != nil {
+			return err
+		}
+		w.headerDone = true
+	}
+	errMsg := event.Error
+	if event.K8s != nil && !event.K8s.Success && errMsg == "" {
+		errMsg = event.K8s.Err
+	}
+	_, err := fmt.Fprintf(w.writer, rowFormat, event.Time, event.Kind, event.Uid, event.Target,
+		event.PreviousStatus, event.Status, errMsg)
+	return err
+}
+
+// queryK8sStatus queries the chaosblade resource of the experiment by the kubeconfig, proxy and token flags
+// of the experiment
+func queryK8sStatus(model *data.ExperimentModel) *spec.Response {
+	ctx := context.WithValue(context.Background(), spec.Uid, model.Uid)
+	flags := recordFlags(model)
+	response, _ := kubernetes.QueryStatus(ctx, kubernetes.QueryCreate, flags[kubernetes.KubeConfigFlag.Name],
+		flags[kubernetes.KubectlProxyFlag.Name], flags[kubernetes.TokenFlag.Name])
+	return response
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
+	"github.com/chaosblade-io/chaosblade/data"
+)
+
+func Test_statusWatcher_poll(t *testing.T) {
+	src := data.NewMemorySource()
+	for _, model := range []*data.ExperimentModel{
+		{Uid: "uid1", Command: "cpu", SubCommand: "fullload", Status: Created},
+		{Uid: "uid2", Command: "k8s", SubCommand: "pod-cpu fullload", Status: Success},
+	} {
+		if err := src.InsertExperimentModel(model); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+	}
+	k8sQueries := 0
+	buffer := &bytes.Buffer{}
+	watcher := &statusWatcher{
+		query: func() (interface{}, error) {
+			return src.QueryExperimentModels("", "", "", "", "", true)
+		},
+		k8sStatus: func(model *data.ExperimentModel) *spec.Response {
+			k8sQueries++
+			return spec.ReturnSuccess(model.Uid)
+		},
+		until:       terminalStatuses,
+		writer:      buffer,
+		lastEvents:  make(map[string]*statusEvent),
+		lastMessage: make(map[string]string),
+	}
+	steps := []struct {
+		name     string
+		update   func()
+		expected []string
+		done     bool
+	}{
+		{"initial", func() {}, []string{"uid1::Created", "uid2::Success"}, false},
+		{"unchanged", func() {}, []string{}, false},
+		{"success", func() { src.UpdateExperimentModelByUid("uid1", Success, "") }, []string{"uid1:Created:Success"}, false},
+		{"destroyed", func() {
+			src.UpdateExperimentModelByUid("uid1", Destroyed, "")
+			src.UpdateExperimentModelByUid("uid2", Destroyed, "")
+		}, []string{"uid1:Success:Destroyed", "uid2:Success:Destroyed"}, true},
+	}
+	for _, step := range steps {
+		step.update()
+		buffer.Reset()
+		done, err := watcher.poll()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		if done != step.done {
+			t.Errorf("%s: unexpected done: %t", step.name, done)
+		}
+		transitions := make([]string, 0)
+		decoder := json.NewDecoder(buffer)
+		for decoder.More() {
+			event := &statusEvent{}
+			if err := decoder.Decode(event); err != nil {
+				t.Fatalf("%s: decode event failed, %v", step.name, err)
+			}
+			if event.Uid == "uid2" && (event.K8s == nil) == (event.Status != Destroyed) {
+				t.Errorf("%s: unexpected k8s status of the %s event: %v", step.name, event.Status, event.K8s)
+			}
+			transitions = append(transitions, event.Uid+":"+event.PreviousStatus+":"+event.Status)
+		}
+		if strings.Join(transitions, ",") != strings.Join(step.expected, ",") {
+			t.Errorf("%s: unexpected events: %v, expected: %v", step.name, transitions, step.expected)
+		}
+	}
+	if k8sQueries != 3 {
+		t.Errorf("unexpected k8s queries: %d", k8sQueries)
+	}
+}
+
+func Test_statusWatcher_follow(t *testing.T) {
+	src := data.NewMemorySource()
+	for _, model := range []*data.ExperimentModel{
+		{Uid: "uid1", Command: "cpu", SubCommand: "fullload", Status: Success},
+		{Uid: "uid2", Command: "mem", SubCommand: "load", Status: Success},
+		{Uid: "uid3", Command: "disk", SubCommand: "fill", Status: Created},
+	} {
+		if err := src.InsertExperimentModel(model); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+	}
+	buffer := &bytes.Buffer{}
+	watcher := &statusWatcher{
+		// the records changed out of the status filter are followed by the uids
+		query: func() (interface{}, error) {
+			return src.QueryExperimentModels("", "", "", Success, "", true)
+		},
+		follow: func(kind, uid string) (interface{}, error) {
+			return src.QueryExperimentModelByUid(uid)
+		},
+		until:       terminalStatuses,
+		writer:      buffer,
+		lastEvents:  make(map[string]*statusEvent),
+		lastMessage: make(map[string]string),
+	}
+	steps := []struct {
+		name     string
+		update   func()
+		expected []string
+		done     bool
+	}{
+		{"initial", func() {}, []string{"uid1::Success", "uid2::Success"}, false},
+		{"not followed", func() { src.UpdateExperimentModelByUid("uid3", Success, "") }, []string{}, false},
+		{"destroyed", func() { src.UpdateExperimentModelByUid("uid1", Destroyed, "") }, []string{"uid1:Success:Destroyed"}, false},
+		{"deleted", func() { src.DeleteExperimentModelByUid("uid2") }, []string{}, true},
+	}
+	for _, step := range steps {
+		step.update()
+		buffer.Reset()
+		done, err := watcher.poll()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		if done != step.done {
+			t.Errorf("%s: unexpected done: %t", step.name, done)
+		}
+		transitions := make([]string, 0)
+		decoder := json.NewDecoder(buffer)
+		for decoder.More() {
+			event := &statusEvent{}
+			if err := decoder.Decode(event); err != nil {
+				t.Fatalf("%s: decode event failed, %v", step.name, err)
+			}
+			transitions = append(transitions, event.Uid+":"+event.PreviousStatus+":"+event.Status)
+		}
+		if strings.Join(transitions, ",") != strings.Join(step.expected, ",") {
+			t.Errorf("%s: unexpected events: %v, expected: %v", step.name, transitions, step.expected)
+		}
+	}
+}
+
+func Test_statusWatcher_table(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	watcher := &statusWatcher{
+		query: func() (interface{}, error) {
+			return &data.PreparationRecord{Uid: "uid1", ProgramType: "jvm", Status: Revoked}, nil
+		},
+		until:       terminalStatuses,
+		writer:      buffer,
+		table:       true,
+		lastEvents:  make(map[string]*statusEvent),
+		lastMessage: make(map[string]string),
+	}
+	done, err := watcher.poll()
+	if err != nil || !done {
+		t.Fatalf("unexpected result, done: %t, err: %v", done, err)
+	}
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "TIME") || !strings.Contains(lines[1], "Revoked") {
+		t.Errorf("unexpected table: %s", buffer.String())
+	}
+}
@@ -19,6 +19,8 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/chaosblade-io/chaosblade-spec-go/spec"
+
 	"github.com/chaosblade-io/chaosblade/version"
 )
 
@@ -32,23 +34,31 @@ func (vc *VersionCommand) Init() {
 		Short:   "Print version info",
 		Long:    "Print detailed version information including Git details",
 		Aliases: []string{"v"},
-		Run: func(cmd *cobra.Command, args []string) {
-			cmd.Printf("ChaosBlade Version Information:\n")
-			cmd.Printf("==============================\n")
-			cmd.Printf("Version:     %s\n", version.Ver)
-			cmd.Printf("Git Tag:     %s\n", version.GitTag)
-			cmd.Printf("Git Commit:  %s\n", version.GitCommit)
-			cmd.Printf("Git Branch:  %s\n", version.GitBranch)
-			cmd.Printf("Build Time:  %s\n", version.BuildTime)
-
-			if version.IsRelease() {
-				cmd.Printf("Release:     Yes (Production)\n")
-			} else {
-				cmd.Printf("Release:     No (Development)\n")
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := make(map[string]interface{})
+			for key, value := range version.GetVersionInfo() {
+				info[key] = value
 			}
-
-			cmd.Printf("==============================\n")
-			return
+			info["release"] = version.IsRelease()
+			return printResponse(cmd, spec.ReturnSuccess(info), func() { printVersion(cmd) })
 		},
 	}
 }
+
+func printVersion(cmd *cobra.Command) {
+	cmd.Printf("ChaosBlade Version Information:\n")
+	cmd.Printf("==============================\n")
+	cmd.Printf("Version:     %s\n", version.Ver)
+	cmd.Printf("Git Tag:     %s\n", version.GitTag)
+	cmd.Printf("Git Commit:  %s\n", version.GitCommit)
+	cmd.Printf("Git Branch:  %s\n", version.GitBranch)
+	cmd.Printf("Build Time:  %s\n", version.BuildTime)
+
+	if version.IsRelease() {
+		cmd.Printf("Release:     Yes (Production)\n")
+	} else {
+		cmd.Printf("Release:     No (Development)\n")
+	}
+
+	cmd.Printf("==============================\n")
+}
@@ -0,0 +1,458 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditSourceEnv overrides the source recorded in the audit entries, it is set for the background processes
+const AuditSourceEnv = "CHAOSBLADE_AUDIT_SOURCE"
+
+// The sources which change the experiments and preparations
+const (
+	AuditSourceCLI     = "cli"
+	AuditSourceAsync   = "async"
+	AuditSourceTimeout = "timeout"
+	AuditSourceServer  = "server"
+)
+
+const (
+	AuditKindExperiment  = "experiment"
+	AuditKindPreparation = "preparation"
+)
+
+// AuditEntry is a status transition of an experiment or preparation. The Hash is the sha256 of the entry
+// with the empty Hash, and the PrevHash is the Hash of the previous entry, so a modified, inserted or deleted entry
+// breaks the chain. The hash is not keyed, so the deleted last entries and a recomputed chain are only detected
+// by verifying the head hash kept outside the data file, see VerifyAuditHead.
+type AuditEntry struct {
+	Seq            int64  `json:"seq"`
+	Time           string `json:"time"`
+	Kind           string `json:"kind"`
+	Uid            string `json:"uid"`
+	Action         string `json:"action"`
+	PreviousStatus string `json:"previousStatus"`
+	Status         string `json:"status"`
+	User           string `json:"user"`
+	CommandLine    string `json:"commandLine"`
+	Source         string `json:"source"`
+	PrevHash       string `json:"prevHash"`
+	Hash           string `json:"hash"`
+}
+
+// AuditJournal is the append-only journal of the status transitions, the entries can not be updated or deleted
+type AuditJournal interface {
+	// AppendAuditEntry sets the Seq, PrevHash and Hash of the entry by the last entry and appends it
+	AppendAuditEntry(entry *AuditEntry) error
+
+	// QueryAuditEntries returns the entries order by seq
+	QueryAuditEntries() ([]*AuditEntry, error)
+}
+
+// errAuditConflict is returned if the entry is not chained to the last entry, which is appended concurrently
+var errAuditConflict = errors.New("the audit entry is not chained to the last entry")
+
+// AuditChainError is returned if the chain is broken at the entry of the seq
+type AuditChainError struct {
+	Seq    int64
+	Reason string
+}
+
+func (e *AuditChainError) Error() string {
+	return fmt.Sprintf("the audit chain is broken at the entry %d, %s", e.Seq, e.Reason)
+}
+
+var (
+	auditSource     = os.Getenv(AuditSourceEnv)
+	auditSourceLock sync.RWMutex
+	auditUser       string
+	auditUserOnce   sync.Once
+)
+
+// SetAuditSource sets the source recorded in the audit entries of the process
+func SetAuditSource(source string) {
+	auditSourceLock.Lock()
+	defer auditSourceLock.Unlock()
+	auditSource = source
+}
+
+func getAuditSource() string {
+	auditSourceLock.RLock()
+	defer auditSourceLock.RUnlock()
+	if auditSource == "" {
+		return AuditSourceCLI
+	}
+	return auditSource
+}
+
+// getAuditUser returns the name of the OS user, or the uid if the user can not be looked up
+func getAuditUser() string {
+	auditUserOnce.Do(func() {
+		if current, err := user.Current(); err == nil && current.Username != "" {
+			auditUser = current.Username
+		} else if name := os.Getenv("USER"); name != "" {
+			auditUser = name
+		} else {
+			auditUser = strconv.Itoa(os.Getuid())
+		}
+	})
+	return auditUser
+}
+
+// hashAuditEntry returns the hex sha256 of the JSON encoded entry without the Hash
+func hashAuditEntry(entry *AuditEntry) string {
+	copied := *entry
+	copied.Hash = ""
+	bytes, _ := json.Marshal(&copied)
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:])
+}
+
+// chainAuditEntry links the entry to the last one, the last is nil for the first entry
+func chainAuditEntry(entry, last *AuditEntry) {
+	entry.Seq, entry.PrevHash = 1, ""
+	if last != nil {
+		entry.Seq, entry.PrevHash = last.Seq+1, last.Hash
+	}
+	entry.Hash = hashAuditEntry(entry)
+}
+
+// VerifyAuditEntries checks the entries order by seq, it returns *AuditChainError at the first broken entry
+func VerifyAuditEntries(entries []*AuditEntry) error {
+	var last *AuditEntry
+	for _, entry := range entries {
+		expectedSeq, expectedPrevHash := int64(1), ""
+		if last != nil {
+			expectedSeq, expectedPrevHash = last.Seq+1, last.Hash
+		}
+		if entry.Seq != expectedSeq {
+			return &AuditChainError{Seq: entry.Seq, Reason: fmt.Sprintf("expected the seq %d", expectedSeq)}
+		}
+		if entry.PrevHash != expectedPrevHash {
+			return &AuditChainError{Seq: entry.Seq, Reason: "the previous hash does not match the previous entry"}
+		}
+		if entry.Hash != hashAuditEntry(entry) {
+			return &AuditChainError{Seq: entry.Seq, Reason: "the hash does not match the content"}
+		}
+		last = entry
+	}
+	return nil
+}
+
+// VerifyAuditHead checks the entries contain the head, which is the Hash of the last entry verified before and kept
+// outside the data file, so the entries deleted from the end or the chain recomputed after it are detected
+func VerifyAuditHead(entries []*AuditEntry, head string) error {
+	for _, entry := range entries {
+		if entry.Hash == head {
+			return nil
+		}
+	}
+	seq := int64(1)
+	if len(entries) > 0 {
+		seq = entries[len(entries)-1].Seq + 1
+	}
+	return &AuditChainError{Seq: seq, Reason: fmt.Sprintf("the head %s is not found, "+
+		"the entries are deleted or the chain is recomputed", head)}
+}
+
+// auditAction names the transition by the kind and the new status, the empty status means the record is deleted
+func auditAction(kind string, inserted bool, status string) string {
+	switch {
+	case inserted && kind == AuditKindExperiment:
+		return "create"
+	case inserted:
+		return "prepare"
+	case status == "":
+		return "delete"
+	case status == "Destroyed":
+		return "destroy"
+	case status == "Revoked":
+		return "revoke"
+	case status == "Expired":
+		return "expire"
+	case status == "Aborted":
+		return "abort"
+	}
+	return "update"
+}
+
+// auditedSource records the status transitions of the source in its audit journal
+type auditedSource struct {
+	SourceI
+}
+
+// NewAuditedSource returns the source which appends an audit entry and publishes the lifecycle event of every
+// status transition together with the transition, the transition fails if they can not be written.
+func NewAuditedSource(src SourceI) SourceI {
+	if _, ok := src.(*auditedSource); ok {
+		return src
+	}
+	return &auditedSource{SourceI: src}
+}
+
+// unwrapSource returns the source decorated by the audited source
+func unwrapSource(src SourceI) SourceI {
+	if audited, ok := src.(*auditedSource); ok {
+		return audited.SourceI
+	}
+	return src
+}
+
+func newAuditEntry(kind, uid, action, previousStatus, status string) *AuditEntry {
+	return &AuditEntry{
+		Time:           time.Now().Format(time.RFC3339Nano),
+		Kind:           kind,
+		Uid:            uid,
+		Action:         action,
+		PreviousStatus: previousStatus,
+		Status:         status,
+		User:           getAuditUser(),
+		CommandLine:    strings.Join(os.Args, " "),
+		Source:         getAuditSource(),
+	}
+}
+
+// apply writes the transition with its journal. The change writes the transition by the source which is not
+// a journaledSource, and the journal is written after it.
+func (a *auditedSource) apply(t *transition, change func() error) error {
+	var code int32
+	if value, ok := eventCodes.LoadAndDelete(t.uid); ok {
+		code = value.(int32)
+	}
+	router := getEventRouter()
+	var deliveries []*EventDelivery
+	t.journal = func(uid, previous string, experiment *ExperimentModel, preparation *PreparationRecord) (
+		*AuditEntry, []*EventDelivery, error,
+	) {
+		status := t.status
+		if experiment != nil {
+			status = experiment.Status
+		} else if preparation != nil {
+			status = preparation.Status
+		}
+		entry := newAuditEntry(t.kind, uid, auditAction(t.kind, t.inserted(), status), previous, status)
+		deliveries = nil
+		// the deleted records and the inserted preparations are not the lifecycle events
+		if t.deleted || t.preparation != nil || (t.experiment != nil && status != "Created") {
+			return entry, nil, nil
+		}
+		var err error
+		deliveries, err = newEventDeliveries(router, t.kind, uid, previous, status, code, experiment, preparation)
+		return entry, deliveries, err
+	}
+	var err error
+	if journaled, ok := a.SourceI.(journaledSource); ok {
+		err = journaled.applyTransition(t)
+	} else {
+		err = a.applySequentially(t, change)
+	}
+	if err != nil {
+		return err
+	}
+	if len(deliveries) > 0 {
+		router.Notify(deliveries)
+	}
+	return nil
+}
+
+// applySequentially writes the change, and then the journal of it if the record exists
+func (a *auditedSource) applySequentially(t *transition, change func() error) error {
+	previous, found, err := a.status(t)
+	if err != nil {
+		return err
+	}
+	if err := change(); err != nil {
+		return err
+	}
+	if !found && !t.inserted() {
+		return nil
+	}
+	var experiment *ExperimentModel
+	var preparation *PreparationRecord
+	if !t.deleted && t.kind == AuditKindExperiment {
+		experiment, err = a.SourceI.QueryExperimentModelByUid(t.uid)
+	} else if !t.deleted {
+		preparation, err = a.SourceI.QueryPreparationByUid(t.uid)
+	}
+	if err != nil {
+		return err
+	}
+	entry, deliveries, err := t.journal(t.uid, previous, experiment, preparation)
+	if err != nil {
+		return err
+	}
+	if err := a.SourceI.AppendAuditEntry(entry); err != nil {
+		return err
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return a.SourceI.InsertEventDeliveries(deliveries)
+}
+
+// status returns the status of the record changed by the transition, the inserted record is not found
+func (a *auditedSource) status(t *transition) (string, bool, error) {
+	if t.inserted() {
+		return "", false, nil
+	}
+	if t.kind == AuditKindExperiment {
+		model, err := a.SourceI.QueryExperimentModelByUid(t.uid)
+		if err != nil || model == nil {
+			return "", false, err
+		}
+		return model.Status, true, nil
+	}
+	record, err := a.SourceI.QueryPreparationByUid(t.uid)
+	if err != nil || record == nil {
+		return "", false, err
+	}
+	return record.Status, true, nil
+}
+
+func (a *auditedSource) prune(kind string, before time.Time, statuses []string,
+	change func(time.Time, []string) ([]string, error),
+) ([]string, error) {
+	journal := func(uid, previous string, experiment *ExperimentModel, preparation *PreparationRecord) (
+		*AuditEntry, []*EventDelivery, error,
+	) {
+		return newAuditEntry(kind, uid, "prune", "", ""), nil, nil
+	}
+	if journaled, ok := a.SourceI.(journaledSource); ok {
+		return journaled.pruneWithJournal(kind, before, statuses, journal)
+	}
+	uids, err := change(before, statuses)
+	if err != nil {
+		return uids, err
+	}
+	for _, uid := range uids {
+		if err := a.SourceI.AppendAuditEntry(newAuditEntry(kind, uid, "prune", "", "")); err != nil {
+			return uids, err
+		}
+	}
+	return uids, nil
+}
+
+func (a *auditedSource) InsertExperimentModel(model *ExperimentModel) error {
+	return a.apply(&transition{kind: AuditKindExperiment, uid: model.Uid, experiment: model}, func() error {
+		return a.SourceI.InsertExperimentModel(model)
+	})
+}
+
+func (a *auditedSource) UpdateExperimentModelByUid(uid, status, errMsg string) error {
+	t := &transition{kind: AuditKindExperiment, uid: uid, status: status, errMsg: errMsg}
+	return a.apply(t, func() error {
+		return a.SourceI.UpdateExperimentModelByUid(uid, status, errMsg)
+	})
+}
+
+func (a *auditedSource) UpdateExperimentStatusByUid(uid string, expected []string, status, errMsg string) error {
+	// the record must exist even if any status is expected
+	t := &transition{kind: AuditKindExperiment, uid: uid, status: status, errMsg: errMsg, expected: []string{}}
+	t.expected = append(t.expected, expected...)
+	return a.apply(t, func() error {
+		return a.SourceI.UpdateExperimentStatusByUid(uid, expected, status, errMsg)
+	})
+}
+
+func (a *auditedSource) DeleteExperimentModelByUid(uid string) error {
+	return a.apply(&transition{kind: AuditKindExperiment, uid: uid, deleted: true}, func() error {
+		return a.SourceI.DeleteExperimentModelByUid(uid)
+	})
+}
+
+func (a *auditedSource) PruneExperimentModels(before time.Time, statuses []string) ([]string, error) {
+	return a.prune(AuditKindExperiment, before, statuses, a.SourceI.PruneExperimentModels)
+}
+
+func (a *auditedSource) InsertPreparationRecord(record *PreparationRecord) error {
+	return a.apply(&transition{kind: AuditKindPreparation, uid: record.Uid, preparation: record}, func() error {
+		return a.SourceI.InsertPreparationRecord(record)
+	})
+}
+
+func (a *auditedSource) UpdatePreparationRecordByUid(uid, status, errMsg string) error {
+	t := &transition{kind: AuditKindPreparation, uid: uid, status: status, errMsg: errMsg}
+	return a.apply(t, func() error {
+		return a.SourceI.UpdatePreparationRecordByUid(uid, status, errMsg)
+	})
+}
+
+func (a *auditedSource) PrunePreparationRecords(before time.Time, statuses []string) ([]string, error) {
+	return a.prune(AuditKindPreparation, before, statuses, a.SourceI.PrunePreparationRecords)
+}
+
+// AppendAuditEntry appends the entry in a transaction, the transaction takes the write lock when beginning,
+// so the entries appended by the processes concurrently are chained in order
+func (s *Source) AppendAuditEntry(entry *AuditEntry) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := appendAuditEntry(tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// appendAuditEntry chains the entry to the last entry and inserts it in the transaction
+func appendAuditEntry(tx *sql.Tx, entry *AuditEntry) error {
+	last := &AuditEntry{}
+	err := tx.QueryRow(`SELECT seq, hash FROM audit_log ORDER BY seq DESC LIMIT 1`).Scan(&last.Seq, &last.Hash)
+	if err == sql.ErrNoRows {
+		last = nil
+	} else if err != nil {
+		return err
+	}
+	chainAuditEntry(entry, last)
+	_, err = tx.Exec(`INSERT INTO audit_log (seq, time, kind, uid, action, previous_status, status, os_user, command_line,
+	source, prev_hash, hash) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Seq, entry.Time, entry.Kind, entry.Uid, entry.Action, entry.PreviousStatus, entry.Status, entry.User,
+		entry.CommandLine, entry.Source, entry.PrevHash, entry.Hash)
+	return err
+}
+
+func (s *Source) QueryAuditEntries() ([]*AuditEntry, error) {
+	rows, err := s.DB.Query(`SELECT seq, time, kind, uid, action, previous_status, status, os_user, command_line,
+	source, prev_hash, hash FROM audit_log ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	entries := make([]*AuditEntry, 0)
+	for rows.Next() {
+		entry := &AuditEntry{}
+		if err := rows.Scan(&entry.Seq, &entry.Time, &entry.Kind, &entry.Uid, &entry.Action, &entry.PreviousStatus,
+			&entry.Status, &entry.User, &entry.CommandLine, &entry.Source, &entry.PrevHash, &entry.Hash); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func newAuditEntries(count int) []*AuditEntry {
+	entries := make([]*AuditEntry, 0, count)
+	var last *AuditEntry
+	for i := 0; i < count; i++ {
+		entry := &AuditEntry{Kind: AuditKindExperiment, Uid: "uid", Action: "create", Status: "Created", Source: AuditSourceCLI}
+		chainAuditEntry(entry, last)
+		entries = append(entries, entry)
+		last = entry
+	}
+	return entries
+}
+
+func TestVerifyAuditEntries(t *testing.T) {
+	tests := []struct {
+		name      string
+		tamper    func(entries []*AuditEntry) []*AuditEntry
+		brokenSeq int64
+	}{
+		{"intact", func(entries []*AuditEntry) []*AuditEntry { return entries }, 0},
+		{"modified", func(entries []*AuditEntry) []*AuditEntry {
+			entries[1].Status = "Destroyed"
+			return entries
+		}, 2},
+		{"rehashed", func(entries []*AuditEntry) []*AuditEntry {
+			entries[1].Status = "Destroyed"
+			entries[1].Hash = hashAuditEntry(entries[1])
+			return entries
+		}, 3},
+		{"deleted", func(entries []*AuditEntry) []*AuditEntry {
+			return append(entries[:1], entries[2:]...)
+		}, 3},
+		{"deleted first", func(entries []*AuditEntry) []*AuditEntry { return entries[1:] }, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyAuditEntries(tt.tamper(newAuditEntries(3)))
+			if tt.brokenSeq == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			chainErr, ok := err.(*AuditChainError)
+			if !ok || chainErr.Seq != tt.brokenSeq {
+				t.Errorf("unexpected error: %v, expected broken at %d", err, tt.brokenSeq)
+			}
+		})
+	}
+}
+
+func TestVerifyAuditHead(t *testing.T) {
+	head := newAuditEntries(3)[2].Hash
+	recomputed := newAuditEntries(3)
+	recomputed[0].User = "nobody"
+	for i := range recomputed {
+		var last *AuditEntry
+		if i > 0 {
+			last = recomputed[i-1]
+		}
+		chainAuditEntry(recomputed[i], last)
+	}
+	tests := []struct {
+		name      string
+		entries   []*AuditEntry
+		brokenSeq int64
+	}{
+		{"head", newAuditEntries(3), 0},
+		{"appended after head", newAuditEntries(5), 0},
+		{"deleted last", newAuditEntries(2), 3},
+		{"recomputed", recomputed, 4},
+		{"empty", nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the recomputed chain is valid without the head
+			if err := VerifyAuditEntries(tt.entries); err != nil {
+				t.Fatalf("unexpected chain error: %v", err)
+			}
+			err := VerifyAuditHead(tt.entries, head)
+			if tt.brokenSeq == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			chainErr, ok := err.(*AuditChainError)
+			if !ok || chainErr.Seq != tt.brokenSeq {
+				t.Errorf("unexpected error: %v, expected broken at %d", err, tt.brokenSeq)
+			}
+		})
+	}
+}
+
+func TestSource_auditLogAppendOnly(t *testing.T) {
+	src := newTestSource(t)
+	if err := src.AppendAuditEntry(&AuditEntry{Uid: "uid", Action: "create"}); err != nil {
+		t.Fatalf("append audit entry failed, %v", err)
+	}
+	for _, statement := range []string{`UPDATE audit_log SET status = "Destroyed"`, `DELETE FROM audit_log`} {
+		if _, err := src.DB.Exec(statement); err == nil {
+			t.Errorf("expected %s refused", statement)
+		}
+	}
+}
+
+func TestFileSource_AppendAuditEntry_concurrently(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "chaosblade.jsonl")
+	sources := make([]*FileSource, 2)
+	for i := range sources {
+		src, err := NewFileSource(dataFile)
+		if err != nil {
+			t.Fatalf("open data file failed, %v", err)
+		}
+		defer src.Close()
+		sources[i] = src
+	}
+	var wg sync.WaitGroup
+	for _, src := range sources {
+		wg.Add(1)
+		go func(src *FileSource) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				if err := src.AppendAuditEntry(&AuditEntry{Uid: "uid", Action: "create"}); err != nil {
+					t.Errorf("append audit entry failed, %v", err)
+				}
+			}
+		}(src)
+	}
+	wg.Wait()
+	entries, err := sources[0].QueryAuditEntries()
+	if err != nil {
+		t.Fatalf("query audit entries failed, %v", err)
+	}
+	if len(entries) != 20 {
+		t.Errorf("unexpected entries count: %d", len(entries))
+	}
+	if err := VerifyAuditEntries(entries); err != nil {
+		t.Errorf("verify the audit entries failed, %v", err)
+	}
+}
@@ -0,0 +1,762 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// backends are the storage backends which must pass the conformance tests
+var backends = map[string]func(t *testing.T) SourceI{
+	SQLiteScheme: func(t *testing.T) SourceI {
+		return newTestSource(t)
+	},
+	MemoryScheme: func(t *testing.T) SourceI {
+		return NewMemorySource()
+	},
+	FileScheme: func(t *testing.T) SourceI {
+		src, err := NewFileSource(filepath.Join(t.TempDir(), "chaosblade.jsonl"))
+		if err != nil {
+			t.Fatalf("open data file failed, %v", err)
+		}
+		t.Cleanup(func() { src.Close() })
+		return src
+	},
+}
+
+func TestSourceConformance(t *testing.T) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, src SourceI)
+	}{
+		{"experiment", testExperimentCRUD},
+		{"update experiment status", testUpdateExperimentStatus},
+		{"query experiments", testQueryExperimentModels},
+		{"query experiments by command", testQueryExperimentModelsByCommand},
+		{"query experiments by flags", testQueryExperimentModelsByFlags},
+		{"probes", testExperimentProbes},
+		{"deadline", testExperimentDeadline},
+		{"preparation", testPreparationCRUD},
+		{"prune", testPrune},
+		{"audit", testAuditJournal},
+		{"event outbox", testEventOutbox},
+		{"lifecycle events", testLifecycleEvents},
+		{"journal atomicity", testJournalAtomicity},
+	}
+	for scheme, newSource := range backends {
+		for _, tt := range tests {
+			t.Run(scheme+"/"+tt.name, func(t *testing.T) {
+				tt.test(t, newSource(t))
+			})
+		}
+	}
+}
+
+func insertExperiments(t *testing.T, src SourceI, models ...*ExperimentModel) {
+	t.Helper()
+	for _, model := range models {
+		if err := src.InsertExperimentModel(model); err != nil {
+			t.Fatalf("insert %s experiment failed, %v", model.Uid, err)
+		}
+	}
+}
+
+func uidsOf(models []*ExperimentModel) []string {
+	uids := make([]string, 0, len(models))
+	for _, model := range models {
+		uids = append(uids, model.Uid)
+	}
+	return uids
+}
+
+func testExperimentCRUD(t *testing.T, src SourceI) {
+	model := &ExperimentModel{
+		Uid: "e1", Command: "cpu", SubCommand: "fullload", Flag: " --cpu-percent=50", Status: "Created",
+		CreateTime: "2025-01-01T00:00:00Z", UpdateTime: "2025-01-01T00:00:00Z",
+		GroupUid: "g1", Labels: map[string]string{"team": "sre"},
+	}
+	insertExperiments(t, src, model)
+	if err := src.InsertExperimentModel(&ExperimentModel{Uid: "e1", Command: "cpu"}); err == nil {
+		t.Errorf("expect error when inserting the duplicated uid")
+	}
+	got, err := src.QueryExperimentModelByUid("e1")
+	if err != nil || got == nil {
+		t.Fatalf("query experiment failed, %v, %v", got, err)
+	}
+	expect := *model
+	expect.Flags = map[string]string{"cpu-percent": "50"}
+	if !reflect.DeepEqual(got, &expect) {
+		t.Errorf("unexpected experiment %+v, expect %+v", got, expect)
+	}
+	if got, err := src.QueryExperimentModelByUid("unknown"); err != nil || got != nil {
+		t.Errorf("expect nil for the unknown uid, got %v, %v", got, err)
+	}
+
+	if err := src.UpdateExperimentModelByUid("e1", "Error", "failed"); err != nil {
+		t.Fatalf("update experiment failed, %v", err)
+	}
+	if err := src.UpdateExperimentModelByUid("unknown", "Error", "failed"); err != nil {
+		t.Errorf("update unknown experiment failed, %v", err)
+	}
+	got, _ = src.QueryExperimentModelByUid("e1")
+	if got.Status != "Error" || got.Error != "failed" || got.UpdateTime == model.UpdateTime {
+		t.Errorf("unexpected updated experiment %+v", got)
+	}
+
+	insertExperiments(t, src, &ExperimentModel{Uid: "e2", Command: "mem", Status: "Success", GroupUid: "g1"},
+		&ExperimentModel{Uid: "e3", Command: "disk", Status: "Success", GroupUid: "g2"})
+	models, err := src.QueryExperimentModelsByGroup("g1")
+	if err != nil {
+		t.Fatalf("query experiments by group failed, %v", err)
+	}
+	if uids := uidsOf(models); !reflect.DeepEqual(uids, []string{"e1", "e2"}) {
+		t.Errorf("unexpected group experiments %v", uids)
+	}
+
+	if err := src.DeleteExperimentModelByUid("e1"); err != nil {
+		t.Fatalf("delete experiment failed, %v", err)
+	}
+	if got, _ := src.QueryExperimentModelByUid("e1"); got != nil {
+		t.Errorf("the deleted experiment is found, %+v", got)
+	}
+}
+
+func testUpdateExperimentStatus(t *testing.T, src SourceI) {
+	insertExperiments(t, src, &ExperimentModel{Uid: "e1", Command: "cpu", Status: "Created"})
+	tests := []struct {
+		name     string
+		uid      string
+		expected []string
+		status   string
+		conflict string
+	}{
+		{"created to success", "e1", []string{"Created"}, "Success", ""},
+		{"success to success", "e1", []string{"Created"}, "Success", "Success"},
+		{"success to destroyed", "e1", []string{"Created", "Success"}, "Destroyed", ""},
+		{"any status", "e1", nil, "Error", ""},
+		{"unknown experiment", "unknown", []string{"Created"}, "Success", "-"},
+	}
+	for _, tt := range tests {
+		err := src.UpdateExperimentStatusByUid(tt.uid, tt.expected, tt.status, tt.name)
+		if tt.conflict == "" {
+			if err != nil {
+				t.Fatalf("%s: update status failed, %v", tt.name, err)
+			}
+			if got, _ := src.QueryExperimentModelByUid(tt.uid); got.Status != tt.status || got.Error != tt.name {
+				t.Errorf("%s: unexpected experiment %+v", tt.name, got)
+			}
+			continue
+		}
+		conflict, ok := err.(*StatusConflictError)
+		if !ok {
+			t.Fatalf("%s: expect status conflict error, got %v", tt.name, err)
+		}
+		if (tt.conflict == "-" && conflict.Status != "") || (tt.conflict != "-" && conflict.Status != tt.conflict) {
+			t.Errorf("%s: unexpected conflict status %s", tt.name, conflict.Status)
+		}
+	}
+}
+
+func testQueryExperimentModels(t *testing.T, src SourceI) {
+	insertExperiments(t, src,
+		&ExperimentModel{Uid: "e1", Command: "cpu", SubCommand: "fullload", Flag: " --cpu-percent=50", Status: "Success"},
+		&ExperimentModel{Uid: "e2", Command: "cpu", SubCommand: "fullload", Flag: " --cpu-percent=80", Status: "Destroyed"},
+		&ExperimentModel{Uid: "e3", Command: "mem", SubCommand: "load", Flag: " --mem-percent=50", Status: "Success"},
+		&ExperimentModel{Uid: "e4", Command: "cpu", SubCommand: "fullload", Flag: " --CPU-percent=90", Status: "Success"},
+	)
+	tests := []struct {
+		name                              string
+		target, action, flag, status, lim string
+		asc                               bool
+		expect                            []string
+	}{
+		{"all asc", "", "", "", "", "", true, []string{"e1", "e2", "e3", "e4"}},
+		{"all desc", "", "", "", "", "", false, []string{"e4", "e3", "e2", "e1"}},
+		{"target and action", "cpu", "fullload", "", "", "", true, []string{"e1", "e2", "e4"}},
+		{"lower case status", "", "", "", "success", "", true, []string{"e1", "e3", "e4"}},
+		{"case-insensitive flag", "", "", "cpu-percent", "", "", true, []string{"e1", "e2", "e4"}},
+		{"count", "", "", "", "", "2", false, []string{"e4", "e3"}},
+		{"offset and count", "", "", "", "", "1,2", true, []string{"e2", "e3"}},
+		{"no matched", "disk", "", "", "", "", true, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			models, err := src.QueryExperimentModels(tt.target, tt.action, tt.flag, tt.status, tt.lim, tt.asc)
+			if err != nil {
+				t.Fatalf("query experiments failed, %v", err)
+			}
+			if uids := uidsOf(models); !reflect.DeepEqual(uids, tt.expect) {
+				t.Errorf("unexpected experiments %v, expect %v", uids, tt.expect)
+			}
+		})
+	}
+}
+
+func testQueryExperimentModelsByCommand(t *testing.T, src SourceI) {
+	insertExperiments(t, src,
+		&ExperimentModel{Uid: "e1", Command: "network", SubCommand: "delay", Flag: " --time=3000 --interface=eth0", Status: "Success"},
+		&ExperimentModel{Uid: "e2", Command: "network", SubCommand: "delay", Flag: " --time=1000 --interface=eth0", Status: "Success"},
+		&ExperimentModel{Uid: "e3", Command: "network", SubCommand: "loss", Flag: " --percent=50 --interface=eth0", Status: "Success"},
+	)
+	tests := []struct {
+		name   string
+		flags  map[string]string
+		expect []string
+	}{
+		{"no flags", nil, []string{"e1", "e2"}},
+		{"matched flag", map[string]string{"time": "3000"}, []string{"e1"}},
+		{"empty flag value is ignored", map[string]string{"time": "", "interface": "eth0"}, []string{"e1", "e2"}},
+		{"no matched", map[string]string{"interface": "eth1"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			models, err := src.QueryExperimentModelsByCommand("network", "delay", tt.flags)
+			if err != nil {
+				t.Fatalf("query experiments failed, %v", err)
+			}
+			if uids := uidsOf(models); !reflect.DeepEqual(uids, tt.expect) {
+				t.Errorf("unexpected experiments %v, expect %v", uids, tt.expect)
+			}
+		})
+	}
+}
+
+func testQueryExperimentModelsByFlags(t *testing.T, src SourceI) {
+	insertExperiments(t, src,
+		&ExperimentModel{Uid: "e1", Command: "k8s", SubCommand: "pod-pod delete", Status: "Success",
+			Flag: " --names=pod-a --timeout=60", Flags: map[string]string{"names": "pod-a", "timeout": "60"}},
+		&ExperimentModel{Uid: "e2", Command: "k8s", SubCommand: "pod-pod delete", Status: "Destroyed",
+			Flag: " --names=pod-ab --timeout=60", Flags: map[string]string{"names": "pod-ab", "timeout": "60"}},
+		// the experiment without flags is skipped by the flag filters
+		&ExperimentModel{Uid: "e4", Command: "mem", SubCommand: "load", Status: "Success"},
+		// the flags are parsed from the inline flags
+		&ExperimentModel{Uid: "e3", Command: "cpu", SubCommand: "fullload", Status: "Success",
+			Flag: " --timeout=600 --cpu-list=0-3"},
+	)
+	tests := []struct {
+		name                   string
+		target, action, status string
+		flags                  map[string]string
+		expect                 []string
+	}{
+		{"exact value", "", "", "", map[string]string{"names": "pod-a"}, []string{"e1"}},
+		{"all flags", "", "", "", map[string]string{"names": "pod-ab", "timeout": "60"}, []string{"e2"}},
+		{"not prefix", "", "", "", map[string]string{"timeout": "6"}, []string{}},
+		{"parsed flags", "", "", "", map[string]string{"cpu-list": "0-3"}, []string{"e3"}},
+		{"with status", "k8s", "pod-pod delete", "success", map[string]string{"timeout": "60"}, []string{"e1"}},
+		{"no flags", "k8s", "", "", nil, []string{"e1", "e2"}},
+		{"after flag-less", "", "", "success", map[string]string{"timeout": "600"}, []string{"e3"}},
+		{"flag-less command", "mem", "load", "", map[string]string{"timeout": "60"}, []string{}},
+		{"flag-less without filter", "mem", "load", "", nil, []string{"e4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			models, err := src.QueryExperimentModelsByFlags(tt.target, tt.action, tt.status, tt.flags, "", true)
+			if err != nil {
+				t.Fatalf("query experiments failed, %v", err)
+			}
+			if uids := uidsOf(models); !reflect.DeepEqual(uids, tt.expect) {
+				t.Errorf("unexpected experiments %v, expect %v", uids, tt.expect)
+			}
+		})
+	}
+}
+
+func testExperimentProbes(t *testing.T, src SourceI) {
+	probes := []*Probe{{
+		Definition: "http://127.0.0.1/health expect=200",
+		Before:     &ProbeResult{Success: true, Message: "status 200", Time: "2025-01-01T00:00:00Z"},
+	}}
+	insertExperiments(t, src, &ExperimentModel{Uid: "e1", Command: "cpu", Status: "Success", Probes: probes})
+	got, _ := src.QueryExperimentModelByUid("e1")
+	if got == nil || !reflect.DeepEqual(got.Probes, probes) {
+		t.Fatalf("unexpected probes of the inserted experiment %+v", got)
+	}
+	probes[0].During = &ProbeResult{Success: false, Message: "status 503", Time: "2025-01-01T00:01:00Z"}
+	if err := src.UpdateExperimentProbesByUid("e1", probes); err != nil {
+		t.Fatalf("update probes failed, %v", err)
+	}
+	if err := src.UpdateExperimentProbesByUid("unknown", probes); err != nil {
+		t.Errorf("update probes of unknown experiment failed, %v", err)
+	}
+	got, _ = src.QueryExperimentModelByUid("e1")
+	if !reflect.DeepEqual(got.Probes, probes) || got.Status != "Success" {
+		t.Errorf("unexpected updated experiment %+v", got)
+	}
+}
+
+func testExperimentDeadline(t *testing.T, src SourceI) {
+	now := time.Now()
+	insertExperiments(t, src,
+		&ExperimentModel{Uid: "late", Command: "cpu", Status: "Success"},
+		&ExperimentModel{Uid: "early", Command: "cpu", Status: "Created"},
+		&ExperimentModel{Uid: "future", Command: "cpu", Status: "Success"},
+		&ExperimentModel{Uid: "destroyed", Command: "cpu", Status: "Destroyed"},
+		&ExperimentModel{Uid: "deleted", Command: "cpu", Status: "Success"},
+	)
+	deadlines := map[string]time.Time{
+		"late":      now.Add(-time.Minute),
+		"early":     now.Add(-time.Hour),
+		"future":    now.Add(time.Hour),
+		"destroyed": now.Add(-time.Hour),
+		"deleted":   now.Add(-time.Hour),
+	}
+	for uid, deadline := range deadlines {
+		if err := src.InsertExperimentDeadline(uid, deadline); err != nil {
+			t.Fatalf("insert deadline failed, %v", err)
+		}
+	}
+	// the deadline is replaced
+	if err := src.InsertExperimentDeadline("future", now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("replace deadline failed, %v", err)
+	}
+	if err := src.DeleteExperimentModelByUid("deleted"); err != nil {
+		t.Fatalf("delete experiment failed, %v", err)
+	}
+	models, err := src.QueryOverdueExperimentModels(now)
+	if err != nil {
+		t.Fatalf("query overdue experiments failed, %v", err)
+	}
+	if uids := uidsOf(models); !reflect.DeepEqual(uids, []string{"early", "late"}) {
+		t.Errorf("unexpected overdue experiments %v", uids)
+	}
+	// the failed destroying is retried after the next retry time
+	if err := src.DeferExperimentDeadline("late", now.Add(time.Minute), "destroy failed"); err != nil {
+		t.Fatalf("defer deadline failed, %v", err)
+	}
+	if err := src.DeferExperimentDeadline("late", now.Add(2*time.Minute), "destroy failed again"); err != nil {
+		t.Fatalf("defer deadline failed, %v", err)
+	}
+	deadline, err := src.QueryExperimentDeadline("late")
+	if err != nil {
+		t.Fatalf("query deadline failed, %v", err)
+	}
+	expected := &ExperimentDeadline{Uid: "late", Deadline: now.Add(-time.Minute).Unix(), Attempts: 2,
+		LastError: "destroy failed again", NextRetry: now.Add(2 * time.Minute).Unix()}
+	if !reflect.DeepEqual(deadline, expected) {
+		t.Errorf("unexpected deadline %+v", deadline)
+	}
+	if deadline, _ := src.QueryExperimentDeadline("deleted"); deadline != nil {
+		t.Errorf("unexpected deadline of the deleted experiment %+v", deadline)
+	}
+	models, _ = src.QueryOverdueExperimentModels(now)
+	if uids := uidsOf(models); !reflect.DeepEqual(uids, []string{"early"}) {
+		t.Errorf("unexpected overdue experiments %v", uids)
+	}
+	if err := src.DeleteExperimentDeadline("early"); err != nil {
+		t.Fatalf("delete deadline failed, %v", err)
+	}
+	models, _ = src.QueryOverdueExperimentModels(now.Add(3 * time.Hour))
+	if uids := uidsOf(models); !reflect.DeepEqual(uids, []string{"late", "future"}) {
+		t.Errorf("unexpected overdue experiments %v", uids)
+	}
+}
+
+func testPreparationCRUD(t *testing.T, src SourceI) {
+	records := []*PreparationRecord{
+		{Uid: "p1", ProgramType: "jvm", Process: "app", Pid: "100", Status: "Running"},
+		{Uid: "p2", ProgramType: "jvm", Process: "app", Pid: "200", Status: "Running"},
+		{Uid: "p3", ProgramType: "jvm", Process: "other", Pid: "300", Status: "Revoked"},
+		{Uid: "p4", ProgramType: "cplus", Process: "app", Port: "8703", Status: "Running"},
+	}
+	for _, record := range records {
+		if err := src.InsertPreparationRecord(record); err != nil {
+			t.Fatalf("insert preparation failed, %v", err)
+		}
+	}
+	if err := src.InsertPreparationRecord(&PreparationRecord{Uid: "p1", ProgramType: "jvm"}); err == nil {
+		t.Errorf("expect error when inserting the duplicated uid")
+	}
+	got, err := src.QueryPreparationByUid("p4")
+	if err != nil || !reflect.DeepEqual(got, records[3]) {
+		t.Errorf("unexpected preparation %+v, %v", got, err)
+	}
+	if got, err := src.QueryPreparationByUid("unknown"); err != nil || got != nil {
+		t.Errorf("expect nil for the unknown uid, got %v, %v", got, err)
+	}
+
+	running := []struct {
+		name, process, pid, expect string
+	}{
+		{"by type", "", "", "p1"},
+		{"by pid", "", "200", "p2"},
+		{"by process and pid", "app", "200", "p2"},
+		{"not running", "other", "", ""},
+	}
+	for _, tt := range running {
+		got, err := src.QueryRunningPreByTypeAndProcess("jvm", tt.process, tt.pid)
+		if err != nil {
+			t.Fatalf("%s: query running preparation failed, %v", tt.name, err)
+		}
+		if (got == nil && tt.expect != "") || (got != nil && got.Uid != tt.expect) {
+			t.Errorf("%s: unexpected preparation %+v, expect %s", tt.name, got, tt.expect)
+		}
+	}
+
+	if err := src.UpdatePreparationRecordByUid("p1", "Revoked", "stopped"); err != nil {
+		t.Fatalf("update preparation failed, %v", err)
+	}
+	if err := src.UpdatePreparationPortByUid("p1", "9526"); err != nil {
+		t.Fatalf("update port failed, %v", err)
+	}
+	if err := src.UpdatePreparationPidByUid("p1", "101"); err != nil {
+		t.Fatalf("update pid failed, %v", err)
+	}
+	got, _ = src.QueryPreparationByUid("p1")
+	if got.Status != "Revoked" || got.Error != "stopped" || got.Port != "9526" || got.Pid != "101" || got.UpdateTime == "" {
+		t.Errorf("unexpected updated preparation %+v", got)
+	}
+
+	query := []struct {
+		name, target, status, limit string
+		asc                         bool
+		expect                      []string
+	}{
+		{"all desc", "", "", "", false, []string{"p4", "p3", "p2", "p1"}},
+		{"by type and status", "jvm", "revoked", "", true, []string{"p1", "p3"}},
+		{"offset and count", "", "", "1,2", true, []string{"p2", "p3"}},
+	}
+	for _, tt := range query {
+		got, err := src.QueryPreparationRecords(tt.target, tt.status, "", "", tt.limit, tt.asc)
+		if err != nil {
+			t.Fatalf("%s: query preparations failed, %v", tt.name, err)
+		}
+		uids := make([]string, 0, len(got))
+		for _, record := range got {
+			uids = append(uids, record.Uid)
+		}
+		if !reflect.DeepEqual(uids, tt.expect) {
+			t.Errorf("%s: unexpected preparations %v, expect %v", tt.name, uids, tt.expect)
+		}
+	}
+}
+
+func testPrune(t *testing.T, src SourceI) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour).Format(time.RFC3339Nano)
+	recent := now.Add(-time.Hour).Format(time.RFC3339Nano)
+	insertExperiments(t, src,
+		&ExperimentModel{Uid: "old-destroyed", Command: "cpu", Status: "Destroyed", CreateTime: old, UpdateTime: old},
+		&ExperimentModel{Uid: "old-success", Command: "cpu", Status: "Success", CreateTime: old, UpdateTime: old},
+		&ExperimentModel{Uid: "recent-destroyed", Command: "cpu", Status: "Destroyed", CreateTime: old, UpdateTime: recent},
+		&ExperimentModel{Uid: "old-error", Command: "cpu", Status: "Error", CreateTime: old},
+	)
+	for _, record := range []*PreparationRecord{
+		{Uid: "old-revoked", ProgramType: "jvm", Status: "Revoked", CreateTime: old, UpdateTime: old},
+		{Uid: "old-running", ProgramType: "jvm", Status: "Running", CreateTime: old, UpdateTime: old},
+	} {
+		if err := src.InsertPreparationRecord(record); err != nil {
+			t.Fatalf("insert preparation failed, %v", err)
+		}
+	}
+	if _, err := src.PruneExperimentModels(now, []string{"Success"}); err == nil {
+		t.Errorf("expect error when pruning the Success experiments")
+	}
+	result, err := Prune(src, &RetentionPolicy{OlderThan: 24 * time.Hour, Statuses: DefaultPruneStatuses}, now)
+	if err != nil {
+		t.Fatalf("prune failed, %v", err)
+	}
+	if !reflect.DeepEqual(result.Experiments, []string{"old-destroyed", "old-error"}) ||
+		!reflect.DeepEqual(result.Preparations, []string{"old-revoked"}) {
+		t.Errorf("unexpected prune result %+v", result)
+	}
+	models, _ := src.QueryExperimentModels("", "", "", "", "", true)
+	if uids := uidsOf(models); !reflect.DeepEqual(uids, []string{"old-success", "recent-destroyed"}) {
+		t.Errorf("unexpected experiments after pruning %v", uids)
+	}
+	if record, _ := src.QueryPreparationByUid("old-running"); record == nil {
+		t.Errorf("the running preparation is pruned")
+	}
+}
+
+func testAuditJournal(t *testing.T, src SourceI) {
+	audited := NewAuditedSource(src)
+	insertExperiments(t, audited, &ExperimentModel{Uid: "a1", Command: "cpu", SubCommand: "fullload", Status: "Created"})
+	if err := audited.UpdateExperimentStatusByUid("a1", []string{"Created"}, "Success", ""); err != nil {
+		t.Fatalf("update status failed, %v", err)
+	}
+	// the conflicting transition is not recorded
+	if err := audited.UpdateExperimentStatusByUid("a1", []string{"Created"}, "Error", ""); err == nil {
+		t.Fatalf("expected the status conflict")
+	}
+	if err := audited.UpdateExperimentStatusByUid("a1", nil, "Destroyed", ""); err != nil {
+		t.Fatalf("update status failed, %v", err)
+	}
+	if err := audited.InsertPreparationRecord(&PreparationRecord{Uid: "p1", ProgramType: "jvm", Status: "Created"}); err != nil {
+		t.Fatalf("insert preparation failed, %v", err)
+	}
+	if err := audited.UpdatePreparationRecordByUid("p1", "Revoked", ""); err != nil {
+		t.Fatalf("update preparation failed, %v", err)
+	}
+	entries, err := src.QueryAuditEntries()
+	if err != nil {
+		t.Fatalf("query audit entries failed, %v", err)
+	}
+	transitions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		transitions = append(transitions, entry.Uid+":"+entry.Action+":"+entry.PreviousStatus+">"+entry.Status)
+		if entry.User == "" || entry.CommandLine == "" || entry.Source != AuditSourceCLI {
+			t.Errorf("unexpected user, command line or source of the entry %+v", entry)
+		}
+	}
+	expected := []string{
+		"a1:create:>Created", "a1:update:Created>Success", "a1:destroy:Success>Destroyed",
+		"p1:prepare:>Created", "p1:revoke:Created>Revoked",
+	}
+	if !reflect.DeepEqual(transitions, expected) {
+		t.Errorf("unexpected transitions: %v, expected: %v", transitions, expected)
+	}
+	if err := VerifyAuditEntries(entries); err != nil {
+		t.Errorf("verify the audit entries failed, %v", err)
+	}
+}
+
+func testEventOutbox(t *testing.T, src SourceI) {
+	deliveries := []*EventDelivery{
+		{EventId: "e1", EventType: EventExperimentCreated, Uid: "a1", Sink: "ops", Payload: "{}", Status: DeliveryPending, NextAttempt: 100},
+		{EventId: "e1", EventType: EventExperimentCreated, Uid: "a1", Sink: "audit", Payload: "{}", Status: DeliveryPending, NextAttempt: 200},
+	}
+	if err := src.InsertEventDeliveries(deliveries); err != nil {
+		t.Fatalf("insert deliveries failed, %v", err)
+	}
+	due, err := src.QueryEventDeliveries(DeliveryPending, 150)
+	if err != nil {
+		t.Fatalf("query deliveries failed, %v", err)
+	}
+	if len(due) != 1 || due[0].Sink != "ops" || due[0].Id == 0 {
+		t.Fatalf("unexpected due deliveries %+v", due)
+	}
+	id := due[0].Id
+	if claimed, err := src.ClaimEventDelivery(id, 100, 160); err != nil || !claimed {
+		t.Fatalf("claim delivery failed, %t, %v", claimed, err)
+	}
+	// the delivery claimed by another process is not claimed again
+	if claimed, err := src.ClaimEventDelivery(id, 100, 170); err != nil || claimed {
+		t.Fatalf("unexpected claim, %t, %v", claimed, err)
+	}
+	if err := src.UpdateEventDelivery(id, DeliveryFailed, 3, 0, "connection refused"); err != nil {
+		t.Fatalf("update delivery failed, %v", err)
+	}
+	failed, err := src.QueryEventDeliveries(DeliveryFailed, 0)
+	if err != nil {
+		t.Fatalf("query deliveries failed, %v", err)
+	}
+	if len(failed) != 1 || failed[0].Id != id || failed[0].Attempts != 3 || failed[0].Error != "connection refused" {
+		t.Errorf("unexpected failed deliveries %+v", failed)
+	}
+	if claimed, _ := src.ClaimEventDelivery(id, 0, 170); claimed {
+		t.Errorf("the failed delivery is claimed")
+	}
+	all, err := src.QueryEventDeliveries("", 0)
+	if err != nil {
+		t.Fatalf("query deliveries failed, %v", err)
+	}
+	if len(all) != 2 || all[0].Id != id || all[1].Sink != "audit" {
+		t.Errorf("unexpected deliveries %+v", all)
+	}
+}
+
+// testEventRouter routes all events to the ops sink and records the notified deliveries
+type testEventRouter struct {
+	notified []*EventDelivery
+}
+
+func (r *testEventRouter) Route(event *LifecycleEvent) []string {
+	return []string{"ops"}
+}
+
+func (r *testEventRouter) Notify(deliveries []*EventDelivery) {
+	r.notified = append(r.notified, deliveries...)
+}
+
+func testLifecycleEvents(t *testing.T, src SourceI) {
+	router := &testEventRouter{}
+	SetEventRouter(router)
+	defer SetEventRouter(nil)
+	audited := NewAuditedSource(src)
+	insertExperiments(t, audited, &ExperimentModel{Uid: "a1", Command: "cpu", SubCommand: "fullload", Status: "Created"})
+	if err := audited.UpdateExperimentStatusByUid("a1", []string{"Created"}, "Success", ""); err != nil {
+		t.Fatalf("update status failed, %v", err)
+	}
+	if err := audited.UpdateExperimentModelByUid("a1", "Destroyed", ""); err != nil {
+		t.Fatalf("update status failed, %v", err)
+	}
+	if err := audited.InsertPreparationRecord(&PreparationRecord{Uid: "p1", ProgramType: "jvm", Status: "Created"}); err != nil {
+		t.Fatalf("insert preparation failed, %v", err)
+	}
+	for _, status := range []string{"Running", "Running", "Revoked"} {
+		if err := audited.UpdatePreparationRecordByUid("p1", status, ""); err != nil {
+			t.Fatalf("update preparation failed, %v", err)
+		}
+	}
+	deliveries, err := src.QueryEventDeliveries(DeliveryPending, 0)
+	if err != nil {
+		t.Fatalf("query deliveries failed, %v", err)
+	}
+	types := make([]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		types = append(types, delivery.EventType)
+		event := &LifecycleEvent{}
+		if err := json.Unmarshal([]byte(delivery.Payload), event); err != nil || event.Id != delivery.EventId ||
+			event.Uid != delivery.Uid || (event.Experiment == nil && event.Preparation == nil) {
+			t.Errorf("unexpected payload %s, %v", delivery.Payload, err)
+		}
+	}
+	// the unchanged status is not an event
+	expected := []string{
+		EventExperimentCreated, EventExperimentSucceeded, EventExperimentDestroyed,
+		EventPreparationAttached, EventPreparationRevoked,
+	}
+	if !reflect.DeepEqual(types, expected) || len(router.notified) != len(expected) {
+		t.Errorf("unexpected events: %v, notified %d, expected: %v", types, len(router.notified), expected)
+	}
+}
+
+func testJournalAtomicity(t *testing.T, src SourceI) {
+	journaled, ok := src.(journaledSource)
+	if !ok {
+		t.Fatalf("the source is not journaled")
+	}
+	insertExperiments(t, src, &ExperimentModel{Uid: "a1", Command: "cpu", SubCommand: "fullload", Status: "Success",
+		UpdateTime: time.Now().Add(-time.Hour).Format(time.RFC3339Nano)})
+	failed := func(uid, previous string, experiment *ExperimentModel, preparation *PreparationRecord) (
+		*AuditEntry, []*EventDelivery, error,
+	) {
+		return nil, nil, errors.New("journal failed")
+	}
+	// the transition is not applied if the journal can not be written
+	err := journaled.applyTransition(&transition{kind: AuditKindExperiment, uid: "a1", status: "Destroyed",
+		expected: []string{"Success"}, journal: failed})
+	if err == nil {
+		t.Fatalf("expected the journal error")
+	}
+	if model, err := src.QueryExperimentModelByUid("a1"); err != nil || model == nil || model.Status != "Success" {
+		t.Errorf("unexpected experiment %+v after the failed journal, %v", model, err)
+	}
+	if _, err := journaled.pruneWithJournal(AuditKindExperiment, time.Now(), []string{"Success"}, failed); err == nil {
+		t.Fatalf("expected the journal error")
+	}
+	if model, err := src.QueryExperimentModelByUid("a1"); err != nil || model == nil {
+		t.Errorf("the experiment is pruned without the journal, %v", err)
+	}
+	// the transition is written with its audit entry and event deliveries
+	router := &testEventRouter{}
+	SetEventRouter(router)
+	defer SetEventRouter(nil)
+	audited := NewAuditedSource(src)
+	if err := audited.UpdateExperimentStatusByUid("a1", []string{"Success"}, "Destroyed", ""); err != nil {
+		t.Fatalf("update status failed, %v", err)
+	}
+	// the missing record is not changed and not journaled
+	if err := audited.UpdateExperimentStatusByUid("a2", nil, "Destroyed", ""); err == nil {
+		t.Errorf("expected the status conflict of the missing experiment")
+	}
+	if err := audited.UpdateExperimentModelByUid("a2", "Destroyed", ""); err != nil {
+		t.Errorf("update the missing experiment failed, %v", err)
+	}
+	entries, err := src.QueryAuditEntries()
+	if err != nil || len(entries) != 1 || entries[0].Status != "Destroyed" || entries[0].PreviousStatus != "Success" {
+		t.Fatalf("unexpected audit entries %+v, %v", entries, err)
+	}
+	deliveries, err := src.QueryEventDeliveries(DeliveryPending, 0)
+	if err != nil || len(deliveries) != 1 || deliveries[0].EventType != EventExperimentDestroyed {
+		t.Fatalf("unexpected deliveries %+v, %v", deliveries, err)
+	}
+	if len(router.notified) != 1 || router.notified[0].Id != deliveries[0].Id {
+		t.Errorf("unexpected notified deliveries %+v", router.notified)
+	}
+	uids, err := audited.PruneExperimentModels(time.Now().Add(time.Minute), []string{"Destroyed"})
+	if err != nil || !reflect.DeepEqual(uids, []string{"a1"}) {
+		t.Fatalf("unexpected pruned uids %v, %v", uids, err)
+	}
+	entries, err = src.QueryAuditEntries()
+	if err != nil || len(entries) != 2 || entries[1].Action != "prune" {
+		t.Errorf("unexpected audit entries %+v, %v", entries, err)
+	}
+	if err := VerifyAuditEntries(entries); err != nil {
+		t.Errorf("verify the audit entries failed, %v", err)
+	}
+}
+
+func TestFileSource_reopen(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "chaosblade.jsonl")
+	writer, err := NewFileSource(dataFile)
+	if err != nil {
+		t.Fatalf("open data file failed, %v", err)
+	}
+	defer writer.Close()
+	reader, err := NewFileSource(dataFile)
+	if err != nil {
+		t.Fatalf("open data file failed, %v", err)
+	}
+	defer reader.Close()
+
+	insertExperiments(t, writer, &ExperimentModel{Uid: "e1", Command: "cpu", Status: "Success"})
+	if err := writer.UpdateExperimentModelByUid("e1", "Destroyed", ""); err != nil {
+		t.Fatalf("update experiment failed, %v", err)
+	}
+	// the changes of other processes are replayed, the incomplete line is skipped
+	file, err := os.OpenFile(dataFile, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("open data file failed, %v", err)
+	}
+	file.WriteString(`{"op":"insertExperiment","uid":"e2"`)
+	file.Close()
+
+	got, err := reader.QueryExperimentModels("", "", "", "", "", true)
+	if err != nil {
+		t.Fatalf("query experiments failed, %v", err)
+	}
+	if len(got) != 1 || got[0].Uid != "e1" || got[0].Status != "Destroyed" {
+		t.Errorf("unexpected experiments %+v", got)
+	}
+}
+
+func TestOpenSource(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		dataSource string
+		expect     interface{}
+		wantErr    bool
+	}{
+		{"memory://", &MemorySource{}, false},
+		{"file://" + filepath.Join(dir, "chaosblade.jsonl"), &FileSource{}, false},
+		{"sqlite://" + filepath.Join(dir, "chaosblade.dat"), &Source{}, false},
+		{"mysql://localhost", nil, true},
+		{"chaosblade.dat", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dataSource, func(t *testing.T) {
+			src, err := OpenSource(tt.dataSource)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OpenSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if reflect.TypeOf(src) != reflect.TypeOf(tt.expect) {
+				t.Errorf("unexpected source type %T", src)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DataSourceEnv selects the storage backend by the url, for example:
+//
+//	sqlite:///opt/chaosblade/chaosblade.dat
+//	file:///var/run/chaosblade/chaosblade.jsonl
+//	memory://
+const DataSourceEnv = "CHAOSBLADE_DATASOURCE"
+
+const (
+	SQLiteScheme = "sqlite"
+	FileScheme   = "file"
+	MemoryScheme = "memory"
+)
+
+// Opener opens the source by the path of the data source url
+type Opener func(path string) (SourceI, error)
+
+var openers = map[string]Opener{
+	SQLiteScheme: openSQLiteSource,
+	FileScheme:   openFileSource,
+	MemoryScheme: openMemorySource,
+}
+
+// RegisterOpener registers the storage backend of the scheme, the registered one is replaced
+func RegisterOpener(scheme string, opener Opener) {
+	openers[scheme] = opener
+}
+
+// ParseDataSource splits the data source url into the scheme and the path.
+// The empty url means the default sqlite data file.
+func ParseDataSource(dataSource string) (scheme, path string, err error) {
+	if dataSource == "" {
+		return SQLiteScheme, "", nil
+	}
+	idx := strings.Index(dataSource, "://")
+	if idx <= 0 {
+		return "", "", fmt.Errorf("illegal %s value %q, the format is scheme://path", DataSourceEnv, dataSource)
+	}
+	scheme, path = dataSource[:idx], dataSource[idx+len("://"):]
+	if _, ok := openers[scheme]; !ok {
+		return "", "", fmt.Errorf("unsupported data source scheme %q, supported: %s", scheme,
+			strings.Join(supportedSchemes(), ", "))
+	}
+	return scheme, path, nil
+}
+
+// OpenSource opens the storage backend of the data source url
+func OpenSource(dataSource string) (SourceI, error) {
+	scheme, path, err := ParseDataSource(dataSource)
+	if err != nil {
+		return nil, err
+	}
+	return openers[scheme](path)
+}
+
+// SQLiteDataFile returns the sqlite data file selected by the CHAOSBLADE_DATASOURCE environment variable,
+// it returns error if another storage backend is selected
+func SQLiteDataFile() (string, error) {
+	scheme, path, err := ParseDataSource(os.Getenv(DataSourceEnv))
+	if err != nil {
+		return "", err
+	}
+	if scheme != SQLiteScheme {
+		return "", fmt.Errorf("the %s data source is not a sqlite data file", scheme)
+	}
+	if path == "" {
+		return GetDataFilePath(), nil
+	}
+	return path, nil
+}
+
+func supportedSchemes() []string {
+	schemes := make([]string, 0, len(openers))
+	for scheme := range openers {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
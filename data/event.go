@@ -0,0 +1,291 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// The types of the lifecycle events
+const (
+	EventExperimentCreated   = "experiment.created"
+	EventExperimentSucceeded = "experiment.succeeded"
+	EventExperimentFailed    = "experiment.failed"
+	EventExperimentDestroyed = "experiment.destroyed"
+	EventExperimentExpired   = "experiment.expired"
+	EventExperimentAborted   = "experiment.aborted"
+	EventPreparationAttached = "preparation.attached"
+	EventPreparationRevoked  = "preparation.revoked"
+	EventPreparationFailed   = "preparation.failed"
+)
+
+// The statuses of the event deliveries, the Failed deliveries are kept in the outbox until they are redelivered
+const (
+	DeliveryPending   = "Pending"
+	DeliveryDelivered = "Delivered"
+	DeliveryFailed    = "Failed"
+)
+
+// lifecycleEventTypes are the event types of the kinds and the new statuses
+var lifecycleEventTypes = map[string]map[string]string{
+	AuditKindExperiment: {
+		"Created":   EventExperimentCreated,
+		"Success":   EventExperimentSucceeded,
+		"Error":     EventExperimentFailed,
+		"Destroyed": EventExperimentDestroyed,
+		"Expired":   EventExperimentExpired,
+		"Aborted":   EventExperimentAborted,
+	},
+	AuditKindPreparation: {
+		"Running": EventPreparationAttached,
+		"Revoked": EventPreparationRevoked,
+		"Error":   EventPreparationFailed,
+	},
+}
+
+// LifecycleEvent is a status transition of an experiment or preparation, the record is the one after the transition
+type LifecycleEvent struct {
+	Id             string `json:"id"`
+	Type           string `json:"type"`
+	Time           string `json:"time"`
+	Uid            string `json:"uid"`
+	PreviousStatus string `json:"previousStatus,omitempty"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+	// Code is the code of the response which causes the transition, such as the failed executor response
+	Code        int32              `json:"code,omitempty"`
+	Source      string             `json:"source"`
+	Experiment  *ExperimentModel   `json:"experiment,omitempty"`
+	Preparation *PreparationRecord `json:"preparation,omitempty"`
+}
+
+// EventDelivery is the delivery of an event to a sink, which is stored in the outbox with the event payload,
+// so the event is not lost if the sink is unavailable
+type EventDelivery struct {
+	Id        int64  `json:"id"`
+	EventId   string `json:"eventId"`
+	EventType string `json:"eventType"`
+	Uid       string `json:"uid"`
+	Sink      string `json:"sink"`
+	Payload   string `json:"payload,omitempty"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+	// NextAttempt is the unix seconds of the next attempt of the Pending delivery
+	NextAttempt int64  `json:"nextAttempt"`
+	Error       string `json:"error,omitempty"`
+	CreateTime  string `json:"createTime"`
+	UpdateTime  string `json:"updateTime"`
+}
+
+// EventOutbox stores the deliveries of the lifecycle events until they are delivered
+type EventOutbox interface {
+	// InsertEventDeliveries stores the deliveries of an event together
+	InsertEventDeliveries(deliveries []*EventDelivery) error
+
+	// QueryEventDeliveries returns the deliveries of the status order by id, the empty status matches all.
+	// The positive dueBefore only matches the deliveries whose next attempt is not after it.
+	QueryEventDeliveries(status string, dueBefore int64) ([]*EventDelivery, error)
+
+	// ClaimEventDelivery postpones the next attempt of the Pending delivery to the lease if the next attempt
+	// is not changed, so the delivery is attempted by one process. It returns false if another process claims it.
+	ClaimEventDelivery(id, nextAttempt, lease int64) (bool, error)
+
+	// UpdateEventDelivery records the result of an attempt
+	UpdateEventDelivery(id int64, status string, attempts int, nextAttempt int64, errMsg string) error
+}
+
+// errEventDeliveryClaimed is returned if the delivery is claimed by another process
+var errEventDeliveryClaimed = errors.New("the event delivery is claimed by another process")
+
+// EventRouter selects the sinks of the lifecycle events
+type EventRouter interface {
+	// Route returns the names of the sinks which the event is delivered to
+	Route(event *LifecycleEvent) []string
+
+	// Notify is called after the deliveries of the event are stored in the outbox
+	Notify(deliveries []*EventDelivery)
+}
+
+var (
+	eventRouter     EventRouter
+	eventRouterLock sync.RWMutex
+	// eventCodes are the response codes of the next events of the uids
+	eventCodes sync.Map
+)
+
+// SetEventRouter sets the router of the lifecycle events published by the audited source,
+// no event is published without the router
+func SetEventRouter(router EventRouter) {
+	eventRouterLock.Lock()
+	defer eventRouterLock.Unlock()
+	eventRouter = router
+}
+
+// SetEventCode sets the response code of the next lifecycle event of the uid, it is set before the status is updated
+func SetEventCode(uid string, code int32) {
+	eventCodes.Store(uid, code)
+}
+
+func getEventRouter() EventRouter {
+	eventRouterLock.RLock()
+	defer eventRouterLock.RUnlock()
+	return eventRouter
+}
+
+// newEventId returns a random hex id
+func newEventId() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return now()
+	}
+	return hex.EncodeToString(bytes)
+}
+
+// newEventDeliveries returns the deliveries of the lifecycle event of the status transition to the sinks routed by
+// the router, the unchanged status is not an event, and no event is published without the router
+func newEventDeliveries(router EventRouter, kind, uid, previousStatus, status string, code int32,
+	experiment *ExperimentModel, preparation *PreparationRecord,
+) ([]*EventDelivery, error) {
+	eventType := lifecycleEventTypes[kind][status]
+	if router == nil || eventType == "" || previousStatus == status {
+		return nil, nil
+	}
+	event := &LifecycleEvent{
+		Id:             newEventId(),
+		Type:           eventType,
+		Time:           time.Now().Format(time.RFC3339Nano),
+		Uid:            uid,
+		PreviousStatus: previousStatus,
+		Status:         status,
+		Code:           code,
+		Source:         getAuditSource(),
+		Experiment:     experiment,
+		Preparation:    preparation,
+	}
+	if experiment != nil {
+		event.Error = experiment.Error
+	} else if preparation != nil {
+		event.Error = preparation.Error
+	}
+	sinks := router.Route(event)
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshal the %s event of %s failed, %v", eventType, uid, err)
+	}
+	createTime := now()
+	deliveries := make([]*EventDelivery, 0, len(sinks))
+	for _, sink := range sinks {
+		deliveries = append(deliveries, &EventDelivery{
+			EventId:     event.Id,
+			EventType:   eventType,
+			Uid:         uid,
+			Sink:        sink,
+			Payload:     string(payload),
+			Status:      DeliveryPending,
+			NextAttempt: time.Now().Unix(),
+			CreateTime:  createTime,
+			UpdateTime:  createTime,
+		})
+	}
+	return deliveries, nil
+}
+
+// InsertEventDeliveries inserts the deliveries in a transaction
+func (s *Source) InsertEventDeliveries(deliveries []*EventDelivery) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := insertEventDeliveries(tx, deliveries); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertEventDeliveries inserts the deliveries in the transaction and sets their ids
+func insertEventDeliveries(tx *sql.Tx, deliveries []*EventDelivery) error {
+	for _, delivery := range deliveries {
+		result, err := tx.Exec(`INSERT INTO event_outbox (event_id, event_type, uid, sink, payload, status, attempts,
+	next_attempt, error, create_time, update_time) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			delivery.EventId, delivery.EventType, delivery.Uid, delivery.Sink, delivery.Payload, delivery.Status,
+			delivery.Attempts, delivery.NextAttempt, delivery.Error, delivery.CreateTime, delivery.UpdateTime)
+		if err != nil {
+			return err
+		}
+		if delivery.Id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Source) QueryEventDeliveries(status string, dueBefore int64) ([]*EventDelivery, error) {
+	query := `SELECT id, event_id, event_type, uid, sink, payload, status, attempts, next_attempt, error, create_time,
+	update_time FROM event_outbox WHERE 1=1`
+	args := make([]interface{}, 0)
+	if status != "" {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	if dueBefore > 0 {
+		query += ` AND next_attempt <= ?`
+		args = append(args, dueBefore)
+	}
+	rows, err := s.DB.Query(query+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	deliveries := make([]*EventDelivery, 0)
+	for rows.Next() {
+		delivery := &EventDelivery{}
+		if err := rows.Scan(&delivery.Id, &delivery.EventId, &delivery.EventType, &delivery.Uid, &delivery.Sink,
+			&delivery.Payload, &delivery.Status, &delivery.Attempts, &delivery.NextAttempt, &delivery.Error,
+			&delivery.CreateTime, &delivery.UpdateTime); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (s *Source) ClaimEventDelivery(id, nextAttempt, lease int64) (bool, error) {
+	result, err := s.DB.Exec(`UPDATE event_outbox SET next_attempt = ?, update_time = ?
+	WHERE id = ? AND status = ? AND next_attempt = ?`, lease, now(), id, DeliveryPending, nextAttempt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (s *Source) UpdateEventDelivery(id int64, status string, attempts int, nextAttempt int64, errMsg string) error {
+	_, err := s.DB.Exec(`UPDATE event_outbox SET status = ?, attempts = ?, next_attempt = ?, error = ?, update_time = ?
+	WHERE id = ?`, status, attempts, nextAttempt, errMsg, now(), id)
+	return err
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestFileSource_ClaimEventDelivery_concurrently(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "chaosblade.jsonl")
+	sources := make([]*FileSource, 4)
+	for i := range sources {
+		src, err := NewFileSource(dataFile)
+		if err != nil {
+			t.Fatalf("open data file failed, %v", err)
+		}
+		defer src.Close()
+		sources[i] = src
+	}
+	if err := sources[0].InsertEventDeliveries([]*EventDelivery{
+		{EventId: "e1", Uid: "a1", Sink: "ops", Status: DeliveryPending, NextAttempt: 100},
+	}); err != nil {
+		t.Fatalf("insert deliveries failed, %v", err)
+	}
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	claims := 0
+	for i, src := range sources {
+		wg.Add(1)
+		go func(src *FileSource, lease int64) {
+			defer wg.Done()
+			claimed, err := src.ClaimEventDelivery(1, 100, lease)
+			if err != nil {
+				t.Errorf("claim delivery failed, %v", err)
+			}
+			if claimed {
+				lock.Lock()
+				claims++
+				lock.Unlock()
+			}
+		}(src, int64(200+i))
+	}
+	wg.Wait()
+	if claims != 1 {
+		t.Errorf("the delivery is claimed %d times", claims)
+	}
+}
+
+func TestSetEventCode(t *testing.T) {
+	SetEventRouter(&testEventRouter{})
+	defer SetEventRouter(nil)
+	src := NewMemorySource()
+	audited := NewAuditedSource(src)
+	insertExperiments(t, audited, &ExperimentModel{Uid: "a1", Command: "cpu", SubCommand: "fullload", Status: "Created"})
+	SetEventCode("a1", 63061)
+	if err := audited.UpdateExperimentModelByUid("a1", "Error", "connection refused"); err != nil {
+		t.Fatalf("update status failed, %v", err)
+	}
+	if err := audited.UpdateExperimentModelByUid("a1", "Destroyed", ""); err != nil {
+		t.Fatalf("update status failed, %v", err)
+	}
+	deliveries, err := src.QueryEventDeliveries("", 0)
+	if err != nil || len(deliveries) != 3 {
+		t.Fatalf("unexpected deliveries %+v, %v", deliveries, err)
+	}
+	// the code is only of the next event of the uid
+	for idx, expected := range []int32{0, 63061, 0} {
+		event := &LifecycleEvent{}
+		if err := json.Unmarshal([]byte(deliveries[idx].Payload), event); err != nil || event.Code != expected {
+			t.Errorf("unexpected code of the %s event %d, %v", deliveries[idx].EventType, event.Code, err)
+		}
+	}
+}
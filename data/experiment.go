@@ -17,13 +17,13 @@
 package data
 
 import (
-	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
-	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
 )
 
@@ -36,111 +36,203 @@ type ExperimentModel struct {
 	Error      string
 	CreateTime string
 	UpdateTime string
+	// GroupUid is the uid of the group which the experiment belongs to, such as a plan run or a game day
+	GroupUid string
+	// Labels are the free-form key value pairs of the experiment
+	Labels map[string]string
+	// Flags are the non-empty flags of the experiment keyed by name, the Flag keeps the same flags inline.
+	// They are parsed from the Flag if not set when inserting.
+	Flags map[string]string
+	// Probes are the steady-state probes of the experiment with their latest results
+	Probes []*Probe
 }
 
-type ExperimentSource interface {
-	// CheckAndInitExperimentTable, if experiment table not exists, then init it
-	CheckAndInitExperimentTable()
+// Probe is a steady-state probe of the experiment, such as `http://svc/health expect=200 p99<300ms`.
+// The results are the latest ones before injecting, during the experiment and after destroying.
+type Probe struct {
+	Definition string       `json:"definition"`
+	Before     *ProbeResult `json:"before,omitempty"`
+	During     *ProbeResult `json:"during,omitempty"`
+	After      *ProbeResult `json:"after,omitempty"`
+}
 
-	// ExperimentTableExists return true if experiment exists
-	ExperimentTableExists() (bool, error)
+// ProbeResult is the result of evaluating a probe
+type ProbeResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	Time    string `json:"time"`
+}
 
-	// InitExperimentTable for first executed
-	InitExperimentTable() error
+// ExperimentDeadline is the time after which the experiment must be destroyed, with the state of retrying the failed
+// destroying. The times are unix seconds, and the experiment is not overdue before the NextRetry.
+type ExperimentDeadline struct {
+	Uid       string
+	Deadline  int64
+	Attempts  int
+	LastError string
+	NextRetry int64
+}
 
+type ExperimentSource interface {
 	// InsertExperimentModel for creating chaos experiment
 	InsertExperimentModel(model *ExperimentModel) error
 
 	// UpdateExperimentModelByUid
 	UpdateExperimentModelByUid(uid, status, errMsg string) error
 
+	// UpdateExperimentStatusByUid changes the status in a transaction only if the current status is one of the expected,
+	// it returns *StatusConflictError if not. The empty expected statuses match any status.
+	UpdateExperimentStatusByUid(uid string, expected []string, status, errMsg string) error
+
 	// QueryExperimentModelByUid
 	QueryExperimentModelByUid(uid string) (*ExperimentModel, error)
 
 	// QueryExperimentModels
 	QueryExperimentModels(target, action, flag, status, limit string, asc bool) ([]*ExperimentModel, error)
 
+	// QueryExperimentModelsByFlags returns the experiments which have all the flags with the same values
+	QueryExperimentModelsByFlags(target, action, status string, flags map[string]string, limit string, asc bool) ([]*ExperimentModel, error)
+
+	// QueryExperimentModelsByGroup returns the experiments of the group order by create time
+	QueryExperimentModelsByGroup(groupUid string) ([]*ExperimentModel, error)
+
 	// QueryExperimentModelsByCommand
 	// flags value contains necessary parameters generally
 	QueryExperimentModelsByCommand(command, subCommand string, flags map[string]string) ([]*ExperimentModel, error)
 
+	// UpdateExperimentProbesByUid replaces the probes of the experiment, the unknown uid is ignored
+	UpdateExperimentProbesByUid(uid string, probes []*Probe) error
+
 	// DeleteExperimentModelByUid
 	DeleteExperimentModelByUid(uid string) error
-}
 
-const expTableDDL = `CREATE TABLE IF NOT EXISTS experiment (
-	id INTEGER PRIMARY KEY AUTOINCREMENT,
-	uid VARCHAR(32) UNIQUE,
-	command VARCHAR NOT NULL,
-	sub_command VARCHAR,
-	flag VARCHAR,
-	status VARCHAR,
-	error VARCHAR,
-	create_time VARCHAR,
-	update_time VARCHAR
-)`
+	// InsertExperimentDeadline records the time after which the experiment must be destroyed
+	InsertExperimentDeadline(uid string, deadline time.Time) error
+
+	// DeleteExperimentDeadline
+	DeleteExperimentDeadline(uid string) error
+
+	// QueryExperimentDeadline returns nil if the experiment has no deadline
+	QueryExperimentDeadline(uid string) (*ExperimentDeadline, error)
+
+	// DeferExperimentDeadline records the failed destroying of the overdue experiment, which is retried after the time
+	DeferExperimentDeadline(uid string, nextRetry time.Time, errMsg string) error
 
-var expIndexDDL = []string{
-	`CREATE INDEX exp_uid_uidx ON experiment (uid)`,
-	`CREATE INDEX exp_command_idx ON experiment (command)`,
-	`CREATE INDEX exp_status_idx ON experiment (status)`,
+	// QueryOverdueExperimentModels returns the Created or Success experiments whose deadline and next retry time
+	// are not after the time
+	QueryOverdueExperimentModels(deadline time.Time) ([]*ExperimentModel, error)
+
+	// PruneExperimentModels deletes the experiments of the statuses which are not updated after the time,
+	// and returns the deleted uids
+	PruneExperimentModels(before time.Time, statuses []string) ([]string, error)
 }
 
-var insertExpDML = `INSERT INTO
-	experiment (uid, command, sub_command, flag, status, error, create_time, update_time)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-`
+// StatusConflictError is returned if the experiment is not found or its status is not the expected one
+type StatusConflictError struct {
+	Uid      string
+	Status   string
+	Expected []string
+}
 
-func (s *Source) CheckAndInitExperimentTable() {
-	exists, err := s.ExperimentTableExists()
-	ctx := context.Background()
-	if err != nil {
-		log.Fatalf(ctx, "%s", err.Error())
-		// log.Error(err, "ExperimentTableExists err")
-		// os.Exit(1)
+func (e *StatusConflictError) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("the %s experiment not found", e.Uid)
 	}
-	if !exists {
-		err = s.InitExperimentTable()
-		if err != nil {
-			log.Fatalf(ctx, "%s", err.Error())
-			// log.Error(err, "InitExperimentTable err")
-			// os.Exit(1)
+	return fmt.Sprintf("the status of the %s experiment is %s, expected %s", e.Uid, e.Status,
+		strings.Join(e.Expected, " or "))
+}
+
+// checkExpectedStatus returns *StatusConflictError if the status is not one of the expected statuses
+func checkExpectedStatus(uid, status string, expected []string) error {
+	if len(expected) == 0 {
+		return nil
+	}
+	for _, expect := range expected {
+		if status == expect {
+			return nil
 		}
 	}
+	return &StatusConflictError{Uid: uid, Status: status, Expected: expected}
 }
 
-func (s *Source) ExperimentTableExists() (bool, error) {
-	stmt, err := s.DB.Prepare(tableExistsDQL)
-	if err != nil {
-		return false, fmt.Errorf("select experiment table exists err when invoke db prepare, %s", err)
-	}
-	defer stmt.Close()
-	rows, err := stmt.Query("experiment")
-	if err != nil {
-		return false, fmt.Errorf("select experiment table exists or not err, %s", err)
+// ParseFlags returns the non-empty flags of the inline flags built by spec.ConvertExpMatchersToString
+func ParseFlags(flag string) map[string]string {
+	flags := make(map[string]string)
+	for name, value := range spec.ConvertCommandsToExpModel("", "", flag).ActionFlags {
+		if value != "" {
+			flags[name] = value
+		}
 	}
-	defer rows.Close()
-	var c int
-	if rows.Next() {
-		rows.Scan(&c)
+	if len(flags) == 0 {
+		return nil
 	}
+	return flags
+}
 
-	return c != 0, nil
+// matchFlags returns true if the flags contain the non-empty expected flag values
+func matchFlags(flags, expected map[string]string) bool {
+	for name, value := range expected {
+		if value == "" {
+			continue
+		}
+		if flags[name] != value {
+			return false
+		}
+	}
+	return true
 }
 
-func (s *Source) InitExperimentTable() error {
-	_, err := s.DB.Exec(expTableDDL)
+// marshalMap returns the JSON of the map, or empty string if the map is empty
+func marshalMap(values map[string]string) (string, error) {
+	if len(values) == 0 {
+		return "", nil
+	}
+	bytes, err := json.Marshal(values)
 	if err != nil {
-		return fmt.Errorf("create experiment table err, %s", err)
+		return "", err
 	}
-	for _, sql := range expIndexDDL {
-		s.DB.Exec(sql)
+	return string(bytes), nil
+}
+
+// marshalProbes returns the JSON of the probes, or empty string if there is no probe
+func marshalProbes(probes []*Probe) (string, error) {
+	if len(probes) == 0 {
+		return "", nil
 	}
-	return nil
+	bytes, err := json.Marshal(probes)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
 }
 
+var insertExpDML = `INSERT INTO
+	experiment (uid, command, sub_command, flag, status, error, create_time, update_time, group_uid, labels, flags, probes)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
 func (s *Source) InsertExperimentModel(model *ExperimentModel) error {
-	stmt, err := s.DB.Prepare(insertExpDML)
+	return insertExperimentModel(s.DB, model)
+}
+
+func insertExperimentModel(q querier, model *ExperimentModel) error {
+	labels, err := marshalMap(model.Labels)
+	if err != nil {
+		return err
+	}
+	modelFlags := model.Flags
+	if modelFlags == nil {
+		modelFlags = ParseFlags(model.Flag)
+	}
+	flags, err := marshalMap(modelFlags)
+	if err != nil {
+		return err
+	}
+	probes, err := marshalProbes(model.Probes)
+	if err != nil {
+		return err
+	}
+	stmt, err := q.Prepare(insertExpDML)
 	if err != nil {
 		return err
 	}
@@ -154,6 +246,10 @@ func (s *Source) InsertExperimentModel(model *ExperimentModel) error {
 		model.Error,
 		model.CreateTime,
 		model.UpdateTime,
+		model.GroupUid,
+		labels,
+		flags,
+		probes,
 	)
 	if err != nil {
 		return err
@@ -177,8 +273,45 @@ func (s *Source) UpdateExperimentModelByUid(uid, status, errMsg string) error {
 	return nil
 }
 
+func (s *Source) UpdateExperimentStatusByUid(uid string, expected []string, status, errMsg string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var current string
+	err = tx.QueryRow(`SELECT status FROM experiment WHERE uid = ?`, uid).Scan(&current)
+	if err == sql.ErrNoRows {
+		return &StatusConflictError{Uid: uid, Expected: expected}
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkExpectedStatus(uid, current, expected); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE experiment SET status = ?, error = ?, update_time = ? WHERE uid = ?`,
+		status, errMsg, time.Now().Format(time.RFC3339Nano), uid); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Source) UpdateExperimentProbesByUid(uid string, probes []*Probe) error {
+	value, err := marshalProbes(probes)
+	if err != nil {
+		return err
+	}
+	_, err = s.DB.Exec(`UPDATE experiment SET probes = ? WHERE uid = ?`, value, uid)
+	return err
+}
+
 func (s *Source) QueryExperimentModelByUid(uid string) (*ExperimentModel, error) {
-	stmt, err := s.DB.Prepare(`SELECT * FROM experiment WHERE uid = ?`)
+	return queryExperimentModelByUid(s.DB, uid)
+}
+
+func queryExperimentModelByUid(q querier, uid string) (*ExperimentModel, error) {
+	stmt, err := q.Prepare(`SELECT * FROM experiment WHERE uid = ?`)
 	if err != nil {
 		return nil, err
 	}
@@ -248,31 +381,79 @@ func (s *Source) QueryExperimentModels(target, action, flag, status, limit strin
 	return getExperimentModelsFrom(rows)
 }
 
-func (s *Source) QueryExperimentModelsByCommand(command, subCommand string, flags map[string]string) ([]*ExperimentModel, error) {
-	models := make([]*ExperimentModel, 0)
-	experimentModels, err := s.QueryExperimentModels(command, subCommand, "", "", "", true)
-	if err != nil {
-		return models, err
-	}
-	if flags == nil || len(flags) == 0 {
-		return experimentModels, nil
-	}
-	for _, experimentModel := range experimentModels {
-		recordModel := spec.ConvertCommandsToExpModel(subCommand, command, experimentModel.Flag)
-		recordFlags := recordModel.ActionFlags
-		isMatched := true
-		for k, v := range flags {
-			if v == "" {
-				continue
-			}
-			if recordFlags[k] != v {
-				isMatched = false
-				break
-			}
+func (s *Source) QueryExperimentModelsByFlags(target, action, status string, flags map[string]string, limit string,
+	asc bool,
+) ([]*ExperimentModel, error) {
+	sql := `SELECT * FROM experiment where 1=1`
+	parameters := make([]interface{}, 0)
+	if target != "" {
+		sql = fmt.Sprintf(`%s and command = ?`, sql)
+		parameters = append(parameters, target)
+	}
+	if action != "" {
+		sql = fmt.Sprintf(`%s and sub_command = ?`, sql)
+		parameters = append(parameters, action)
+	}
+	if status != "" {
+		sql = fmt.Sprintf(`%s and status = ?`, sql)
+		parameters = append(parameters, UpperFirst(status))
+	}
+	names := make([]string, 0, len(flags))
+	for name, value := range flags {
+		if value != "" {
+			names = append(names, name)
 		}
-		if isMatched {
-			models = append(models, experimentModel)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// the flags of the experiments without flags are empty strings, which are malformed JSON
+		sql = fmt.Sprintf(`%s and json_extract(CASE WHEN json_valid(flags) THEN flags ELSE '{}' END, ?) = ?`, sql)
+		parameters = append(parameters, fmt.Sprintf(`$."%s"`, strings.ReplaceAll(name, `"`, `\"`)), flags[name])
+	}
+	if asc {
+		sql = fmt.Sprintf(`%s order by id asc`, sql)
+	} else {
+		sql = fmt.Sprintf(`%s order by id desc`, sql)
+	}
+	if limit != "" {
+		offset, count, err := parseLimit(limit)
+		if err != nil {
+			return nil, err
 		}
+		sql = fmt.Sprintf(`%s limit ?,?`, sql)
+		parameters = append(parameters, offset, count)
+	}
+	stmt, err := s.DB.Prepare(sql)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	rows, err := stmt.Query(parameters...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return getExperimentModelsFrom(rows)
+}
+
+func (s *Source) QueryExperimentModelsByGroup(groupUid string) ([]*ExperimentModel, error) {
+	stmt, err := s.DB.Prepare(`SELECT * FROM experiment WHERE group_uid = ? order by id asc`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	rows, err := stmt.Query(groupUid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return getExperimentModelsFrom(rows)
+}
+
+func (s *Source) QueryExperimentModelsByCommand(command, subCommand string, flags map[string]string) ([]*ExperimentModel, error) {
+	models, err := s.QueryExperimentModelsByFlags(command, subCommand, "", flags, "", true)
+	if err != nil {
+		return make([]*ExperimentModel, 0), err
 	}
 	return models, nil
 }
@@ -281,11 +462,29 @@ func getExperimentModelsFrom(rows *sql.Rows) ([]*ExperimentModel, error) {
 	models := make([]*ExperimentModel, 0)
 	for rows.Next() {
 		var id int
-		var uid, command, subCommand, flag, status, error, createTime, updateTime string
-		err := rows.Scan(&id, &uid, &command, &subCommand, &flag, &status, &error, &createTime, &updateTime)
+		var uid, command, subCommand, flag, status, error, createTime, updateTime, groupUid, labels, flags, probes string
+		err := rows.Scan(&id, &uid, &command, &subCommand, &flag, &status, &error, &createTime, &updateTime,
+			&groupUid, &labels, &flags, &probes)
 		if err != nil {
 			return nil, err
 		}
+		var labelMap, flagMap map[string]string
+		if labels != "" {
+			if err := json.Unmarshal([]byte(labels), &labelMap); err != nil {
+				return nil, fmt.Errorf("unmarshal labels of %s experiment err, %s", uid, err)
+			}
+		}
+		if flags != "" {
+			if err := json.Unmarshal([]byte(flags), &flagMap); err != nil {
+				return nil, fmt.Errorf("unmarshal flags of %s experiment err, %s", uid, err)
+			}
+		}
+		var probeList []*Probe
+		if probes != "" {
+			if err := json.Unmarshal([]byte(probes), &probeList); err != nil {
+				return nil, fmt.Errorf("unmarshal probes of %s experiment err, %s", uid, err)
+			}
+		}
 		model := &ExperimentModel{
 			Uid:        uid,
 			Command:    command,
@@ -295,10 +494,14 @@ func getExperimentModelsFrom(rows *sql.Rows) ([]*ExperimentModel, error) {
 			Error:      error,
 			CreateTime: createTime,
 			UpdateTime: updateTime,
+			GroupUid:   groupUid,
+			Labels:     labelMap,
+			Flags:      flagMap,
+			Probes:     probeList,
 		}
 		models = append(models, model)
 	}
-	return models, nil
+	return models, rows.Err()
 }
 
 func (s *Source) DeleteExperimentModelByUid(uid string) error {
@@ -311,5 +514,107 @@ func (s *Source) DeleteExperimentModelByUid(uid string) error {
 	if err != nil {
 		return err
 	}
-	return nil
+	return s.DeleteExperimentDeadline(uid)
+}
+
+func (s *Source) InsertExperimentDeadline(uid string, deadline time.Time) error {
+	stmt, err := s.DB.Prepare(`INSERT OR REPLACE INTO
+	experiment_deadline (uid, deadline, create_time)
+	VALUES (?, ?, ?)
+`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(uid, deadline.Unix(), time.Now().Format(time.RFC3339Nano))
+	return err
+}
+
+func (s *Source) QueryExperimentDeadline(uid string) (*ExperimentDeadline, error) {
+	deadline := &ExperimentDeadline{}
+	err := s.DB.QueryRow(`SELECT uid, deadline, attempts, last_error, next_retry FROM experiment_deadline
+	WHERE uid = ?`, uid).Scan(&deadline.Uid, &deadline.Deadline, &deadline.Attempts, &deadline.LastError,
+		&deadline.NextRetry)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return deadline, nil
+}
+
+func (s *Source) DeferExperimentDeadline(uid string, nextRetry time.Time, errMsg string) error {
+	stmt, err := s.DB.Prepare(`UPDATE experiment_deadline
+	SET attempts = attempts + 1, last_error = ?, next_retry = ?
+	WHERE uid = ?
+`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(errMsg, nextRetry.Unix(), uid)
+	return err
+}
+
+func (s *Source) DeleteExperimentDeadline(uid string) error {
+	stmt, err := s.DB.Prepare(`DELETE FROM experiment_deadline WHERE uid = ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(uid)
+	return err
+}
+
+func (s *Source) QueryOverdueExperimentModels(deadline time.Time) ([]*ExperimentModel, error) {
+	stmt, err := s.DB.Prepare(`SELECT e.* FROM experiment e
+	INNER JOIN experiment_deadline d ON e.uid = d.uid
+	WHERE d.deadline <= ? AND d.next_retry <= ? AND e.status IN ('Created', 'Success')
+	ORDER BY d.deadline ASC
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	rows, err := stmt.Query(deadline.Unix(), deadline.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return getExperimentModelsFrom(rows)
+}
+
+func (s *Source) PruneExperimentModels(before time.Time, statuses []string) ([]string, error) {
+	return s.pruneWithJournal(AuditKindExperiment, before, statuses, nil)
+}
+
+// queryPrunableUids returns the uids of the records of the statuses which are not updated after the time.
+// The time is compared after parsing, because the RFC3339Nano strings are not ordered.
+func queryPrunableUids(tx *sql.Tx, table string, before time.Time, statuses []string) ([]string, error) {
+	uids := make([]string, 0)
+	if len(statuses) == 0 {
+		return uids, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(statuses)), ",")
+	parameters := make([]interface{}, 0, len(statuses))
+	for _, status := range statuses {
+		parameters = append(parameters, status)
+	}
+	rows, err := tx.Query(fmt.Sprintf(`SELECT uid, create_time, update_time FROM %s WHERE status IN (%s) ORDER BY id`,
+		table, placeholders), parameters...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var uid, createTime, updateTime string
+		if err := rows.Scan(&uid, &createTime, &updateTime); err != nil {
+			return nil, err
+		}
+		if updatedBefore(createTime, updateTime, before) {
+			uids = append(uids, uid)
+		}
+	}
+	return uids, rows.Err()
 }
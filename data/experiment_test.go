@@ -0,0 +1,162 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestSource(t *testing.T) *Source {
+	src, err := NewSource(filepath.Join(t.TempDir(), dataFile))
+	if err != nil {
+		t.Fatalf("open data file failed, %v", err)
+	}
+	if _, err := src.Migrate(); err != nil {
+		t.Fatalf("migrate data file failed, %v", err)
+	}
+	t.Cleanup(src.Close)
+	return src
+}
+
+func TestSource_QueryOverdueExperimentModels(t *testing.T) {
+	src := newTestSource(t)
+	now := time.Now()
+	experiments := []struct {
+		uid      string
+		status   string
+		deadline time.Time
+	}{
+		{"overdue", "Success", now.Add(-time.Minute)},
+		{"created", "Created", now},
+		{"pending", "Success", now.Add(time.Minute)},
+		{"destroyed", "Destroyed", now.Add(-time.Minute)},
+	}
+	for _, exp := range experiments {
+		if err := src.InsertExperimentModel(&ExperimentModel{
+			Uid: exp.uid, Command: "cpu", SubCommand: "fullload", Status: exp.status,
+		}); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+		if err := src.InsertExperimentDeadline(exp.uid, exp.deadline); err != nil {
+			t.Fatalf("insert deadline failed, %v", err)
+		}
+	}
+	if err := src.InsertExperimentModel(&ExperimentModel{Uid: "no-timeout", Command: "cpu", Status: "Success"}); err != nil {
+		t.Fatalf("insert experiment failed, %v", err)
+	}
+
+	models, err := src.QueryOverdueExperimentModels(now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(models) != 2 || models[0].Uid != "overdue" || models[1].Uid != "created" {
+		t.Errorf("unexpected overdue experiments: %+v", models)
+	}
+
+	// the failed destroying is retried after the next retry time
+	if err := src.DeferExperimentDeadline("created", now.Add(time.Minute), "destroy failed"); err != nil {
+		t.Fatalf("defer deadline failed, %v", err)
+	}
+	if models, _ := src.QueryOverdueExperimentModels(now); len(models) != 1 || models[0].Uid != "overdue" {
+		t.Errorf("unexpected overdue experiments after deferring: %+v", models)
+	}
+	deadline, err := src.QueryExperimentDeadline("created")
+	if err != nil || deadline == nil || deadline.Attempts != 1 || deadline.LastError != "destroy failed" {
+		t.Errorf("unexpected deadline %+v, %v", deadline, err)
+	}
+
+	if err := src.DeleteExperimentModelByUid("overdue"); err != nil {
+		t.Fatalf("delete experiment failed, %v", err)
+	}
+	if err := src.DeleteExperimentDeadline("created"); err != nil {
+		t.Fatalf("delete deadline failed, %v", err)
+	}
+	models, err = src.QueryOverdueExperimentModels(now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(models) != 1 || models[0].Uid != "pending" {
+		t.Errorf("unexpected overdue experiments: %+v", models)
+	}
+}
+
+func TestSource_QueryExperimentModelsByGroup(t *testing.T) {
+	src := newTestSource(t)
+	models := []*ExperimentModel{
+		{Uid: "7c1f7afc281482c8", Command: "cpu", GroupUid: "gameday-1", Labels: map[string]string{"team": "sre"}},
+		{Uid: "9b2e3c4d5f6a7b8c", Command: "network", GroupUid: "gameday-1"},
+		{Uid: "1a2b3c4d5e6f7a8b", Command: "disk", GroupUid: "gameday-2"},
+	}
+	for _, model := range models {
+		if err := src.InsertExperimentModel(model); err != nil {
+			t.Fatalf("insert experiment failed, %v", err)
+		}
+	}
+	got, err := src.QueryExperimentModelsByGroup("gameday-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Uid != "7c1f7afc281482c8" || got[1].Uid != "9b2e3c4d5f6a7b8c" {
+		t.Fatalf("unexpected experiments: %+v", got)
+	}
+	if got[0].Labels["team"] != "sre" || got[1].Labels != nil {
+		t.Errorf("unexpected labels: %v, %v", got[0].Labels, got[1].Labels)
+	}
+}
+
+func TestSource_UpdateExperimentStatusByUid_concurrently(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), dataFile)
+	sources := make([]*Source, 8)
+	for idx := range sources {
+		src, err := NewSource(dataFile)
+		if err != nil {
+			t.Fatalf("open data file failed, %v", err)
+		}
+		defer src.Close()
+		sources[idx] = src
+	}
+	if _, err := sources[0].Migrate(); err != nil {
+		t.Fatalf("migrate data file failed, %v", err)
+	}
+	if err := sources[0].InsertExperimentModel(&ExperimentModel{Uid: "e1", Command: "cpu", Status: "Created"}); err != nil {
+		t.Fatalf("insert experiment failed, %v", err)
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, len(sources))
+	for idx, src := range sources {
+		wg.Add(1)
+		go func(idx int, src *Source) {
+			defer wg.Done()
+			errs[idx] = src.UpdateExperimentStatusByUid("e1", []string{"Created"}, "Success", "")
+		}(idx, src)
+	}
+	wg.Wait()
+	updated := 0
+	for _, err := range errs {
+		if err == nil {
+			updated++
+		} else if _, ok := err.(*StatusConflictError); !ok {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+	if updated != 1 {
+		t.Errorf("expect only one update succeeds, got %d", updated)
+	}
+}
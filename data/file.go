@@ -0,0 +1,612 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	opInsertExperiment      = "insertExperiment"
+	opUpdateExperiment      = "updateExperiment"
+	opUpdateProbes          = "updateProbes"
+	opDeleteExperiment      = "deleteExperiment"
+	opInsertDeadline        = "insertDeadline"
+	opDeleteDeadline        = "deleteDeadline"
+	opDeferDeadline         = "deferDeadline"
+	opInsertPreparation     = "insertPreparation"
+	opUpdatePreparation     = "updatePreparation"
+	opUpdatePreparationPort = "updatePreparationPort"
+	opUpdatePreparationPid  = "updatePreparationPid"
+	opDeleteExperiments     = "deleteExperiments"
+	opDeletePreparations    = "deletePreparations"
+	opAppendAudit           = "appendAudit"
+	opInsertDeliveries      = "insertDeliveries"
+	opClaimDelivery         = "claimDelivery"
+	opUpdateDelivery        = "updateDelivery"
+)
+
+// fileRecord is a line of the data file, which records a change of the experiments or preparations
+type fileRecord struct {
+	Op          string             `json:"op"`
+	Time        string             `json:"time"`
+	Uid         string             `json:"uid,omitempty"`
+	Experiment  *ExperimentModel   `json:"experiment,omitempty"`
+	Preparation *PreparationRecord `json:"preparation,omitempty"`
+	Status      string             `json:"status,omitempty"`
+	Error       string             `json:"error,omitempty"`
+	Port        string             `json:"port,omitempty"`
+	Pid         string             `json:"pid,omitempty"`
+	Deadline    int64              `json:"deadline,omitempty"`
+	// NextRetry is the time after which the failed destroying of the overdue experiment is retried
+	NextRetry int64 `json:"nextRetry,omitempty"`
+	// Expected are the statuses which the experiment must be in to apply the status change
+	Expected []string `json:"expected,omitempty"`
+	// Uids are the records deleted by pruning
+	Uids []string `json:"uids,omitempty"`
+	// Audit is the entry appended to the audit journal
+	Audit *AuditEntry `json:"audit,omitempty"`
+	// Audits are the journal of the change, they are applied with the Deliveries only if the change is applied
+	Audits []*AuditEntry `json:"audits,omitempty"`
+	// Probes are the probes of the experiment replaced by the updating
+	Probes []*Probe `json:"probes,omitempty"`
+	// Deliveries are the event deliveries inserted to the outbox, the ids are assigned when replaying
+	Deliveries []*EventDelivery `json:"deliveries,omitempty"`
+	// Delivery is the event delivery claimed or updated, the NextAttempt of the claimed one is the expected value
+	Delivery *EventDelivery `json:"delivery,omitempty"`
+	// Lease is the next attempt of the claimed delivery
+	Lease int64 `json:"lease,omitempty"`
+}
+
+// FileSource appends the changes to a JSON lines file and replays them to query. It needs no file lock,
+// so it works on the filesystems where the sqlite locking fails. The changes appended by other processes are
+// replayed before every operation. If the file is read-only, the source can only be queried.
+type FileSource struct {
+	lock     sync.Mutex
+	path     string
+	file     *os.File
+	readOnly bool
+	// offset is the end of the last replayed line
+	offset int64
+	store  *memoryStore
+	// pending is the change appended by the source, pendingErr is the error of replaying it
+	pending    *fileRecord
+	pendingErr error
+}
+
+// NewFileSource opens or creates the data file
+func NewFileSource(dataFile string) (*FileSource, error) {
+	fs := &FileSource{path: dataFile, store: newMemoryStore()}
+	file, err := os.OpenFile(dataFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		if !errors.Is(err, os.ErrPermission) && !errors.Is(err, syscall.EROFS) {
+			return nil, fmt.Errorf("open data file err, %s", err)
+		}
+		if file, err = os.Open(dataFile); err != nil {
+			return nil, fmt.Errorf("open data file err, %s", err)
+		}
+		fs.readOnly = true
+	}
+	fs.file = file
+	if err := fs.refresh(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return fs, nil
+}
+
+// openFileSource opens the data file, the chaosblade.jsonl file next to the sqlite data file is used by default
+func openFileSource(dataFile string) (SourceI, error) {
+	if dataFile == "" {
+		sqliteFile := GetDataFilePath()
+		dataFile = strings.TrimSuffix(sqliteFile, path.Ext(sqliteFile)) + ".jsonl"
+	}
+	return NewFileSource(dataFile)
+}
+
+func (f *FileSource) Close() error {
+	return f.file.Close()
+}
+
+// refresh replays the lines appended after the offset, the incomplete last line is replayed next time
+func (f *FileSource) refresh() error {
+	info, err := f.file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat data file err, %s", err)
+	}
+	if info.Size() < f.offset {
+		// the file is truncated, replay it from the beginning
+		f.offset = 0
+		f.store = newMemoryStore()
+	}
+	if info.Size() == f.offset {
+		return nil
+	}
+	content := make([]byte, info.Size()-f.offset)
+	if _, err := f.file.ReadAt(content, f.offset); err != nil && err != io.EOF {
+		return fmt.Errorf("read data file err, %s", err)
+	}
+	end := bytes.LastIndexByte(content, '\n')
+	if end < 0 {
+		return nil
+	}
+	for _, line := range bytes.Split(content[:end], []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var record fileRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return fmt.Errorf("unmarshal the line of data file %s err, %s", f.path, err)
+		}
+		// the conflicting changes appended by processes concurrently are ignored, the first one wins
+		err := f.apply(&record)
+		if f.pending != nil && record.Op == f.pending.Op && record.Uid == f.pending.Uid && record.Time == f.pending.Time {
+			f.pendingErr = err
+			// the ids of the deliveries are set when replaying
+			for idx, delivery := range record.Deliveries {
+				if idx < len(f.pending.Deliveries) {
+					f.pending.Deliveries[idx].Id = delivery.Id
+				}
+			}
+		}
+	}
+	f.offset += int64(end + 1)
+	return nil
+}
+
+// apply applies the change, and the change with the journal is applied only if the journal is chained to the last
+// audit entry, so they are applied together
+func (f *FileSource) apply(record *fileRecord) error {
+	if len(record.Audits) == 0 {
+		return f.applyChange(record)
+	}
+	if err := f.store.checkJournal(record.Audits); err != nil {
+		return err
+	}
+	// the record deleted by other processes concurrently is not changed
+	switch record.Op {
+	case opUpdateExperiment, opDeleteExperiment:
+		if f.store.findExperiment(record.Uid) < 0 {
+			return &StatusConflictError{Uid: record.Uid, Expected: record.Expected}
+		}
+	case opUpdatePreparation:
+		if f.store.findPreparation(record.Uid) < 0 {
+			return &StatusConflictError{Uid: record.Uid}
+		}
+	}
+	if err := f.applyChange(record); err != nil {
+		return err
+	}
+	f.store.appendJournal(record.Audits, record.Deliveries)
+	return nil
+}
+
+func (f *FileSource) applyChange(record *fileRecord) error {
+	switch record.Op {
+	case opInsertExperiment:
+		if record.Experiment == nil {
+			return fmt.Errorf("the experiment of the %s operation is empty", record.Op)
+		}
+		return f.store.insertExperiment(record.Experiment)
+	case opUpdateExperiment:
+		return f.store.updateExperiment(record.Uid, record.Expected, record.Status, record.Error, record.Time)
+	case opUpdateProbes:
+		f.store.updateProbes(record.Uid, record.Probes)
+	case opDeleteExperiment:
+		f.store.deleteExperiment(record.Uid)
+	case opInsertDeadline:
+		f.store.insertDeadline(record.Uid, record.Deadline)
+	case opDeleteDeadline:
+		f.store.deleteDeadline(record.Uid)
+	case opDeferDeadline:
+		f.store.deferDeadline(record.Uid, record.NextRetry, record.Error)
+	case opInsertPreparation:
+		if record.Preparation == nil {
+			return fmt.Errorf("the preparation of the %s operation is empty", record.Op)
+		}
+		return f.store.insertPreparation(record.Preparation)
+	case opUpdatePreparation:
+		f.store.updatePreparation(record.Uid, record.Time, func(preparation *PreparationRecord) {
+			preparation.Status = record.Status
+			preparation.Error = record.Error
+		})
+	case opUpdatePreparationPort:
+		f.store.updatePreparation(record.Uid, record.Time, func(preparation *PreparationRecord) {
+			preparation.Port = record.Port
+		})
+	case opUpdatePreparationPid:
+		f.store.updatePreparation(record.Uid, record.Time, func(preparation *PreparationRecord) {
+			preparation.Pid = record.Pid
+		})
+	case opDeleteExperiments:
+		f.store.deleteExperiments(record.Uids)
+	case opDeletePreparations:
+		f.store.deletePreparations(record.Uids)
+	case opAppendAudit:
+		if record.Audit == nil {
+			return fmt.Errorf("the audit entry of the %s operation is empty", record.Op)
+		}
+		return f.store.appendAuditEntry(record.Audit)
+	case opInsertDeliveries:
+		f.store.appendJournal(nil, record.Deliveries)
+	case opClaimDelivery:
+		if record.Delivery == nil {
+			return fmt.Errorf("the delivery of the %s operation is empty", record.Op)
+		}
+		return f.store.claimDelivery(record.Delivery.Id, record.Delivery.NextAttempt, record.Lease, record.Time)
+	case opUpdateDelivery:
+		if record.Delivery == nil {
+			return fmt.Errorf("the delivery of the %s operation is empty", record.Op)
+		}
+		delivery := record.Delivery
+		f.store.updateDelivery(delivery.Id, record.Time, func(updated *EventDelivery) {
+			updated.Status, updated.Attempts, updated.NextAttempt, updated.Error =
+				delivery.Status, delivery.Attempts, delivery.NextAttempt, delivery.Error
+		})
+	default:
+		return fmt.Errorf("unknown operation %s", record.Op)
+	}
+	return nil
+}
+
+// append replays the changes of other processes, checks the change by the check function and writes it
+// in one write call, so the lines of processes are not interleaved
+func (f *FileSource) append(record *fileRecord, check func() error) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.readOnly {
+		return fmt.Errorf("the data file %s is read-only", f.path)
+	}
+	if err := f.refresh(); err != nil {
+		return err
+	}
+	record.Time = now()
+	if check != nil {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	f.pending, f.pendingErr = record, nil
+	defer func() { f.pending, f.pendingErr = nil, nil }()
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write data file err, %s", err)
+	}
+	if err := f.refresh(); err != nil {
+		return err
+	}
+	// the change conflicts with the one appended by other processes concurrently
+	return f.pendingErr
+}
+
+// query replays the changes of other processes before querying
+func (f *FileSource) query(query func(store *memoryStore) error) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.refresh(); err != nil {
+		return err
+	}
+	return query(f.store)
+}
+
+func (f *FileSource) InsertExperimentModel(model *ExperimentModel) error {
+	return f.append(&fileRecord{Op: opInsertExperiment, Uid: model.Uid, Experiment: model}, func() error {
+		if f.store.findExperiment(model.Uid) >= 0 {
+			return fmt.Errorf("the %s experiment already exists", model.Uid)
+		}
+		return nil
+	})
+}
+
+func (f *FileSource) UpdateExperimentModelByUid(uid, status, errMsg string) error {
+	return f.append(&fileRecord{Op: opUpdateExperiment, Uid: uid, Status: status, Error: errMsg}, nil)
+}
+
+// UpdateExperimentStatusByUid checks the status before appending the change, and the change is checked again
+// when replaying, so the conflicting change appended by other processes concurrently is not applied
+func (f *FileSource) UpdateExperimentStatusByUid(uid string, expected []string, status, errMsg string) error {
+	record := &fileRecord{Op: opUpdateExperiment, Uid: uid, Status: status, Error: errMsg, Expected: expected}
+	return f.append(record, func() error {
+		idx := f.store.findExperiment(uid)
+		if idx < 0 {
+			return &StatusConflictError{Uid: uid, Expected: expected}
+		}
+		return checkExpectedStatus(uid, f.store.experiments[idx].Status, expected)
+	})
+}
+
+func (f *FileSource) QueryExperimentModelByUid(uid string) (model *ExperimentModel, err error) {
+	err = f.query(func(store *memoryStore) error {
+		model = store.queryExperimentByUid(uid)
+		return nil
+	})
+	return
+}
+
+func (f *FileSource) QueryExperimentModels(target, action, flag, status, limit string, asc bool) (models []*ExperimentModel, err error) {
+	err = f.query(func(store *memoryStore) error {
+		models, err = store.queryExperiments(target, action, flag, status, nil, limit, asc)
+		return err
+	})
+	return
+}
+
+func (f *FileSource) QueryExperimentModelsByFlags(target, action, status string, flags map[string]string, limit string,
+	asc bool,
+) (models []*ExperimentModel, err error) {
+	err = f.query(func(store *memoryStore) error {
+		models, err = store.queryExperiments(target, action, "", status, flags, limit, asc)
+		return err
+	})
+	return
+}
+
+func (f *FileSource) QueryExperimentModelsByGroup(groupUid string) (models []*ExperimentModel, err error) {
+	err = f.query(func(store *memoryStore) error {
+		models = store.queryExperimentsByGroup(groupUid)
+		return nil
+	})
+	return
+}
+
+func (f *FileSource) QueryExperimentModelsByCommand(command, subCommand string, flags map[string]string) ([]*ExperimentModel, error) {
+	models, err := f.QueryExperimentModelsByFlags(command, subCommand, "", flags, "", true)
+	if err != nil {
+		return make([]*ExperimentModel, 0), err
+	}
+	return models, nil
+}
+
+func (f *FileSource) UpdateExperimentProbesByUid(uid string, probes []*Probe) error {
+	return f.append(&fileRecord{Op: opUpdateProbes, Uid: uid, Probes: probes}, nil)
+}
+
+func (f *FileSource) DeleteExperimentModelByUid(uid string) error {
+	return f.append(&fileRecord{Op: opDeleteExperiment, Uid: uid}, nil)
+}
+
+func (f *FileSource) InsertExperimentDeadline(uid string, deadline time.Time) error {
+	return f.append(&fileRecord{Op: opInsertDeadline, Uid: uid, Deadline: deadline.Unix()}, nil)
+}
+
+func (f *FileSource) DeleteExperimentDeadline(uid string) error {
+	return f.append(&fileRecord{Op: opDeleteDeadline, Uid: uid}, nil)
+}
+
+func (f *FileSource) QueryExperimentDeadline(uid string) (deadline *ExperimentDeadline, err error) {
+	err = f.query(func(store *memoryStore) error {
+		deadline = store.queryDeadline(uid)
+		return nil
+	})
+	return
+}
+
+func (f *FileSource) DeferExperimentDeadline(uid string, nextRetry time.Time, errMsg string) error {
+	return f.append(&fileRecord{Op: opDeferDeadline, Uid: uid, NextRetry: nextRetry.Unix(), Error: errMsg}, nil)
+}
+
+func (f *FileSource) QueryOverdueExperimentModels(deadline time.Time) (models []*ExperimentModel, err error) {
+	err = f.query(func(store *memoryStore) error {
+		models = store.queryOverdueExperiments(deadline.Unix())
+		return nil
+	})
+	return
+}
+
+func (f *FileSource) InsertPreparationRecord(record *PreparationRecord) error {
+	return f.append(&fileRecord{Op: opInsertPreparation, Uid: record.Uid, Preparation: record}, func() error {
+		if f.store.findPreparation(record.Uid) >= 0 {
+			return fmt.Errorf("the %s preparation already exists", record.Uid)
+		}
+		return nil
+	})
+}
+
+func (f *FileSource) QueryPreparationByUid(uid string) (record *PreparationRecord, err error) {
+	err = f.query(func(store *memoryStore) error {
+		record = store.queryPreparationByUid(uid)
+		return nil
+	})
+	return
+}
+
+func (f *FileSource) QueryRunningPreByTypeAndProcess(programType string, processName, processId string) (record *PreparationRecord, err error) {
+	err = f.query(func(store *memoryStore) error {
+		record = store.queryRunningPreparation(programType, processName, processId)
+		return nil
+	})
+	return
+}
+
+func (f *FileSource) UpdatePreparationRecordByUid(uid, status, errMsg string) error {
+	return f.append(&fileRecord{Op: opUpdatePreparation, Uid: uid, Status: status, Error: errMsg}, nil)
+}
+
+func (f *FileSource) UpdatePreparationPortByUid(uid, port string) error {
+	return f.append(&fileRecord{Op: opUpdatePreparationPort, Uid: uid, Port: port}, nil)
+}
+
+func (f *FileSource) UpdatePreparationPidByUid(uid, pid string) error {
+	return f.append(&fileRecord{Op: opUpdatePreparationPid, Uid: uid, Pid: pid}, nil)
+}
+
+func (f *FileSource) QueryPreparationRecords(target, status, action, flag, limit string, asc bool) (records []*PreparationRecord, err error) {
+	err = f.query(func(store *memoryStore) error {
+		records, err = store.queryPreparations(target, status, limit, asc)
+		return err
+	})
+	return
+}
+
+func (f *FileSource) PruneExperimentModels(before time.Time, statuses []string) ([]string, error) {
+	return f.pruneWithJournal(AuditKindExperiment, before, statuses, nil)
+}
+
+func (f *FileSource) PrunePreparationRecords(before time.Time, statuses []string) ([]string, error) {
+	return f.pruneWithJournal(AuditKindPreparation, before, statuses, nil)
+}
+
+// applyTransition appends the change with its journal in one line, and appends it again if another process appends
+// an audit entry concurrently
+func (f *FileSource) applyTransition(t *transition) error {
+	var err error
+	for retry := 0; retry < 3; retry++ {
+		record := transitionRecord(t)
+		err = f.append(record, func() error {
+			result, err := f.store.transitionResult(t, record.Time)
+			if err != nil || result == nil {
+				// the unknown record is appended without journal, which only deletes the deadline of the experiment
+				return err
+			}
+			entry, deliveries, err := t.journal(t.uid, result.previous, result.experiment, result.preparation)
+			if err != nil {
+				return err
+			}
+			chainAuditEntry(entry, f.store.lastAuditEntry())
+			record.Audits, record.Deliveries = []*AuditEntry{entry}, deliveries
+			return nil
+		})
+		if !errors.Is(err, errAuditConflict) {
+			return err
+		}
+	}
+	return err
+}
+
+// transitionRecord returns the line of the change of the transition
+func transitionRecord(t *transition) *fileRecord {
+	switch {
+	case t.experiment != nil:
+		return &fileRecord{Op: opInsertExperiment, Uid: t.uid, Experiment: t.experiment}
+	case t.preparation != nil:
+		return &fileRecord{Op: opInsertPreparation, Uid: t.uid, Preparation: t.preparation}
+	case t.deleted && t.kind == AuditKindExperiment:
+		return &fileRecord{Op: opDeleteExperiment, Uid: t.uid}
+	case t.deleted:
+		return &fileRecord{Op: opDeletePreparations, Uids: []string{t.uid}}
+	case t.kind == AuditKindExperiment:
+		return &fileRecord{Op: opUpdateExperiment, Uid: t.uid, Status: t.status, Error: t.errMsg, Expected: t.expected}
+	}
+	return &fileRecord{Op: opUpdatePreparation, Uid: t.uid, Status: t.status, Error: t.errMsg}
+}
+
+// pruneWithJournal appends the uids of the pruned records, so the replaying does not depend on the time
+func (f *FileSource) pruneWithJournal(kind string, before time.Time, statuses []string, journal transitionJournal) (
+	[]string, error,
+) {
+	if err := checkPruneStatuses(statuses); err != nil {
+		return nil, err
+	}
+	var err error
+	for retry := 0; retry < 3; retry++ {
+		record := &fileRecord{Op: opDeleteExperiments}
+		if kind == AuditKindPreparation {
+			record.Op = opDeletePreparations
+		}
+		err = f.append(record, func() error {
+			record.Uids = f.store.prunable(kind, before, statuses)
+			var err error
+			record.Audits, err = pruneJournal(record.Uids, f.store.lastAuditEntry(), journal)
+			return err
+		})
+		if err == nil {
+			return record.Uids, nil
+		}
+		if !errors.Is(err, errAuditConflict) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// AppendAuditEntry chains the entry to the last replayed entry, and chains it again if another process
+// appends an entry concurrently
+func (f *FileSource) AppendAuditEntry(entry *AuditEntry) error {
+	var err error
+	for retry := 0; retry < 3; retry++ {
+		record := &fileRecord{Op: opAppendAudit, Uid: entry.Uid, Audit: entry}
+		err = f.append(record, func() error {
+			chainAuditEntry(entry, f.store.lastAuditEntry())
+			return nil
+		})
+		if !errors.Is(err, errAuditConflict) {
+			return err
+		}
+	}
+	return err
+}
+
+func (f *FileSource) QueryAuditEntries() (entries []*AuditEntry, err error) {
+	err = f.query(func(store *memoryStore) error {
+		entries = store.queryAuditEntries()
+		return nil
+	})
+	return
+}
+
+// InsertEventDeliveries appends the deliveries, the ids are assigned in the replaying order, so the ids of the
+// deliveries appended by processes concurrently do not conflict
+func (f *FileSource) InsertEventDeliveries(deliveries []*EventDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return f.append(&fileRecord{Op: opInsertDeliveries, Uid: deliveries[0].Uid, Deliveries: deliveries}, nil)
+}
+
+func (f *FileSource) QueryEventDeliveries(status string, dueBefore int64) (deliveries []*EventDelivery, err error) {
+	err = f.query(func(store *memoryStore) error {
+		deliveries = store.queryDeliveries(status, dueBefore)
+		return nil
+	})
+	return
+}
+
+// ClaimEventDelivery checks the delivery before appending the claim, and the claim is checked again when replaying,
+// so only one of the claims appended by processes concurrently is applied
+func (f *FileSource) ClaimEventDelivery(id, nextAttempt, lease int64) (bool, error) {
+	record := &fileRecord{Op: opClaimDelivery, Delivery: &EventDelivery{Id: id, NextAttempt: nextAttempt}, Lease: lease}
+	err := f.append(record, func() error {
+		for _, delivery := range f.store.deliveries {
+			if delivery.Id == id && delivery.Status == DeliveryPending && delivery.NextAttempt == nextAttempt {
+				return nil
+			}
+		}
+		return errEventDeliveryClaimed
+	})
+	if errors.Is(err, errEventDeliveryClaimed) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (f *FileSource) UpdateEventDelivery(id int64, status string, attempts int, nextAttempt int64, errMsg string) error {
+	delivery := &EventDelivery{Id: id, Status: status, Attempts: attempts, NextAttempt: nextAttempt, Error: errMsg}
+	return f.append(&fileRecord{Op: opUpdateDelivery, Delivery: delivery}, nil)
+}
@@ -0,0 +1,881 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemorySource keeps the records in the process memory, it is used by the tests and the blade server
+// which does not need to keep the experiments after restarting
+type MemorySource struct {
+	lock  sync.RWMutex
+	store *memoryStore
+}
+
+func NewMemorySource() *MemorySource {
+	return &MemorySource{store: newMemoryStore()}
+}
+
+func openMemorySource(string) (SourceI, error) {
+	return NewMemorySource(), nil
+}
+
+func (m *MemorySource) InsertExperimentModel(model *ExperimentModel) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.store.insertExperiment(model)
+}
+
+func (m *MemorySource) UpdateExperimentModelByUid(uid, status, errMsg string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.store.updateExperiment(uid, nil, status, errMsg, now())
+	return nil
+}
+
+func (m *MemorySource) UpdateExperimentStatusByUid(uid string, expected []string, status, errMsg string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if idx := m.store.findExperiment(uid); idx < 0 {
+		return &StatusConflictError{Uid: uid, Expected: expected}
+	}
+	return m.store.updateExperiment(uid, expected, status, errMsg, now())
+}
+
+func (m *MemorySource) QueryExperimentModelByUid(uid string) (*ExperimentModel, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.store.queryExperimentByUid(uid), nil
+}
+
+func (m *MemorySource) QueryExperimentModels(target, action, flag, status, limit string, asc bool) ([]*ExperimentModel, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.store.queryExperiments(target, action, flag, status, nil, limit, asc)
+}
+
+func (m *MemorySource) QueryExperimentModelsByFlags(target, action, status string, flags map[string]string, limit string,
+	asc bool,
+) ([]*ExperimentModel, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.store.queryExperiments(target, action, "", status, flags, limit, asc)
+}
+
+func (m *MemorySource) QueryExperimentModelsByGroup(groupUid string) ([]*ExperimentModel, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.store.queryExperimentsByGroup(groupUid), nil
+}
+
+func (m *MemorySource) QueryExperimentModelsByCommand(command, subCommand string, flags map[string]string) ([]*ExperimentModel, error) {
+	models, err := m.QueryExperimentModelsByFlags(command, subCommand, "", flags, "", true)
+	if err != nil {
+		return make([]*ExperimentModel, 0), err
+	}
+	return models, nil
+}
+
+func (m *MemorySource) UpdateExperimentProbesByUid(uid string, probes []*Probe) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.store.updateProbes(uid, probes)
+	return nil
+}
+
+func (m *MemorySource) DeleteExperimentModelByUid(uid string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.store.deleteExperiment(uid)
+	return nil
+}
+
+func (m *MemorySource) InsertExperimentDeadline(uid string, deadline time.Time) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.store.insertDeadline(uid, deadline.Unix())
+	return nil
+}
+
+func (m *MemorySource) DeleteExperimentDeadline(uid string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.store.deleteDeadline(uid)
+	return nil
+}
+
+func (m *MemorySource) QueryExperimentDeadline(uid string) (*ExperimentDeadline, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.store.queryDeadline(uid), nil
+}
+
+func (m *MemorySource) DeferExperimentDeadline(uid string, nextRetry time.Time, errMsg string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.store.deferDeadline(uid, nextRetry.Unix(), errMsg)
+	return nil
+}
+
+func (m *MemorySource) QueryOverdueExperimentModels(deadline time.Time) ([]*ExperimentModel, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.store.queryOverdueExperiments(deadline.Unix()), nil
+}
+
+func (m *MemorySource) PruneExperimentModels(before time.Time, statuses []string) ([]string, error) {
+	return m.pruneWithJournal(AuditKindExperiment, before, statuses, nil)
+}
+
+func (m *MemorySource) InsertPreparationRecord(record *PreparationRecord) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.store.insertPreparation(record)
+}
+
+func (m *MemorySource) QueryPreparationByUid(uid string) (*PreparationRecord, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.store.queryPreparationByUid(uid), nil
+}
+
+func (m *MemorySource) QueryRunningPreByTypeAndProcess(programType string, processName, processId string) (*PreparationRecord, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.store.queryRunningPreparation(programType, processName, processId), nil
+}
+
+func (m *MemorySource) UpdatePreparationRecordByUid(uid, status, errMsg string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.store.updatePreparation(uid, now(), func(record *PreparationRecord) {
+		record.Status = status
+		record.Error = errMsg
+	})
+	return nil
+}
+
+func (m *MemorySource) UpdatePreparationPortByUid(uid, port string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.store.updatePreparation(uid, now(), func(record *PreparationRecord) {
+		record.Port = port
+	})
+	return nil
+}
+
+func (m *MemorySource) UpdatePreparationPidByUid(uid, pid string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.store.updatePreparation(uid, now(), func(record *PreparationRecord) {
+		record.Pid = pid
+	})
+	return nil
+}
+
+func (m *MemorySource) QueryPreparationRecords(target, status, action, flag, limit string, asc bool) ([]*PreparationRecord, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.store.queryPreparations(target, status, limit, asc)
+}
+
+func (m *MemorySource) PrunePreparationRecords(before time.Time, statuses []string) ([]string, error) {
+	return m.pruneWithJournal(AuditKindPreparation, before, statuses, nil)
+}
+
+func (m *MemorySource) AppendAuditEntry(entry *AuditEntry) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	chainAuditEntry(entry, m.store.lastAuditEntry())
+	return m.store.appendAuditEntry(entry)
+}
+
+func (m *MemorySource) QueryAuditEntries() ([]*AuditEntry, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.store.queryAuditEntries(), nil
+}
+
+func (m *MemorySource) InsertEventDeliveries(deliveries []*EventDelivery) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	ids := m.store.insertDeliveries(deliveries)
+	for idx, delivery := range deliveries {
+		delivery.Id = ids[idx]
+	}
+	return nil
+}
+
+func (m *MemorySource) QueryEventDeliveries(status string, dueBefore int64) ([]*EventDelivery, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.store.queryDeliveries(status, dueBefore), nil
+}
+
+func (m *MemorySource) ClaimEventDelivery(id, nextAttempt, lease int64) (bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	err := m.store.claimDelivery(id, nextAttempt, lease, now())
+	if errors.Is(err, errEventDeliveryClaimed) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (m *MemorySource) UpdateEventDelivery(id int64, status string, attempts int, nextAttempt int64, errMsg string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.store.updateDelivery(id, now(), func(delivery *EventDelivery) {
+		delivery.Status, delivery.Attempts, delivery.NextAttempt, delivery.Error = status, attempts, nextAttempt, errMsg
+	})
+	return nil
+}
+
+func (m *MemorySource) applyTransition(t *transition) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	result, err := m.store.transitionResult(t, now())
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		// the deadline of the unknown experiment is deleted
+		if t.deleted {
+			return m.store.applyTransition(t, "")
+		}
+		return nil
+	}
+	entry, deliveries, err := t.journal(t.uid, result.previous, result.experiment, result.preparation)
+	if err != nil {
+		return err
+	}
+	if err := m.store.applyTransition(t, result.updateTime); err != nil {
+		return err
+	}
+	chainAuditEntry(entry, m.store.lastAuditEntry())
+	m.store.appendJournal([]*AuditEntry{entry}, deliveries)
+	return nil
+}
+
+func (m *MemorySource) pruneWithJournal(kind string, before time.Time, statuses []string, journal transitionJournal) (
+	[]string, error,
+) {
+	if err := checkPruneStatuses(statuses); err != nil {
+		return nil, err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	uids := m.store.prunable(kind, before, statuses)
+	entries, err := pruneJournal(uids, m.store.lastAuditEntry(), journal)
+	if err != nil {
+		return nil, err
+	}
+	m.store.prune(kind, uids)
+	m.store.appendJournal(entries, nil)
+	return uids, nil
+}
+
+// pruneJournal returns the audit entries of the pruned uids chained to the last entry
+func pruneJournal(uids []string, last *AuditEntry, journal transitionJournal) ([]*AuditEntry, error) {
+	entries := make([]*AuditEntry, 0, len(uids))
+	if journal == nil {
+		return entries, nil
+	}
+	for _, uid := range uids {
+		entry, _, err := journal(uid, "", nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		chainAuditEntry(entry, last)
+		entries, last = append(entries, entry), entry
+	}
+	return entries, nil
+}
+
+func now() string {
+	return time.Now().Format(time.RFC3339Nano)
+}
+
+// memoryStore holds the records in the insertion order, which is the id order of the sqlite tables.
+// It is not thread-safe, the callers must hold the lock.
+type memoryStore struct {
+	experiments  []*ExperimentModel
+	preparations []*PreparationRecord
+	deadlines    map[string]*ExperimentDeadline
+	audits       []*AuditEntry
+	deliveries   []*EventDelivery
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		experiments:  make([]*ExperimentModel, 0),
+		preparations: make([]*PreparationRecord, 0),
+		deadlines:    make(map[string]*ExperimentDeadline),
+		audits:       make([]*AuditEntry, 0),
+		deliveries:   make([]*EventDelivery, 0),
+	}
+}
+
+// transitionResult is the status before a transition and the record after it
+type transitionResult struct {
+	previous    string
+	experiment  *ExperimentModel
+	preparation *PreparationRecord
+	updateTime  string
+}
+
+// transitionResult checks the transition and returns its result without applying it,
+// nil means the unknown record is not changed
+func (s *memoryStore) transitionResult(t *transition, updateTime string) (*transitionResult, error) {
+	result := &transitionResult{updateTime: updateTime}
+	switch {
+	case t.experiment != nil:
+		if s.findExperiment(t.uid) >= 0 {
+			return nil, fmt.Errorf("the %s experiment already exists", t.uid)
+		}
+		result.experiment = copyExperimentModel(t.experiment)
+		if t.experiment.Flags == nil {
+			result.experiment.Flags = ParseFlags(t.experiment.Flag)
+		}
+		return result, nil
+	case t.preparation != nil:
+		if s.findPreparation(t.uid) >= 0 {
+			return nil, fmt.Errorf("the %s preparation already exists", t.uid)
+		}
+		copied := *t.preparation
+		result.preparation = &copied
+		return result, nil
+	}
+	if t.kind == AuditKindExperiment {
+		if idx := s.findExperiment(t.uid); idx >= 0 {
+			result.previous = s.experiments[idx].Status
+			result.experiment = copyExperimentModel(s.experiments[idx])
+			result.experiment.Status, result.experiment.Error, result.experiment.UpdateTime = t.status, t.errMsg, updateTime
+		}
+	} else if idx := s.findPreparation(t.uid); idx >= 0 {
+		result.previous = s.preparations[idx].Status
+		copied := *s.preparations[idx]
+		copied.Status, copied.Error, copied.UpdateTime = t.status, t.errMsg, updateTime
+		result.preparation = &copied
+	}
+	if result.experiment == nil && result.preparation == nil {
+		if t.expected != nil {
+			return nil, &StatusConflictError{Uid: t.uid, Expected: t.expected}
+		}
+		return nil, nil
+	}
+	if err := checkExpectedStatus(t.uid, result.previous, t.expected); err != nil {
+		return nil, err
+	}
+	if t.deleted {
+		result.experiment, result.preparation = nil, nil
+	}
+	return result, nil
+}
+
+// applyTransition applies the transition checked by the transitionResult
+func (s *memoryStore) applyTransition(t *transition, updateTime string) error {
+	switch {
+	case t.experiment != nil:
+		return s.insertExperiment(t.experiment)
+	case t.preparation != nil:
+		return s.insertPreparation(t.preparation)
+	case t.deleted && t.kind == AuditKindExperiment:
+		s.deleteExperiment(t.uid)
+	case t.deleted:
+		s.deletePreparations([]string{t.uid})
+	case t.kind == AuditKindExperiment:
+		return s.updateExperiment(t.uid, t.expected, t.status, t.errMsg, updateTime)
+	default:
+		s.updatePreparation(t.uid, updateTime, func(record *PreparationRecord) {
+			record.Status, record.Error = t.status, t.errMsg
+		})
+	}
+	return nil
+}
+
+// checkJournal returns errAuditConflict if the entries are not chained to the last entry
+func (s *memoryStore) checkJournal(entries []*AuditEntry) error {
+	last := s.lastAuditEntry()
+	for _, entry := range entries {
+		seq, prevHash := int64(1), ""
+		if last != nil {
+			seq, prevHash = last.Seq+1, last.Hash
+		}
+		if entry.Seq != seq || entry.PrevHash != prevHash {
+			return errAuditConflict
+		}
+		last = entry
+	}
+	return nil
+}
+
+// appendJournal appends the entries checked by the checkJournal and inserts the deliveries
+func (s *memoryStore) appendJournal(entries []*AuditEntry, deliveries []*EventDelivery) {
+	for _, entry := range entries {
+		s.appendAuditEntry(entry)
+	}
+	for idx, id := range s.insertDeliveries(deliveries) {
+		deliveries[idx].Id = id
+	}
+}
+
+func (s *memoryStore) prunable(kind string, before time.Time, statuses []string) []string {
+	if kind == AuditKindExperiment {
+		return s.prunableExperiments(before, statuses)
+	}
+	return s.prunablePreparations(before, statuses)
+}
+
+func (s *memoryStore) prune(kind string, uids []string) {
+	if kind == AuditKindExperiment {
+		s.deleteExperiments(uids)
+	} else {
+		s.deletePreparations(uids)
+	}
+}
+
+func (s *memoryStore) insertExperiment(model *ExperimentModel) error {
+	if s.findExperiment(model.Uid) >= 0 {
+		return fmt.Errorf("the %s experiment already exists", model.Uid)
+	}
+	copied := copyExperimentModel(model)
+	if model.Flags == nil {
+		copied.Flags = ParseFlags(model.Flag)
+	}
+	s.experiments = append(s.experiments, copied)
+	return nil
+}
+
+// updateExperiment changes the status if the current status is one of the expected, the unknown uid is ignored
+func (s *memoryStore) updateExperiment(uid string, expected []string, status, errMsg, updateTime string) error {
+	idx := s.findExperiment(uid)
+	if idx < 0 {
+		return nil
+	}
+	model := s.experiments[idx]
+	if err := checkExpectedStatus(uid, model.Status, expected); err != nil {
+		return err
+	}
+	model.Status = status
+	model.Error = errMsg
+	model.UpdateTime = updateTime
+	return nil
+}
+
+func (s *memoryStore) updateProbes(uid string, probes []*Probe) {
+	if idx := s.findExperiment(uid); idx >= 0 {
+		s.experiments[idx].Probes = copyProbes(probes)
+	}
+}
+
+func (s *memoryStore) deleteExperiment(uid string) {
+	if idx := s.findExperiment(uid); idx >= 0 {
+		s.experiments = append(s.experiments[:idx], s.experiments[idx+1:]...)
+	}
+	delete(s.deadlines, uid)
+}
+
+func (s *memoryStore) deleteExperiments(uids []string) {
+	for _, uid := range uids {
+		s.deleteExperiment(uid)
+	}
+}
+
+// prunableExperiments returns the uids of the experiments of the statuses which are not updated after the time
+func (s *memoryStore) prunableExperiments(before time.Time, statuses []string) []string {
+	uids := make([]string, 0)
+	for _, model := range s.experiments {
+		if containsStatus(statuses, model.Status) && updatedBefore(model.CreateTime, model.UpdateTime, before) {
+			uids = append(uids, model.Uid)
+		}
+	}
+	return uids
+}
+
+func (s *memoryStore) insertDeadline(uid string, deadline int64) {
+	s.deadlines[uid] = &ExperimentDeadline{Uid: uid, Deadline: deadline}
+}
+
+func (s *memoryStore) queryDeadline(uid string) *ExperimentDeadline {
+	deadline, ok := s.deadlines[uid]
+	if !ok {
+		return nil
+	}
+	copied := *deadline
+	return &copied
+}
+
+func (s *memoryStore) deferDeadline(uid string, nextRetry int64, errMsg string) {
+	deadline, ok := s.deadlines[uid]
+	if !ok {
+		return
+	}
+	deadline.Attempts++
+	deadline.LastError, deadline.NextRetry = errMsg, nextRetry
+}
+
+func (s *memoryStore) deleteDeadline(uid string) {
+	delete(s.deadlines, uid)
+}
+
+func (s *memoryStore) findExperiment(uid string) int {
+	for idx, model := range s.experiments {
+		if model.Uid == uid {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (s *memoryStore) queryExperimentByUid(uid string) *ExperimentModel {
+	if idx := s.findExperiment(uid); idx >= 0 {
+		return copyExperimentModel(s.experiments[idx])
+	}
+	return nil
+}
+
+func (s *memoryStore) queryExperiments(target, action, flag, status string, flags map[string]string, limit string,
+	asc bool,
+) ([]*ExperimentModel, error) {
+	offset, count, err := parseLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	if status != "" {
+		status = UpperFirst(status)
+	}
+	matched := make([]*ExperimentModel, 0)
+	for _, model := range s.experiments {
+		if target != "" && model.Command != target {
+			continue
+		}
+		if action != "" && model.SubCommand != action {
+			continue
+		}
+		// the same as the case-insensitive sqlite like
+		if flag != "" && !strings.Contains(strings.ToLower(model.Flag), strings.ToLower(flag)) {
+			continue
+		}
+		if status != "" && model.Status != status {
+			continue
+		}
+		if !matchFlags(model.Flags, flags) {
+			continue
+		}
+		matched = append(matched, model)
+	}
+	if !asc {
+		reverse(len(matched), func(i, j int) { matched[i], matched[j] = matched[j], matched[i] })
+	}
+	models := make([]*ExperimentModel, 0)
+	for idx, model := range matched {
+		if idx < offset || (count >= 0 && idx >= offset+count) {
+			continue
+		}
+		models = append(models, copyExperimentModel(model))
+	}
+	return models, nil
+}
+
+func (s *memoryStore) queryExperimentsByGroup(groupUid string) []*ExperimentModel {
+	models := make([]*ExperimentModel, 0)
+	for _, model := range s.experiments {
+		if model.GroupUid == groupUid {
+			models = append(models, copyExperimentModel(model))
+		}
+	}
+	return models
+}
+
+func (s *memoryStore) queryOverdueExperiments(deadline int64) []*ExperimentModel {
+	models := make([]*ExperimentModel, 0)
+	for _, model := range s.experiments {
+		expDeadline, ok := s.deadlines[model.Uid]
+		if !ok || expDeadline.Deadline > deadline || expDeadline.NextRetry > deadline {
+			continue
+		}
+		if model.Status != "Created" && model.Status != "Success" {
+			continue
+		}
+		models = append(models, copyExperimentModel(model))
+	}
+	sort.SliceStable(models, func(i, j int) bool {
+		return s.deadlines[models[i].Uid].Deadline < s.deadlines[models[j].Uid].Deadline
+	})
+	return models
+}
+
+func (s *memoryStore) insertPreparation(record *PreparationRecord) error {
+	if s.findPreparation(record.Uid) >= 0 {
+		return fmt.Errorf("the %s preparation already exists", record.Uid)
+	}
+	copied := *record
+	s.preparations = append(s.preparations, &copied)
+	return nil
+}
+
+func (s *memoryStore) updatePreparation(uid, updateTime string, update func(record *PreparationRecord)) {
+	if idx := s.findPreparation(uid); idx >= 0 {
+		record := s.preparations[idx]
+		update(record)
+		record.UpdateTime = updateTime
+	}
+}
+
+func (s *memoryStore) deletePreparations(uids []string) {
+	for _, uid := range uids {
+		if idx := s.findPreparation(uid); idx >= 0 {
+			s.preparations = append(s.preparations[:idx], s.preparations[idx+1:]...)
+		}
+	}
+}
+
+// prunablePreparations returns the uids of the records of the statuses which are not updated after the time
+func (s *memoryStore) prunablePreparations(before time.Time, statuses []string) []string {
+	uids := make([]string, 0)
+	for _, record := range s.preparations {
+		if containsStatus(statuses, record.Status) && updatedBefore(record.CreateTime, record.UpdateTime, before) {
+			uids = append(uids, record.Uid)
+		}
+	}
+	return uids
+}
+
+func (s *memoryStore) findPreparation(uid string) int {
+	for idx, record := range s.preparations {
+		if record.Uid == uid {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (s *memoryStore) queryPreparationByUid(uid string) *PreparationRecord {
+	if idx := s.findPreparation(uid); idx >= 0 {
+		copied := *s.preparations[idx]
+		return &copied
+	}
+	return nil
+}
+
+func (s *memoryStore) queryRunningPreparation(programType, processName, processId string) *PreparationRecord {
+	for _, record := range s.preparations {
+		if record.ProgramType != programType || record.Status != "Running" {
+			continue
+		}
+		if processId != "" && record.Pid != processId {
+			continue
+		}
+		if processName != "" && record.Process != processName {
+			continue
+		}
+		copied := *record
+		return &copied
+	}
+	return nil
+}
+
+// queryPreparations filters the records by the program type and status, the preparation has no action and flag
+func (s *memoryStore) queryPreparations(target, status, limit string, asc bool) ([]*PreparationRecord, error) {
+	offset, count, err := parseLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	if status != "" {
+		status = UpperFirst(status)
+	}
+	matched := make([]*PreparationRecord, 0)
+	for _, record := range s.preparations {
+		if target != "" && record.ProgramType != target {
+			continue
+		}
+		if status != "" && record.Status != status {
+			continue
+		}
+		matched = append(matched, record)
+	}
+	if !asc {
+		reverse(len(matched), func(i, j int) { matched[i], matched[j] = matched[j], matched[i] })
+	}
+	records := make([]*PreparationRecord, 0)
+	for idx, record := range matched {
+		if idx < offset || (count >= 0 && idx >= offset+count) {
+			continue
+		}
+		copied := *record
+		records = append(records, &copied)
+	}
+	return records, nil
+}
+
+// parseLimit parses the "count" or "offset,count" limit, the negative count means no limit like sqlite
+func (s *memoryStore) lastAuditEntry() *AuditEntry {
+	if len(s.audits) == 0 {
+		return nil
+	}
+	return s.audits[len(s.audits)-1]
+}
+
+// appendAuditEntry appends the entry only if it is chained to the last entry
+func (s *memoryStore) appendAuditEntry(entry *AuditEntry) error {
+	seq, prevHash := int64(1), ""
+	if last := s.lastAuditEntry(); last != nil {
+		seq, prevHash = last.Seq+1, last.Hash
+	}
+	if entry.Seq != seq || entry.PrevHash != prevHash {
+		return errAuditConflict
+	}
+	copied := *entry
+	s.audits = append(s.audits, &copied)
+	return nil
+}
+
+func (s *memoryStore) queryAuditEntries() []*AuditEntry {
+	entries := make([]*AuditEntry, 0, len(s.audits))
+	for _, entry := range s.audits {
+		copied := *entry
+		entries = append(entries, &copied)
+	}
+	return entries
+}
+
+// insertDeliveries appends the deliveries with the next ids and returns the ids
+func (s *memoryStore) insertDeliveries(deliveries []*EventDelivery) []int64 {
+	ids := make([]int64, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		copied := *delivery
+		copied.Id = 1
+		if len(s.deliveries) > 0 {
+			copied.Id = s.deliveries[len(s.deliveries)-1].Id + 1
+		}
+		s.deliveries = append(s.deliveries, &copied)
+		ids = append(ids, copied.Id)
+	}
+	return ids
+}
+
+func (s *memoryStore) queryDeliveries(status string, dueBefore int64) []*EventDelivery {
+	deliveries := make([]*EventDelivery, 0)
+	for _, delivery := range s.deliveries {
+		if (status != "" && delivery.Status != status) || (dueBefore > 0 && delivery.NextAttempt > dueBefore) {
+			continue
+		}
+		copied := *delivery
+		deliveries = append(deliveries, &copied)
+	}
+	return deliveries
+}
+
+// claimDelivery changes the next attempt of the Pending delivery to the lease if the next attempt is the expected one
+func (s *memoryStore) claimDelivery(id, nextAttempt, lease int64, updateTime string) error {
+	for _, delivery := range s.deliveries {
+		if delivery.Id != id {
+			continue
+		}
+		if delivery.Status != DeliveryPending || delivery.NextAttempt != nextAttempt {
+			return errEventDeliveryClaimed
+		}
+		delivery.NextAttempt, delivery.UpdateTime = lease, updateTime
+		return nil
+	}
+	return errEventDeliveryClaimed
+}
+
+// updateDelivery changes the delivery by the update function, the unknown id is ignored
+func (s *memoryStore) updateDelivery(id int64, updateTime string, update func(delivery *EventDelivery)) {
+	for _, delivery := range s.deliveries {
+		if delivery.Id == id {
+			update(delivery)
+			delivery.UpdateTime = updateTime
+			return
+		}
+	}
+}
+
+func parseLimit(limit string) (offset, count int, err error) {
+	if limit == "" {
+		return 0, -1, nil
+	}
+	values := strings.Split(limit, ",")
+	countValue := values[0]
+	if len(values) > 1 {
+		if offset, err = strconv.Atoi(strings.TrimSpace(values[0])); err != nil {
+			return 0, 0, fmt.Errorf("illegal limit %s, %s", limit, err)
+		}
+		countValue = values[1]
+	}
+	if count, err = strconv.Atoi(strings.TrimSpace(countValue)); err != nil {
+		return 0, 0, fmt.Errorf("illegal limit %s, %s", limit, err)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset, count, nil
+}
+
+func reverse(length int, swap func(i, j int)) {
+	for i, j := 0, length-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
+
+func copyExperimentModel(model *ExperimentModel) *ExperimentModel {
+	copied := *model
+	copied.Labels = copyMap(model.Labels)
+	copied.Flags = copyMap(model.Flags)
+	copied.Probes = copyProbes(model.Probes)
+	return &copied
+}
+
+// copyProbes returns nil if there is no probe, the same as copyMap
+func copyProbes(probes []*Probe) []*Probe {
+	if len(probes) == 0 {
+		return nil
+	}
+	copied := make([]*Probe, 0, len(probes))
+	for _, probe := range probes {
+		probeCopy := *probe
+		for _, result := range []**ProbeResult{&probeCopy.Before, &probeCopy.During, &probeCopy.After} {
+			if *result != nil {
+				resultCopy := **result
+				*result = &resultCopy
+			}
+		}
+		copied = append(copied, &probeCopy)
+	}
+	return copied
+}
+
+// copyMap returns nil for the empty map, the same as the sqlite source which does not store the empty map
+func copyMap(values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	copied := make(map[string]string, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+	return copied
+}
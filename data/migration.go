@@ -0,0 +1,267 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Migration upgrades the schema of the data file from the previous version to the Version,
+// the version is stored in PRAGMA [database.]user_version.
+type Migration struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	// migrate executes in a transaction with the user_version update. The data files created before the migrations
+	// are versioned may contain part of the schema, so the statements must check it first.
+	migrate func(tx *sql.Tx) error
+}
+
+// migrations are ordered by version, a new schema change must be appended with the next version
+var migrations = []Migration{
+	{1, "create the experiment and preparation tables, add the pid column to the preparation table", migrateV1},
+	{2, "add the group_uid and labels columns to the experiment table", migrateV2},
+	{3, "create the experiment_deadline table", migrateV3},
+	{4, "add the flags column to the experiment table and parse the flags of the existing experiments", migrateV4},
+	{5, "create the append-only audit_log table", migrateV5},
+	{6, "add the probes column to the experiment table", migrateV6},
+	{7, "create the event_outbox table", migrateV7},
+}
+
+// UserVersion is the latest schema version supported by the binary
+var UserVersion = migrations[len(migrations)-1].Version
+
+func migrateV1(tx *sql.Tx) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS experiment (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	uid VARCHAR(32) UNIQUE,
+	command VARCHAR NOT NULL,
+	sub_command VARCHAR,
+	flag VARCHAR,
+	status VARCHAR,
+	error VARCHAR,
+	create_time VARCHAR,
+	update_time VARCHAR
+)`,
+		`CREATE INDEX IF NOT EXISTS exp_uid_uidx ON experiment (uid)`,
+		`CREATE INDEX IF NOT EXISTS exp_command_idx ON experiment (command)`,
+		`CREATE INDEX IF NOT EXISTS exp_status_idx ON experiment (status)`,
+		`CREATE TABLE IF NOT EXISTS preparation (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	uid VARCHAR(32) UNIQUE,
+	program_type       VARCHAR NOT NULL,
+	process    VARCHAR,
+	port       VARCHAR,
+	status     VARCHAR,
+    error 	   VARCHAR,
+	create_time VARCHAR,
+	update_time VARCHAR,
+	pid 	   VARCHAR
+)`,
+		`CREATE INDEX IF NOT EXISTS pre_uid_uidx ON preparation (uid)`,
+		`CREATE INDEX IF NOT EXISTS pre_status_idx ON preparation (status)`,
+		`CREATE INDEX IF NOT EXISTS pre_type_process_idx ON preparation (program_type, process)`,
+	}
+	if err := execStatements(tx, statements); err != nil {
+		return err
+	}
+	// the preparation table created by the older version has no pid column
+	return addColumnIfNotExists(tx, "preparation", "pid", `ALTER TABLE preparation ADD COLUMN pid VARCHAR DEFAULT ""`)
+}
+
+func migrateV2(tx *sql.Tx) error {
+	if err := addColumnIfNotExists(tx, "experiment", "group_uid",
+		`ALTER TABLE experiment ADD COLUMN group_uid VARCHAR DEFAULT ""`); err != nil {
+		return err
+	}
+	if err := addColumnIfNotExists(tx, "experiment", "labels",
+		`ALTER TABLE experiment ADD COLUMN labels VARCHAR DEFAULT ""`); err != nil {
+		return err
+	}
+	return execStatements(tx, []string{`CREATE INDEX IF NOT EXISTS exp_group_uid_idx ON experiment (group_uid)`})
+}
+
+func migrateV3(tx *sql.Tx) error {
+	// the deadline and next_retry are unix seconds, the attempts are the failed destroying of the overdue experiment
+	return execStatements(tx, []string{
+		`CREATE TABLE IF NOT EXISTS experiment_deadline (
+	uid VARCHAR(32) PRIMARY KEY,
+	deadline INTEGER NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error VARCHAR NOT NULL DEFAULT '',
+	next_retry INTEGER NOT NULL DEFAULT 0,
+	create_time VARCHAR
+)`,
+		`CREATE INDEX IF NOT EXISTS exp_deadline_idx ON experiment_deadline (deadline)`,
+	})
+}
+
+func migrateV4(tx *sql.Tx) error {
+	if err := addColumnIfNotExists(tx, "experiment", "flags",
+		`ALTER TABLE experiment ADD COLUMN flags VARCHAR DEFAULT ""`); err != nil {
+		return err
+	}
+	rows, err := tx.Query(`SELECT uid, flag FROM experiment WHERE flag != "" AND (flags IS NULL OR flags = "")`)
+	if err != nil {
+		return err
+	}
+	experimentFlags := make(map[string]string)
+	for rows.Next() {
+		var uid, flag string
+		if err := rows.Scan(&uid, &flag); err != nil {
+			rows.Close()
+			return err
+		}
+		flags, err := marshalMap(ParseFlags(flag))
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		experimentFlags[uid] = flags
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for uid, flags := range experimentFlags {
+		if _, err := tx.Exec(`UPDATE experiment SET flags = ? WHERE uid = ?`, flags, uid); err != nil {
+			return fmt.Errorf("update the flags of %s experiment err, %s", uid, err)
+		}
+	}
+	return nil
+}
+
+func migrateV5(tx *sql.Tx) error {
+	return execStatements(tx, []string{
+		`CREATE TABLE IF NOT EXISTS audit_log (
+	seq INTEGER PRIMARY KEY,
+	time VARCHAR,
+	kind VARCHAR,
+	uid VARCHAR(32),
+	action VARCHAR,
+	previous_status VARCHAR,
+	status VARCHAR,
+	os_user VARCHAR,
+	command_line VARCHAR,
+	source VARCHAR,
+	prev_hash VARCHAR,
+	hash VARCHAR NOT NULL
+)`,
+		`CREATE INDEX IF NOT EXISTS audit_uid_idx ON audit_log (uid)`,
+		`CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN SELECT RAISE(ABORT, 'the audit log is append-only'); END`,
+		`CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN SELECT RAISE(ABORT, 'the audit log is append-only'); END`,
+	})
+}
+
+func migrateV6(tx *sql.Tx) error {
+	return addColumnIfNotExists(tx, "experiment", "probes",
+		`ALTER TABLE experiment ADD COLUMN probes VARCHAR DEFAULT ""`)
+}
+
+func migrateV7(tx *sql.Tx) error {
+	// the next_attempt is unix seconds
+	return execStatements(tx, []string{
+		`CREATE TABLE IF NOT EXISTS event_outbox (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	event_id VARCHAR NOT NULL,
+	event_type VARCHAR,
+	uid VARCHAR(32),
+	sink VARCHAR NOT NULL,
+	payload VARCHAR,
+	status VARCHAR,
+	attempts INTEGER DEFAULT 0,
+	next_attempt INTEGER DEFAULT 0,
+	error VARCHAR DEFAULT "",
+	create_time VARCHAR,
+	update_time VARCHAR
+)`,
+		`CREATE INDEX IF NOT EXISTS event_status_next_attempt_idx ON event_outbox (status, next_attempt)`,
+	})
+}
+
+func execStatements(tx *sql.Tx, statements []string) error {
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("execute %s sql err, %s", statement, err)
+		}
+	}
+	return nil
+}
+
+func addColumnIfNotExists(tx *sql.Tx, table, column, alterSql string) error {
+	exists, err := columnExists(tx, table, column)
+	if err != nil || exists {
+		return err
+	}
+	return execStatements(tx, []string{alterSql})
+}
+
+// PendingMigrations returns the current schema version and the migrations not applied to the data file.
+// It returns error if the data file is created by a newer binary.
+func (s *Source) PendingMigrations() (int, []Migration, error) {
+	version, err := s.GetUserVersion()
+	if err != nil {
+		return 0, nil, err
+	}
+	if version > UserVersion {
+		return version, nil, fmt.Errorf("the data file version %d is newer than the version %d supported by blade, "+
+			"please upgrade blade or use another data file by CHAOSBLADE_DATAFILE_PATH", version, UserVersion)
+	}
+	pending := make([]Migration, 0)
+	for _, migration := range migrations {
+		if migration.Version > version {
+			pending = append(pending, migration)
+		}
+	}
+	return version, pending, nil
+}
+
+// Migrate applies the pending migrations in order, every migration is committed with its version in a transaction,
+// so a failed migration leaves the data file at the previous version.
+func (s *Source) Migrate() ([]Migration, error) {
+	_, pending, err := s.PendingMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied := make([]Migration, 0, len(pending))
+	for _, migration := range pending {
+		if err := s.applyMigration(migration); err != nil {
+			return applied, fmt.Errorf("migrate the data file to version %d err, %s", migration.Version, err)
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+func (s *Source) applyMigration(migration Migration) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	if err := migration.migrate(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version=%d", migration.Version)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func openTestSource(t *testing.T) *Source {
+	database, err := sql.Open("sqlite", filepath.Join(t.TempDir(), dataFile))
+	if err != nil {
+		t.Fatalf("open data file failed, %v", err)
+	}
+	src := &Source{DB: database}
+	t.Cleanup(src.Close)
+	return src
+}
+
+func TestMigrations_ordered(t *testing.T) {
+	for idx, migration := range migrations {
+		if migration.Version != idx+1 {
+			t.Errorf("unexpected version of migration %d: %d", idx, migration.Version)
+		}
+		if migration.Description == "" || migration.migrate == nil {
+			t.Errorf("the migration %d has no description or migrate function", migration.Version)
+		}
+	}
+}
+
+func TestSource_Migrate(t *testing.T) {
+	src := openTestSource(t)
+	applied, err := src.Migrate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(applied) != len(migrations) {
+		t.Errorf("unexpected applied migrations: %d, expected: %d", len(applied), len(migrations))
+	}
+	version, _ := src.GetUserVersion()
+	if version != UserVersion {
+		t.Errorf("unexpected version: %d, expected: %d", version, UserVersion)
+	}
+	applied, err = src.Migrate()
+	if err != nil || len(applied) != 0 {
+		t.Errorf("unexpected result of migrating again: %v, %v", applied, err)
+	}
+}
+
+func TestSource_Migrate_legacyDataFile(t *testing.T) {
+	src := openTestSource(t)
+	// the tables created by the version without user_version
+	for _, statement := range []string{
+		`CREATE TABLE experiment (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	uid VARCHAR(32) UNIQUE,
+	command VARCHAR NOT NULL,
+	sub_command VARCHAR,
+	flag VARCHAR,
+	status VARCHAR,
+	error VARCHAR,
+	create_time VARCHAR,
+	update_time VARCHAR
+)`,
+		`CREATE INDEX exp_uid_uidx ON experiment (uid)`,
+		`CREATE TABLE preparation (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	uid VARCHAR(32) UNIQUE,
+	program_type VARCHAR NOT NULL,
+	process VARCHAR,
+	port VARCHAR,
+	status VARCHAR,
+	error VARCHAR,
+	create_time VARCHAR,
+	update_time VARCHAR
+)`,
+		`INSERT INTO experiment (uid, command, sub_command, flag, status, error, create_time, update_time)
+	VALUES ('7c1f7afc281482c8', 'cpu', 'fullload', ' --cpu-percent=60 --timeout=30', 'Success', '', '', '')`,
+		`INSERT INTO preparation (uid, program_type, process, port, status, error, create_time, update_time)
+	VALUES ('9b2e3c4d5f6a7b8c', 'jvm', 'tomcat', '8080', 'Running', '', '', '')`,
+	} {
+		if _, err := src.DB.Exec(statement); err != nil {
+			t.Fatalf("prepare the legacy data file failed, %v", err)
+		}
+	}
+	version, pending, err := src.PendingMigrations()
+	if err != nil || version != 0 || len(pending) != len(migrations) {
+		t.Fatalf("unexpected pending migrations: %d, %v, %v", version, pending, err)
+	}
+	if _, err := src.Migrate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	model, err := src.QueryExperimentModelByUid("7c1f7afc281482c8")
+	if err != nil || model == nil || model.Command != "cpu" || model.GroupUid != "" {
+		t.Errorf("unexpected experiment: %+v, err: %v", model, err)
+	}
+	if expect := map[string]string{"cpu-percent": "60", "timeout": "30"}; model != nil && !reflect.DeepEqual(model.Flags, expect) {
+		t.Errorf("unexpected flags of the legacy experiment: %v", model.Flags)
+	}
+	record, err := src.QueryPreparationByUid("9b2e3c4d5f6a7b8c")
+	if err != nil || record == nil || record.Process != "tomcat" || record.Pid != "" {
+		t.Errorf("unexpected preparation: %+v, err: %v", record, err)
+	}
+}
+
+func TestSource_PendingMigrations_newerDataFile(t *testing.T) {
+	src := openTestSource(t)
+	if _, err := src.DB.Exec("PRAGMA user_version=1"); err != nil {
+		t.Fatalf("set user_version failed, %v", err)
+	}
+	version, pending, err := src.PendingMigrations()
+	if err != nil || version != 1 || len(pending) != len(migrations)-1 || pending[0].Version != 2 {
+		t.Errorf("unexpected pending migrations: %d, %v, %v", version, pending, err)
+	}
+	if _, err := src.DB.Exec("PRAGMA user_version=99"); err != nil {
+		t.Fatalf("set user_version failed, %v", err)
+	}
+	if _, err := src.Migrate(); err == nil {
+		t.Errorf("expected error for the newer data file")
+	}
+}
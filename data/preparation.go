@@ -17,13 +17,10 @@
 package data
 
 import (
-	"context"
 	"database/sql"
 	"fmt"
 	"strings"
 	"time"
-
-	"github.com/chaosblade-io/chaosblade-spec-go/log"
 )
 
 type PreparationRecord struct {
@@ -39,15 +36,6 @@ type PreparationRecord struct {
 }
 
 type PreparationSource interface {
-	// CheckAndInitPreTable
-	CheckAndInitPreTable()
-
-	// InitPreparationTable when first executed
-	InitPreparationTable() error
-
-	// PreparationTableExists return true if preparation exists, otherwise return false or error if execute sql exception
-	PreparationTableExists() (bool, error)
-
 	// InsertPreparationRecord
 	InsertPreparationRecord(record *PreparationRecord) error
 
@@ -68,32 +56,10 @@ type PreparationSource interface {
 
 	// QueryPreparationRecords
 	QueryPreparationRecords(target, status, action, flag, limit string, asc bool) ([]*PreparationRecord, error)
-}
-
-// UserVersion PRAGMA [database.]user_version
-const UserVersion = 1
-
-// addPidColumn sql
-const addPidColumn = `ALTER TABLE preparation ADD COLUMN pid VARCHAR DEFAULT ""`
 
-// preparationTableDDL
-const preparationTableDDL = `CREATE TABLE IF NOT EXISTS preparation (
-	id INTEGER PRIMARY KEY AUTOINCREMENT,
-	uid VARCHAR(32) UNIQUE,
-	program_type       VARCHAR NOT NULL,
-	process    VARCHAR,
-	port       VARCHAR,
-	status     VARCHAR,
-    error 	   VARCHAR,
-	create_time VARCHAR,
-	update_time VARCHAR,
-	pid 	   VARCHAR
-)`
-
-var preIndexDDL = []string{
-	`CREATE INDEX pre_uid_uidx ON preparation (uid)`,
-	`CREATE INDEX pre_status_idx ON preparation (status)`,
-	`CREATE INDEX pre_type_process_idx ON preparation (program_type, process)`,
+	// PrunePreparationRecords deletes the records of the statuses which are not updated after the time,
+	// and returns the deleted uids
+	PrunePreparationRecords(before time.Time, statuses []string) ([]string, error)
 }
 
 var insertPreDML = `INSERT INTO
@@ -101,99 +67,12 @@ var insertPreDML = `INSERT INTO
 	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
-func (s *Source) CheckAndInitPreTable() {
-	// check user_version
-	version, err := s.GetUserVersion()
-	ctx := context.Background()
-	if err != nil {
-		log.Fatalf(ctx, "%s", err.Error())
-		// log.Error(err, "GetUserVersion err")
-		// os.Exit(1)
-	}
-	// return directly if equal the current UserVersion
-	if version == UserVersion {
-		return
-	}
-	// check the table exists or not
-	exists, err := s.PreparationTableExists()
-	if err != nil {
-		log.Fatalf(ctx, "%s", err.Error())
-		// log.Error(err, "PreparationTableExists err")
-		// os.Exit(1)
-	}
-	if exists {
-		// check if pid column exists before adding it
-		pidColumnExists, err := s.ColumnExists("preparation", "pid")
-		if err != nil {
-			log.Fatalf(ctx, "%s", err.Error())
-		}
-		if !pidColumnExists {
-			// execute alter sql if column doesn't exist
-			err := s.AlterPreparationTable(addPidColumn)
-			if err != nil {
-				log.Fatalf(ctx, "%s", err.Error())
-				// log.Error(err, "AlterPreparationTable err", "addPidColumn", addPidColumn)
-				// os.Exit(1)
-			}
-		}
-	} else {
-		// execute create table
-		err = s.InitPreparationTable()
-		if err != nil {
-			log.Fatalf(ctx, "%s", err.Error())
-			// log.Error(err, "InitPreparationTable err")
-			// os.Exit(1)
-		}
-	}
-	// update userVersion to new
-	err = s.UpdateUserVersion(UserVersion)
-	if err != nil {
-		log.Fatalf(ctx, "%s", err.Error())
-		// log.Error(err, "UpdateUserVersion err", "UserVersion", UserVersion)
-		// os.Exit(1)
-	}
-}
-
-func (s *Source) InitPreparationTable() error {
-	_, err := s.DB.Exec(preparationTableDDL)
-	if err != nil {
-		return fmt.Errorf("create preparation table err, %s", err)
-	}
-	for _, sql := range preIndexDDL {
-		s.DB.Exec(sql)
-	}
-	return nil
-}
-
-func (s *Source) AlterPreparationTable(alterSql string) error {
-	_, err := s.DB.Exec(alterSql)
-	if err != nil {
-		return fmt.Errorf("execute %s sql err, %s", alterSql, err)
-	}
-	return nil
-}
-
-func (s *Source) PreparationTableExists() (bool, error) {
-	stmt, err := s.DB.Prepare(tableExistsDQL)
-	if err != nil {
-		return false, fmt.Errorf("select preparation table exists err when invoke db prepare, %s", err)
-	}
-	defer stmt.Close()
-	rows, err := stmt.Query("preparation")
-	if err != nil {
-		return false, fmt.Errorf("select preparation table exists or not err, %s", err)
-	}
-	defer rows.Close()
-	var c int
-	if rows.Next() {
-		rows.Scan(&c)
-	}
-
-	return c != 0, nil
+func (s *Source) InsertPreparationRecord(record *PreparationRecord) error {
+	return insertPreparationRecord(s.DB, record)
 }
 
-func (s *Source) InsertPreparationRecord(record *PreparationRecord) error {
-	stmt, err := s.DB.Prepare(insertPreDML)
+func insertPreparationRecord(q querier, record *PreparationRecord) error {
+	stmt, err := q.Prepare(insertPreDML)
 	if err != nil {
 		return err
 	}
@@ -216,7 +95,11 @@ func (s *Source) InsertPreparationRecord(record *PreparationRecord) error {
 }
 
 func (s *Source) QueryPreparationByUid(uid string) (*PreparationRecord, error) {
-	stmt, err := s.DB.Prepare(`SELECT * FROM preparation WHERE uid = ?`)
+	return queryPreparationByUid(s.DB, uid)
+}
+
+func queryPreparationByUid(q querier, uid string) (*PreparationRecord, error) {
+	stmt, err := q.Prepare(`SELECT * FROM preparation WHERE uid = ?`)
 	if err != nil {
 		return nil, err
 	}
@@ -297,7 +180,7 @@ func getPreparationRecordFrom(rows *sql.Rows) ([]*PreparationRecord, error) {
 		}
 		records = append(records, record)
 	}
-	return records, nil
+	return records, rows.Err()
 }
 
 func (s *Source) UpdatePreparationRecordByUid(uid, status, errMsg string) error {
@@ -397,3 +280,7 @@ func (s *Source) QueryPreparationRecords(target, status, action, flag, limit str
 	defer rows.Close()
 	return getPreparationRecordFrom(rows)
 }
+
+func (s *Source) PrunePreparationRecords(before time.Time, statuses []string) ([]string, error) {
+	return s.pruneWithJournal(AuditKindPreparation, before, statuses, nil)
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// RetentionEnv enables the automatic pruning when the source is opened, the value is the age like 30d or 720h
+	RetentionEnv = "CHAOSBLADE_RETENTION"
+	// RetentionStatusEnv is the comma separated statuses pruned automatically, DefaultPruneStatuses by default
+	RetentionStatusEnv = "CHAOSBLADE_RETENTION_STATUS"
+)
+
+// DefaultPruneStatuses are the statuses of the finished experiments and preparations
+var DefaultPruneStatuses = []string{"Destroyed", "Expired", "Aborted", "Error", "Revoked"}
+
+// protectedStatuses are the statuses of the experiments and preparations which take effect or are being created
+var protectedStatuses = []string{"Created", "Success", "Running"}
+
+// RetentionPolicy prunes the records of the statuses which are not updated in the age
+type RetentionPolicy struct {
+	OlderThan time.Duration
+	Statuses  []string
+}
+
+// PruneResult contains the uids of the pruned records
+type PruneResult struct {
+	Experiments  []string `json:"experiments"`
+	Preparations []string `json:"preparations"`
+	Vacuumed     bool     `json:"vacuumed"`
+}
+
+// vacuumer is implemented by the source which can reclaim the space of the deleted records
+type vacuumer interface {
+	Vacuum() error
+}
+
+// ParseAge parses the age with the d suffix for days, such as 30d, or the time.ParseDuration format
+func ParseAge(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if days, found := strings.CutSuffix(value, "d"); found {
+		count, err := strconv.Atoi(days)
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("illegal age %s, the format is like 30d or 720h", value)
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("illegal age %s, the format is like 30d or 720h", value)
+	}
+	return age, nil
+}
+
+// ParseStatuses parses the comma separated statuses, the statuses Created, Success and Running are refused
+func ParseStatuses(value string) ([]string, error) {
+	statuses := make([]string, 0)
+	for _, status := range strings.Split(value, ",") {
+		status = strings.TrimSpace(status)
+		if status == "" {
+			continue
+		}
+		statuses = append(statuses, UpperFirst(status))
+	}
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("the statuses are empty")
+	}
+	return statuses, checkPruneStatuses(statuses)
+}
+
+func checkPruneStatuses(statuses []string) error {
+	for _, status := range statuses {
+		for _, protected := range protectedStatuses {
+			if status == protected {
+				return fmt.Errorf("the %s records can not be pruned", status)
+			}
+		}
+	}
+	return nil
+}
+
+// Prune deletes the experiments and preparations of the policy statuses which are not updated after now - OlderThan,
+// and reclaims the space if any record is deleted
+func Prune(src SourceI, policy *RetentionPolicy, now time.Time) (*PruneResult, error) {
+	if err := checkPruneStatuses(policy.Statuses); err != nil {
+		return nil, err
+	}
+	before := now.Add(-policy.OlderThan)
+	experiments, err := src.PruneExperimentModels(before, policy.Statuses)
+	if err != nil {
+		return nil, fmt.Errorf("prune experiments err, %s", err)
+	}
+	result := &PruneResult{Experiments: experiments}
+	preparations, err := src.PrunePreparationRecords(before, policy.Statuses)
+	if err != nil {
+		return result, fmt.Errorf("prune preparations err, %s", err)
+	}
+	result.Preparations = preparations
+	if v, ok := unwrapSource(src).(vacuumer); ok && len(experiments)+len(preparations) > 0 {
+		if err := v.Vacuum(); err != nil {
+			return result, fmt.Errorf("vacuum err, %s", err)
+		}
+		result.Vacuumed = true
+	}
+	return result, nil
+}
+
+// retentionPolicyFromEnv returns the policy configured by the environment variables, or nil if not configured
+func retentionPolicyFromEnv() (*RetentionPolicy, error) {
+	age := os.Getenv(RetentionEnv)
+	if age == "" {
+		return nil, nil
+	}
+	olderThan, err := ParseAge(age)
+	if err != nil {
+		return nil, fmt.Errorf("illegal %s value, %s", RetentionEnv, err)
+	}
+	statuses := DefaultPruneStatuses
+	if value := os.Getenv(RetentionStatusEnv); value != "" {
+		if statuses, err = ParseStatuses(value); err != nil {
+			return nil, fmt.Errorf("illegal %s value, %s", RetentionStatusEnv, err)
+		}
+	}
+	return &RetentionPolicy{OlderThan: olderThan, Statuses: statuses}, nil
+}
+
+// updatedBefore returns true if the record is updated before the time, the record of the unknown time is kept
+func updatedBefore(createTime, updateTime string, before time.Time) bool {
+	value := updateTime
+	if value == "" {
+		value = createTime
+	}
+	updated, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return false
+	}
+	return updated.Before(before)
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
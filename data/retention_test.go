@@ -0,0 +1,105 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		value   string
+		expect  time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"-1d", 0, true},
+		{"d", 0, true},
+		{"month", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAge(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.expect {
+			t.Errorf("ParseAge(%s) = %v, %v, expect %v, wantErr %v", tt.value, got, err, tt.expect, tt.wantErr)
+		}
+	}
+}
+
+func TestParseStatuses(t *testing.T) {
+	tests := []struct {
+		value   string
+		expect  []string
+		wantErr bool
+	}{
+		{"Destroyed,Error,Revoked", []string{"Destroyed", "Error", "Revoked"}, false},
+		{"destroyed, expired", []string{"Destroyed", "Expired"}, false},
+		{"Destroyed,Success", nil, true},
+		{"created,Destroyed", nil, true},
+		{"running", nil, true},
+		{",", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseStatuses(tt.value)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && !reflect.DeepEqual(got, tt.expect)) {
+			t.Errorf("ParseStatuses(%s) = %v, %v, expect %v, wantErr %v", tt.value, got, err, tt.expect, tt.wantErr)
+		}
+	}
+}
+
+func TestPrune_vacuum(t *testing.T) {
+	src := newTestSource(t)
+	old := time.Now().Add(-time.Hour).Format(time.RFC3339Nano)
+	policy := &RetentionPolicy{OlderThan: time.Minute, Statuses: DefaultPruneStatuses}
+	result, err := Prune(src, policy, time.Now())
+	if err != nil || result.Vacuumed {
+		t.Fatalf("unexpected result of pruning nothing: %+v, %v", result, err)
+	}
+	if err := src.InsertExperimentModel(&ExperimentModel{Uid: "e1", Command: "cpu", Status: "Destroyed", UpdateTime: old}); err != nil {
+		t.Fatalf("insert experiment failed, %v", err)
+	}
+	if err := src.InsertExperimentDeadline("e1", time.Now()); err != nil {
+		t.Fatalf("insert deadline failed, %v", err)
+	}
+	result, err = Prune(src, policy, time.Now())
+	if err != nil || !result.Vacuumed || len(result.Experiments) != 1 {
+		t.Errorf("unexpected prune result: %+v, %v", result, err)
+	}
+	if models, _ := src.QueryOverdueExperimentModels(time.Now().Add(time.Hour)); len(models) != 0 {
+		t.Errorf("the deadline of the pruned experiment is left")
+	}
+}
+
+func TestPrune_protectedStatuses(t *testing.T) {
+	src := newTestSource(t)
+	old := time.Now().Add(-time.Hour).Format(time.RFC3339Nano)
+	if err := src.InsertExperimentModel(&ExperimentModel{Uid: "e1", Command: "cpu", Status: "Created", UpdateTime: old}); err != nil {
+		t.Fatalf("insert experiment failed, %v", err)
+	}
+	for _, status := range protectedStatuses {
+		policy := &RetentionPolicy{OlderThan: time.Minute, Statuses: []string{status}}
+		if result, err := Prune(src, policy, time.Now()); err == nil {
+			t.Errorf("the %s records are pruned: %+v", status, result)
+		}
+	}
+	if model, _ := src.QueryExperimentModelByUid("e1"); model == nil {
+		t.Errorf("the Created experiment is pruned")
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"os"
 	"path"
 	"sync"
+	"time"
 	"unicode"
 
 	_ "github.com/glebarez/sqlite"
@@ -36,6 +37,8 @@ const dataFile = "chaosblade.dat"
 type SourceI interface {
 	ExperimentSource
 	PreparationSource
+	AuditJournal
+	EventOutbox
 }
 
 type Source struct {
@@ -47,26 +50,74 @@ var (
 	once   = sync.Once{}
 )
 
+// GetSource returns the source selected by the CHAOSBLADE_DATASOURCE environment variable,
+// the sqlite data file is used by default
 func GetSource() SourceI {
 	once.Do(func() {
-		src := &Source{
-			DB: getConnection(),
+		src, err := OpenSource(os.Getenv(DataSourceEnv))
+		if err != nil {
+			log.Fatalf(context.Background(), "%s", err.Error())
 		}
-		src.init()
+		src = NewAuditedSource(src)
+		applyRetentionPolicy(src)
 		source = src
 	})
 	return source
 }
 
-const tableExistsDQL = `SELECT count(*) AS c
-	FROM sqlite_master
-	WHERE type = "table"
-	AND name = ?
-`
+// busyTimeout is the milliseconds to wait for the lock held by other blade processes, such as the async creating
+// processes and the timeout destroying processes
+const busyTimeout = 10000
+
+// applyRetentionPolicy prunes the records by the policy configured by the CHAOSBLADE_RETENTION environment variables,
+// the failure does not block the command
+func applyRetentionPolicy(src SourceI) {
+	policy, err := retentionPolicyFromEnv()
+	if err != nil {
+		log.Warnf(context.Background(), "%s", err.Error())
+		return
+	}
+	if policy == nil {
+		return
+	}
+	result, err := Prune(src, policy, time.Now())
+	if err != nil {
+		log.Warnf(context.Background(), "apply the retention policy failed, %s", err.Error())
+		return
+	}
+	if len(result.Experiments)+len(result.Preparations) > 0 {
+		log.Infof(context.Background(), "pruned %d experiments and %d preparations by the retention policy",
+			len(result.Experiments), len(result.Preparations))
+	}
+}
 
-func (s *Source) init() {
-	s.CheckAndInitExperimentTable()
-	s.CheckAndInitPreTable()
+// NewSource opens the sqlite data file without migrating the schema, it is used to inspect or migrate the data file.
+// The WAL journal mode lets the readers run with the writer, and the transactions take the write lock
+// when beginning, so the busy timeout works instead of failing to upgrade the read lock.
+func NewSource(dataFile string) (*Source, error) {
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_txlock=immediate", dataFile, busyTimeout)
+	database, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open data file err, %s", err)
+	}
+	return &Source{DB: database}, nil
+}
+
+// openSQLiteSource opens the data file and migrates it to the latest schema,
+// the data file created by a newer binary is refused
+func openSQLiteSource(dataFile string) (SourceI, error) {
+	if dataFile == "" {
+		dataFile = GetDataFilePath()
+	}
+	src, err := NewSource(dataFile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := src.Migrate(); err != nil {
+		src.Close()
+		return nil, err
+	}
+	return src, nil
 }
 
 // GetDataFilePath gets the data file path.
@@ -109,22 +160,18 @@ func GetDataFilePath() string {
 	}
 }
 
-func getConnection() *sql.DB {
-	database, err := sql.Open("sqlite", GetDataFilePath())
-	if err != nil {
-		log.Fatalf(context.Background(), "open data file err, %s", err.Error())
-		// log.Error(err, "open data file err")
-		// os.Exit(1)
-	}
-	return database
-}
-
 func (s *Source) Close() {
 	if s.DB != nil {
 		s.DB.Close()
 	}
 }
 
+// Vacuum rebuilds the data file to reclaim the space of the deleted records
+func (s *Source) Vacuum() error {
+	_, err := s.DB.Exec("VACUUM")
+	return err
+}
+
 // GetUserVersion returns the user_version value
 func (s *Source) GetUserVersion() (int, error) {
 	userVerRows, err := s.DB.Query("PRAGMA user_version")
@@ -139,20 +186,23 @@ func (s *Source) GetUserVersion() (int, error) {
 	return userVersion, nil
 }
 
-// UpdateUserVersion to the latest
-func (s *Source) UpdateUserVersion(version int) error {
-	_, err := s.DB.Exec(fmt.Sprintf("PRAGMA user_version=%d", version))
-	return err
-}
-
 func UpperFirst(str string) string {
 	return string(unicode.ToUpper(rune(str[0]))) + str[1:]
 }
 
 // ColumnExists checks if a column exists in the specified table
 func (s *Source) ColumnExists(tableName, columnName string) (bool, error) {
+	return columnExists(s.DB, tableName, columnName)
+}
+
+// querier is implemented by *sql.DB and *sql.Tx
+type querier interface {
+	Prepare(query string) (*sql.Stmt, error)
+}
+
+func columnExists(q querier, tableName, columnName string) (bool, error) {
 	query := `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`
-	stmt, err := s.DB.Prepare(query)
+	stmt, err := q.Prepare(query)
 	if err != nil {
 		return false, fmt.Errorf("prepare column exists query err, %s", err)
 	}
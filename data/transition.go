@@ -0,0 +1,162 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// transitionJournal builds the audit entry and the event deliveries of the transition of the uid by the status
+// before it and the record after it, the records are nil if the record is deleted
+type transitionJournal func(uid, previous string, experiment *ExperimentModel, preparation *PreparationRecord) (
+	*AuditEntry, []*EventDelivery, error)
+
+// transition is an insertion, status change or deletion of an experiment or preparation record
+type transition struct {
+	// kind is AuditKindExperiment or AuditKindPreparation, which is also the table name
+	kind string
+	uid  string
+	// experiment or preparation is the inserted record
+	experiment  *ExperimentModel
+	preparation *PreparationRecord
+	deleted     bool
+	status      string
+	errMsg      string
+	// expected are the statuses which the record must be in, the record must exist if it is not nil.
+	// The unknown record is not changed if it is nil.
+	expected []string
+	journal  transitionJournal
+}
+
+func (t *transition) inserted() bool {
+	return t.experiment != nil || t.preparation != nil
+}
+
+// journaledSource writes the transitions together with their audit entries and event deliveries in one transaction,
+// so a transition is never committed without its journal, and it fails if the journal can not be written
+type journaledSource interface {
+	applyTransition(t *transition) error
+
+	// pruneWithJournal deletes the records like the PruneExperimentModels and PrunePreparationRecords,
+	// the nil journal writes nothing
+	pruneWithJournal(kind string, before time.Time, statuses []string, journal transitionJournal) ([]string, error)
+}
+
+// applyTransition writes the transition, its audit entry and event deliveries in one transaction
+func (s *Source) applyTransition(t *transition) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var previous string
+	err = tx.QueryRow(fmt.Sprintf(`SELECT status FROM %s WHERE uid = ?`, t.kind), t.uid).Scan(&previous)
+	found := err == nil
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if t.deleted && t.kind == AuditKindExperiment {
+		if _, err := tx.Exec(`DELETE FROM experiment_deadline WHERE uid = ?`, t.uid); err != nil {
+			return err
+		}
+	}
+	switch {
+	case t.experiment != nil:
+		previous, err = "", insertExperimentModel(tx, t.experiment)
+	case t.preparation != nil:
+		previous, err = "", insertPreparationRecord(tx, t.preparation)
+	case !found && t.expected != nil:
+		return &StatusConflictError{Uid: t.uid, Expected: t.expected}
+	case !found:
+		return tx.Commit()
+	case t.deleted:
+		_, err = tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE uid = ?`, t.kind), t.uid)
+	default:
+		if err := checkExpectedStatus(t.uid, previous, t.expected); err != nil {
+			return err
+		}
+		_, err = tx.Exec(fmt.Sprintf(`UPDATE %s SET status = ?, error = ?, update_time = ? WHERE uid = ?`, t.kind),
+			t.status, t.errMsg, now(), t.uid)
+	}
+	if err != nil {
+		return err
+	}
+	var experiment *ExperimentModel
+	var preparation *PreparationRecord
+	if !t.deleted && t.kind == AuditKindExperiment {
+		experiment, err = queryExperimentModelByUid(tx, t.uid)
+	} else if !t.deleted {
+		preparation, err = queryPreparationByUid(tx, t.uid)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeJournal(tx, t.journal, t.uid, previous, experiment, preparation); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Source) pruneWithJournal(kind string, before time.Time, statuses []string, journal transitionJournal) (
+	[]string, error,
+) {
+	if err := checkPruneStatuses(statuses); err != nil {
+		return nil, err
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	uids, err := queryPrunableUids(tx, kind, before, statuses)
+	if err != nil {
+		return nil, err
+	}
+	for _, uid := range uids {
+		if _, err := tx.Exec(fmt.Sprintf(`DELETE FROM %s WHERE uid = ?`, kind), uid); err != nil {
+			return nil, err
+		}
+		if kind == AuditKindExperiment {
+			if _, err := tx.Exec(`DELETE FROM experiment_deadline WHERE uid = ?`, uid); err != nil {
+				return nil, err
+			}
+		}
+		if err := writeJournal(tx, journal, uid, "", nil, nil); err != nil {
+			return nil, err
+		}
+	}
+	return uids, tx.Commit()
+}
+
+// writeJournal appends the audit entry and inserts the event deliveries of the transition in the transaction
+func writeJournal(tx *sql.Tx, journal transitionJournal, uid, previous string, experiment *ExperimentModel,
+	preparation *PreparationRecord,
+) error {
+	if journal == nil {
+		return nil
+	}
+	entry, deliveries, err := journal(uid, previous, experiment, preparation)
+	if err != nil {
+		return err
+	}
+	if err := appendAuditEntry(tx, entry); err != nil {
+		return err
+	}
+	return insertEventDeliveries(tx, deliveries)
+}
@@ -20,9 +20,12 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/shirou/gopsutil/process"
+
 	"github.com/chaosblade-io/chaosblade-spec-go/channel"
 	"github.com/chaosblade-io/chaosblade-spec-go/log"
 	"github.com/chaosblade-io/chaosblade-spec-go/spec"
@@ -85,6 +88,36 @@ func processExists(port string) bool {
 	return false
 }
 
+// ProxyPorts returns the ports of the running proxy processes
+func ProxyPorts(ctx context.Context) ([]string, error) {
+	pids, err := channel.NewLocalChannel().GetPidsByProcessName(ApplicationName, ctx)
+	if err != nil {
+		return nil, err
+	}
+	ports := make([]string, 0)
+	for _, pid := range pids {
+		id, err := strconv.Atoi(pid)
+		if err != nil {
+			continue
+		}
+		proc, err := process.NewProcess(int32(id))
+		if err != nil {
+			continue
+		}
+		args, err := proc.CmdlineSlice()
+		if err != nil {
+			continue
+		}
+		for idx, arg := range args {
+			if arg == "--port" && idx+1 < len(args) {
+				ports = append(ports, args[idx+1])
+				break
+			}
+		}
+	}
+	return ports, nil
+}
+
 func startProxy(ctx context.Context, port, ip string) *spec.Response {
 	args := fmt.Sprintf("--port %s", port)
 	if ip != "" {
@@ -93,6 +126,11 @@ func startProxy(ctx context.Context, port, ip string) *spec.Response {
 	return channel.NewLocalChannel().Run(ctx, cplusBinPath, args)
 }
 
+// Check checks the proxy service listening on the port is running
+func Check(ctx context.Context, port string) *spec.Response {
+	return postCheck(ctx, port)
+}
+
 func postCheck(ctx context.Context, port string) *spec.Response {
 	url := getProxyServiceUrl(port, "status")
 	result, err, _ := util.Curl(ctx, url)
@@ -79,6 +79,18 @@ func (e *Executor) Exec(uid string, ctx context.Context, model *spec.ExpModel) *
 	return spec.ResponseFailWithFlags(spec.HttpExecFailed, url, result)
 }
 
+// DryRun returns the url of the proxy which would be requested, without requesting it
+func (e *Executor) DryRun(uid string, ctx context.Context, model *spec.ExpModel) *spec.Response {
+	port, resp := e.getPortFromDB(ctx, uid, model)
+	if resp != nil {
+		return resp
+	}
+	if _, ok := spec.IsDestroy(ctx); ok {
+		return spec.ReturnSuccess(e.destroyUrl(port, uid))
+	}
+	return spec.ReturnSuccess(e.createUrl(port, uid, model))
+}
+
 func (e *Executor) createUrl(port, suid string, model *spec.ExpModel) string {
 	url := fmt.Sprintf("http://%s:%s/create?target=%s&suid=%s&action=%s",
 		"127.0.0.1", port, model.Target, suid, model.ActionName)
@@ -101,11 +113,9 @@ func (e *Executor) destroyUrl(port, uid string) string {
 	return url
 }
 
-var db = data.GetSource()
-
 func (e *Executor) getPortFromDB(ctx context.Context, uid string, model *spec.ExpModel) (string, *spec.Response) {
 	port := model.ActionFlags["port"]
-	record, err := db.QueryRunningPreByTypeAndProcess("cplus", port, "")
+	record, err := data.GetSource().QueryRunningPreByTypeAndProcess("cplus", port, "")
 	if err != nil {
 		log.Errorf(ctx, "%s", spec.DatabaseError.Sprintf("query", err))
 		return "", spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
@@ -35,6 +35,9 @@ import (
 
 const DefaultUri = "sandbox/" + DefaultNamespace + "/module/http/chaosblade"
 
+// unknownPort is rendered in the url by DryRun if the java agent is not attached
+const unknownPort = "{port}"
+
 // Executor for jvm experiment
 type Executor struct {
 	Uri     string
@@ -176,6 +179,52 @@ func (e *Executor) Exec(uid string, ctx context.Context, model *spec.ExpModel) *
 	return spec.ResponseFailWithFlags(spec.HttpExecFailed, url, result)
 }
 
+// Request is the http request to the sandbox module which is rendered by DryRun
+type Request struct {
+	// Attach is true if the java agent is not attached to the process yet, and the port is unknown until attached
+	Attach bool            `json:"attach,omitempty"`
+	Method string          `json:"method"`
+	Url    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// DryRun returns the sandbox request which would be sent, without attaching the java agent or sending it
+func (e *Executor) DryRun(uid string, ctx context.Context, model *spec.ExpModel) *spec.Response {
+	processName := model.ActionFlags["process"]
+	processId := model.ActionFlags["pid"]
+	record, err := e.getRecordFromDB(ctx, processName, processId)
+	if err != nil {
+		return spec.ResponseFailWithFlags(spec.DatabaseError, "get",
+			fmt.Sprintf("where by processName:%s or pid%s", processName, processId), err.Error())
+	}
+	request := &Request{Method: "GET"}
+	port := unknownPort
+	if record != nil && record.Port != "" && model.ActionFlags["refresh"] != "true" {
+		if exists, _ := cl.ProcessExists(record.Pid); exists {
+			port = record.Port
+		}
+	}
+	suid, isDestroy := spec.IsDestroy(ctx)
+	if isDestroy {
+		if suid == spec.UnknownUid {
+			request.Url = e.sandboxUrl(port, e.getDestroyRequestPathWithoutUid(model.Target, model.ActionName))
+		} else {
+			request.Url = e.sandboxUrl(port, e.getDestroyRequestPathWithUid(uid))
+		}
+		return spec.ReturnSuccess(request)
+	}
+	if processName == "" && processId == "" && port == unknownPort {
+		return spec.ResponseFailWithFlags(spec.ParameterLess, "process|pid")
+	}
+	request.Attach = port == unknownPort
+	url, body, resp := e.createUrl(context.WithValue(ctx, spec.Uid, uid), port, model)
+	if resp != nil {
+		return resp
+	}
+	request.Method, request.Url, request.Body = "POST", url, body
+	return spec.ReturnSuccess(request)
+}
+
 func (e *Executor) createUrl(ctx context.Context, port string, model *spec.ExpModel) (string, []byte, *spec.Response) {
 	url := e.sandboxUrl(port, "create")
 	bodyMap := make(map[string]string, 0)
@@ -220,7 +269,7 @@ func (e *Executor) getStatusRequestPath(uid string) string {
 
 func (e *Executor) QueryStatus(ctx context.Context) *spec.Response {
 	uid := ctx.Value(spec.Uid).(string)
-	experimentModel, err := db.QueryExperimentModelByUid(uid)
+	experimentModel, err := data.GetSource().QueryExperimentModelByUid(uid)
 	if err != nil {
 		log.Errorf(ctx, "%s", spec.DatabaseError.Sprintf("query", err))
 		return spec.ResponseFailWithFlags(spec.DatabaseError, "query", err)
@@ -260,8 +309,6 @@ func (e *Executor) QueryStatus(ctx context.Context) *spec.Response {
 	return &resp
 }
 
-var db = data.GetSource()
-
 func (e *Executor) getRecordFromDB(ctx context.Context, processName, processId string) (*data.PreparationRecord, error) {
 	if processName != "" || processId != "" {
 		pid, response := CheckFlagValues(ctx, processName, processId)
@@ -270,7 +317,7 @@ func (e *Executor) getRecordFromDB(ctx context.Context, processName, processId s
 		}
 		processId = pid
 	}
-	record, err := db.QueryRunningPreByTypeAndProcess("jvm", processName, processId)
+	record, err := data.GetSource().QueryRunningPreByTypeAndProcess("jvm", processName, processId)
 	if err != nil {
 		return nil, err
 	}
@@ -364,7 +411,7 @@ func Prepare(ctx context.Context, processName, processId, javaHome string) (resp
 	if !response.Success {
 		return response, port
 	}
-	record, err := db.QueryRunningPreByTypeAndProcess("jvm", processName, processId)
+	record, err := data.GetSource().QueryRunningPreByTypeAndProcess("jvm", processName, processId)
 	if record == nil || err != nil || record.Uid == "" {
 		// get port from local port
 		port, err = getAndCacheSandboxPort()
@@ -389,7 +436,7 @@ func Prepare(ctx context.Context, processName, processId, javaHome string) (resp
 			response, username, userid = Attach(ctx, port, "", processId)
 			if response.Success {
 				// update port
-				err := db.UpdatePreparationPortByUid(record.Uid, port)
+				err := data.GetSource().UpdatePreparationPortByUid(record.Uid, port)
 				if err != nil {
 					log.Warnf(ctx, "update preparation port failed, %v", err)
 				}
@@ -398,7 +445,7 @@ func Prepare(ctx context.Context, processName, processId, javaHome string) (resp
 	}
 	if record.Pid != processId {
 		// update pid
-		db.UpdatePreparationPidByUid(record.Uid, processId)
+		data.GetSource().UpdatePreparationPidByUid(record.Uid, processId)
 	}
 	handlePrepareResponse(ctx, record.Uid, response)
 	return response, port
@@ -461,7 +508,7 @@ func insertPrepareRecord(prepareType string, processName, port, processId string
 		CreateTime:  time.Now().Format(time.RFC3339Nano),
 		UpdateTime:  time.Now().Format(time.RFC3339Nano),
 	}
-	err = db.InsertPreparationRecord(record)
+	err = data.GetSource().InsertPreparationRecord(record)
 	if err != nil {
 		return nil, err
 	}
@@ -471,10 +518,10 @@ func insertPrepareRecord(prepareType string, processName, port, processId string
 func handlePrepareResponse(ctx context.Context, uid string, response *spec.Response) {
 	response.Result = uid
 	if !response.Success {
-		db.UpdatePreparationRecordByUid(uid, "Error", response.Err)
+		data.GetSource().UpdatePreparationRecordByUid(uid, "Error", response.Err)
 		return
 	}
-	err := db.UpdatePreparationRecordByUid(uid, "Running", "")
+	err := data.GetSource().UpdatePreparationRecordByUid(uid, "Running", "")
 	if err != nil {
 		log.Warnf(ctx, "update preparation record error: %s", err.Error())
 	}
@@ -63,6 +63,11 @@ func Attach(ctx context.Context, port, javaHome, pid string) (*spec.Response, st
 	return check(ctx, port), username, userid
 }
 
+// CheckSandbox checks the chaosblade module of the sandbox listening on the port is active
+func CheckSandbox(ctx context.Context, port string) *spec.Response {
+	return check(ctx, port)
+}
+
 // curl -s http://localhost:$2/sandbox/default/module/http/chaosblade/status 2>&1
 func check(ctx context.Context, port string) *spec.Response {
 	url := getSandboxUrl(port, "chaosblade/status", "")
@@ -332,6 +337,67 @@ func getSandboxUrl(port, uri, param string) string {
 		port, DefaultNamespace, uri, param)
 }
 
+// SandboxPorts returns the ports of the chaosblade namespace in the sandbox token files of the users in /etc/passwd
+// and the current user, the sandboxes may be shut down already
+func SandboxPorts() []string {
+	usernames := make([]string, 0)
+	if current, err := osuser.Current(); err == nil {
+		usernames = append(usernames, current.Username)
+	}
+	if bytes, err := os.ReadFile("/etc/passwd"); err == nil {
+		for _, line := range strings.Split(string(bytes), "\n") {
+			if name := strings.SplitN(line, ":", 2)[0]; name != "" && !strings.HasPrefix(name, "#") {
+				usernames = append(usernames, name)
+			}
+		}
+	}
+	ports := make([]string, 0)
+	files := make(map[string]bool)
+	for _, username := range usernames {
+		tokenFile := getSandboxTokenFile(username)
+		if files[tokenFile] {
+			continue
+		}
+		files[tokenFile] = true
+		bytes, err := os.ReadFile(tokenFile)
+		if err != nil {
+			continue
+		}
+		for _, port := range parseSandboxTokenPorts(string(bytes)) {
+			if !containsPort(ports, port) {
+				ports = append(ports, port)
+			}
+		}
+	}
+	return ports
+}
+
+// parseSandboxTokenPorts returns the ports of the chaosblade namespace lines, the line is namespace;token;ip;port
+func parseSandboxTokenPorts(content string) []string {
+	ports := make([]string, 0)
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ";")
+		if len(fields) < 4 || fields[0] != DefaultNamespace {
+			continue
+		}
+		port := strings.TrimSpace(fields[3])
+		if _, err := strconv.Atoi(port); err != nil || containsPort(ports, port) {
+			continue
+		}
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+func containsPort(ports []string, port string) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
 func getSandboxTokenFile(username string) string {
 	userHome := util.GetSpecifyingUserHome(username)
 	return path.Join(userHome, ".sandbox.token")
@@ -120,3 +120,16 @@ func Test_getJavaBinAndJavaHome(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseSandboxTokenPorts(t *testing.T) {
+	content := `default;8d0b2a;127.0.0.1;51001
+chaosblade;4a3e1c;127.0.0.1;51002
+chaosblade;4a3e1d;127.0.0.1;51002
+chaosblade;broken
+chaosblade;5b7f9e;127.0.0.1;51003
+`
+	ports := parseSandboxTokenPorts(content)
+	if fmt.Sprint(ports) != "[51002 51003]" {
+		t.Errorf("parseSandboxTokenPorts() = %v, expected [51002 51003]", ports)
+	}
+}
@@ -192,6 +192,33 @@ func (e *Executor) Exec(uid string, ctx context.Context, expModel *spec.ExpModel
 	return response
 }
 
+// DryRun returns the ChaosBlade resource which would be created or deleted, without connecting to the cluster
+func (e *Executor) DryRun(uid string, ctx context.Context, expModel *spec.ExpModel) *spec.Response {
+	if suid, ok := spec.IsDestroy(ctx); ok {
+		if suid == spec.UnknownUid {
+			return spec.ResponseFailWithFlags(spec.ParameterInvalid, "suid", spec.UnknownUid,
+				"not support destroy k8s experiments without uid")
+		}
+		return spec.ReturnSuccess(v1alpha1.ChaosBlade{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "chaosblade.io/v1alpha1",
+				Kind:       "ChaosBlade",
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: suid},
+		})
+	}
+	flags := make(map[string]string, len(expModel.ActionFlags)+1)
+	for name, value := range expModel.ActionFlags {
+		flags[name] = value
+	}
+	if expModel.ActionProcessHang {
+		flags["cgroup-root"] = "/host-sys/fs/cgroup"
+	}
+	model := *expModel
+	model.ActionFlags = flags
+	return spec.ReturnSuccess(convertExpModelToChaosBladeObject(uid, &model))
+}
+
 func (*Executor) destroy(ctx context.Context, cli client.Client, config, proxyURL, token string) (*spec.Response, bool) {
 	err := delete(ctx, cli)
 	if err != nil {
@@ -52,5 +52,14 @@ func (e *ComposeExecutorForK8s) Exec(uid string, ctx context.Context, model *spe
 	}
 }
 
+// DryRun renders the experiments of the kubernetes client, the ssh channel can not be rendered
+func (e *ComposeExecutorForK8s) DryRun(uid string, ctx context.Context, model *spec.ExpModel) *spec.Response {
+	if strings.ToLower(model.ActionFlags[exec.ChannelFlag.Name]) == e.sshExecutor.Name() {
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, exec.ChannelFlag.Name, e.sshExecutor.Name(),
+			"the experiments of the ssh channel can not be rendered")
+	}
+	return e.clientExecutors.(*Executor).DryRun(uid, ctx, model)
+}
+
 func (*ComposeExecutorForK8s) SetChannel(channel spec.Channel) {
 }
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	os_exec "os/exec"
 	"path"
+	"sort"
 	"syscall"
 
 	"github.com/chaosblade-io/chaosblade-exec-middleware/exec"
@@ -45,23 +46,8 @@ func (e *Executor) Exec(uid string, ctx context.Context, model *spec.ExpModel) *
 		return sshExecutor.Exec(uid, ctx, model)
 	}
 
-	var mode string
-	var argsArray []string
-
 	_, isDestroy := spec.IsDestroy(ctx)
-	if isDestroy {
-		mode = spec.Destroy
-	} else {
-		mode = spec.Create
-	}
-
-	argsArray = append(argsArray, mode, model.Target, model.ActionName, fmt.Sprintf("--uid=%s", uid))
-	for k, v := range model.ActionFlags {
-		if v == "" || k == "timeout" {
-			continue
-		}
-		argsArray = append(argsArray, fmt.Sprintf("--%s=%s", k, v))
-	}
+	argsArray := commandArgs(ctx, uid, model)
 
 	chaosOsBin := path.Join(util.GetProgramPath(), "bin", spec.ChaosMiddlewareBin)
 	command := os_exec.CommandContext(ctx, chaosOsBin, argsArray...)
@@ -87,3 +73,33 @@ func (e *Executor) Exec(uid string, ctx context.Context, model *spec.ExpModel) *
 
 func (*Executor) SetChannel(channel spec.Channel) {
 }
+
+// DryRun returns the chaos_middleware command line which would be executed, without executing it
+func (e *Executor) DryRun(uid string, ctx context.Context, model *spec.ExpModel) *spec.Response {
+	if model.ActionFlags[exec.ChannelFlag.Name] == "ssh" {
+		return spec.ResponseFailWithFlags(spec.ParameterIllegal, exec.ChannelFlag.Name, "ssh",
+			"the experiments of the ssh channel can not be rendered")
+	}
+	chaosOsBin := path.Join(util.GetProgramPath(), "bin", spec.ChaosMiddlewareBin)
+	return spec.ReturnSuccess(append([]string{chaosOsBin}, commandArgs(ctx, uid, model)...))
+}
+
+// commandArgs returns the arguments of the chaos_middleware command, the flags are sorted by name
+func commandArgs(ctx context.Context, uid string, model *spec.ExpModel) []string {
+	mode := spec.Create
+	if _, isDestroy := spec.IsDestroy(ctx); isDestroy {
+		mode = spec.Destroy
+	}
+	argsArray := []string{mode, model.Target, model.ActionName, fmt.Sprintf("--uid=%s", uid)}
+	names := make([]string, 0, len(model.ActionFlags))
+	for k := range model.ActionFlags {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		if v := model.ActionFlags[k]; v != "" && k != "timeout" {
+			argsArray = append(argsArray, fmt.Sprintf("--%s=%s", k, v))
+		}
+	}
+	return argsArray
+}
@@ -24,10 +24,12 @@ require (
         github.com/chaosblade-io/chaosblade-spec-go v1.8.0
         github.com/glebarez/sqlite v1.11.0
         github.com/olekukonko/tablewriter v0.0.5-0.20201029120751-42e21c7531a3
+        github.com/prometheus/client_golang v1.22.0
         github.com/shirou/gopsutil v3.21.11+incompatible
         github.com/spf13/cobra v1.9.1
         github.com/spf13/pflag v1.0.6
         golang.org/x/term v0.37.0
+        gopkg.in/yaml.v2 v2.4.0
         k8s.io/apimachinery v0.34.1
         k8s.io/client-go v0.34.1
         k8s.io/klog/v2 v2.130.1
@@ -41,6 +43,8 @@ require (
         github.com/Microsoft/go-winio v0.6.2 // indirect
         github.com/Microsoft/hcsshim v0.13.0 // indirect
         github.com/asaskevich/govalidator v0.0.0-20210307081110-f21760c49a8d // indirect
+        github.com/beorn7/perks v1.0.1 // indirect
+        github.com/cespare/xxhash/v2 v2.3.0 // indirect
         github.com/cilium/ebpf v0.17.3 // indirect
         github.com/containerd/cgroups v1.1.0 // indirect
         github.com/containerd/cgroups/v3 v3.0.5 // indirect
@@ -112,6 +116,9 @@ require (
         github.com/opencontainers/selinux v1.13.0 // indirect
         github.com/pkg/errors v0.9.1 // indirect
         github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+        github.com/prometheus/client_model v0.6.1 // indirect
+        github.com/prometheus/common v0.62.0 // indirect
+        github.com/prometheus/procfs v0.15.1 // indirect
         github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
         github.com/sirupsen/logrus v1.9.3 // indirect
         github.com/tklauser/go-sysconf v0.3.12 // indirect
@@ -140,7 +147,6 @@ require (
         google.golang.org/protobuf v1.36.10 // indirect
         gopkg.in/inf.v0 v0.9.1 // indirect
         gopkg.in/natefinch/lumberjack.v2 v2.0.0 // indirect
-        gopkg.in/yaml.v2 v2.4.0 // indirect
         gopkg.in/yaml.v3 v3.0.1 // indirect
         gorm.io/gorm v1.25.7 // indirect
         gotest.tools/v3 v3.5.2 // indirect